@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/refsync"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== ADMIN ====================
+
+// syncObjects are the reference data sync-metadata compares, in order
+var syncObjects = []string{"departments", "topics", "slas", "teams", "statuses"}
+
+// syncResult is the outcome of sync-metadata
+type syncResult struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Changes []refsync.Change `json:"changes"`
+}
+
+func (app *App) adminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Administer osTicket instances",
+	}
+
+	// admin sync-metadata
+	syncCmd := &cobra.Command{
+		Use:   "sync-metadata",
+		Short: "Compare reference data between two profiles' instances",
+		Long: `Compare the reference data of the instance of profile --from with that of
+--to and list what --to needs to match: objects to add, to update and to
+remove. Objects are matched by name, since IDs differ between instances.
+Use "default" for the default profile.
+
+--objects picks what to compare: ` + strings.Join(syncObjects, ", ") + `.
+
+The osTicket API plugin has no calls that create, change or delete
+reference data, so the changes cannot be applied through the API: without
+--dry-run the command lists them and fails, to be made in the admin panel
+of --to. Run it again afterwards to check the instances match.`,
+		Example: `  osticket admin sync-metadata --from staging --to prod --objects topics,slas,departments --dry-run
+  osticket admin sync-metadata --from staging --to prod --dry-run -o json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			objects, _ := cmd.Flags().GetStringSlice("objects")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			for _, o := range objects {
+				if !containsString(syncObjects, o) {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --objects value %q: use %s", o, strings.Join(syncObjects, ", ")))
+					exit(exitValidation)
+				}
+			}
+			if from == to {
+				fmt.Fprintln(app.Err, red("Error:"), "--from and --to must be different profiles")
+				exit(exitValidation)
+			}
+
+			ctx := cmd.Context()
+			fromClient := app.profileClient(ctx, from)
+			toClient := app.profileClient(ctx, to)
+			if fromURL := baseURL(fromClient); fromURL == baseURL(toClient) {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("profiles %s and %s use the same URL %s (set by --url or %s?)", from, to, fromURL, config.EnvBaseURL))
+				exit(exitValidation)
+			}
+
+			result := syncResult{From: from, To: to, Changes: []refsync.Change{}}
+			for _, object := range syncObjects {
+				if !containsString(objects, object) {
+					continue
+				}
+				source, err := syncItems(ctx, fromClient, object)
+				if err != nil {
+					fmt.Fprintln(app.Err, red(fmt.Sprintf("Error loading %s of %s:", object, from)), err)
+					exit(exitCode(err))
+				}
+				target, err := syncItems(ctx, toClient, object)
+				if err != nil {
+					fmt.Fprintln(app.Err, red(fmt.Sprintf("Error loading %s of %s:", object, to)), err)
+					exit(exitCode(err))
+				}
+				result.Changes = append(result.Changes, refsync.Diff(object, source, target)...)
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: result,
+				Rows:  result.Changes,
+				Table: func(w io.Writer) { displaySyncChanges(w, result) },
+			})
+			if !dryRun && len(result.Changes) > 0 {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("cannot apply: the osTicket API plugin has no calls that change reference data; make the changes above in the admin panel of %s", to))
+				exit(1)
+			}
+		},
+	}
+	syncCmd.Flags().String("from", "", "Profile of the instance to copy from")
+	syncCmd.Flags().String("to", "", "Profile of the instance to bring in line")
+	syncCmd.Flags().StringSlice("objects", []string{"departments", "topics", "slas"}, "Reference data to compare: "+strings.Join(syncObjects, ", "))
+	syncCmd.Flags().Bool("dry-run", false, "Only list the differences")
+	syncCmd.MarkFlagRequired("from")
+	syncCmd.MarkFlagRequired("to")
+	cmd.AddCommand(syncCmd)
+
+	return cmd
+}
+
+// profileClient builds the API client of a profile ("default" for the
+// default profile), leaving the active profile as it was
+func (app *App) profileClient(ctx context.Context, profile string) osticket.OSTicketAPI {
+	active := config.GetProfile()
+	defer config.SetProfile(active)
+	name := profile
+	if name == "default" && !containsString(config.ListProfiles(), "default") {
+		name = ""
+	}
+	if err := config.SetProfile(name); err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitValidation)
+	}
+	return app.client(ctx)
+}
+
+// syncItems loads one kind of reference data for refsync
+func syncItems(ctx context.Context, client osticket.OSTicketAPI, object string) ([]refsync.Item, error) {
+	var items []refsync.Item
+	switch object {
+	case "departments":
+		data, err := client.GetDepartments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data.Departments {
+			items = append(items, refsync.Item{ID: d.ID, Name: d.Name})
+		}
+	case "topics":
+		data, err := client.GetTopics(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range data.Topics {
+			items = append(items, refsync.Item{ID: t.TopicID, Name: t.Topic})
+		}
+	case "slas":
+		data, err := client.GetSLAs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range data.SLA {
+			items = append(items, refsync.Item{ID: s.ID, Name: s.Name, Fields: map[string]string{
+				"grace_period": strconv.Itoa(s.GracePeriod),
+			}})
+		}
+	case "teams":
+		data, err := client.GetTeams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range data.Teams {
+			items = append(items, refsync.Item{ID: t.ID, Name: t.Name})
+		}
+	case "statuses":
+		data, err := client.GetStatuses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range data.Statuses {
+			items = append(items, refsync.Item{ID: s.ID, Name: s.Name, Fields: map[string]string{"state": s.State}})
+		}
+	}
+	return items, nil
+}
+
+// displaySyncChanges prints the changes --to needs as a table
+func displaySyncChanges(w io.Writer, result syncResult) {
+	if len(result.Changes) == 0 {
+		fmt.Fprintln(w, green(fmt.Sprintf("✓ %s matches %s", result.To, result.From)))
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Object", "Change", "Name", "Details"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+	for _, c := range result.Changes {
+		colors := make([]tablewriter.Colors, 4)
+		var details []string
+		switch c.Action {
+		case refsync.Add:
+			colors[1] = tablewriter.Colors{tablewriter.FgGreenColor}
+			details = append(details, fmt.Sprintf("ID %d in %s", c.FromID, result.From))
+		case refsync.Update:
+			colors[1] = tablewriter.Colors{tablewriter.FgYellowColor}
+			for _, f := range c.Fields {
+				details = append(details, fmt.Sprintf("%s: %s -> %s", f.Field, f.From, f.To))
+			}
+		case refsync.Remove:
+			colors[1] = tablewriter.Colors{tablewriter.FgRedColor}
+			details = append(details, fmt.Sprintf("ID %d in %s", c.ToID, result.To))
+		}
+		table.Rich([]string{c.Object, c.Action, c.Name, strings.Join(details, "; ")}, colors)
+	}
+	table.Render()
+	fmt.Fprintf(w, "\n%d change(s) to bring %s in line with %s\n", len(result.Changes), result.To, result.From)
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// ==================== TICKET AGE ====================
+
+// ageThresholds loads the configured age thresholds, warning and falling
+// back to the defaults when the config is invalid
+func (app *App) ageThresholds() *thresholds.Thresholds {
+	ages, err := config.GetAgeThresholds()
+	if err != nil {
+		app.logger().Warn(err.Error() + " (using default age thresholds)")
+		ages, _ = thresholds.New(nil)
+	}
+	return ages
+}
+
+// ticketAge returns a ticket's age and how urgent it is. ok is false when
+// the ticket has no parseable creation time.
+func ticketAge(ages *thresholds.Thresholds, t map[string]interface{}, now time.Time) (age time.Duration, level thresholds.Level, ok bool) {
+	age, ok = thresholds.Age(mapString(t, "created"), now)
+	if !ok {
+		return 0, thresholds.OK, false
+	}
+	level = ages.Classify(mapInt(t, "priority_id"), age, mapInt(t, "isoverdue") == 1)
+	return age, level, true
+}
+
+// ageColor returns the table color for an age level
+func ageColor(level thresholds.Level) tablewriter.Colors {
+	switch level {
+	case thresholds.Late:
+		return tablewriter.Colors{tablewriter.FgRedColor}
+	case thresholds.Warn:
+		return tablewriter.Colors{tablewriter.FgYellowColor}
+	}
+	return tablewriter.Colors{tablewriter.FgGreenColor}
+}
+
+// colorAge colors text for an age level
+func colorAge(level thresholds.Level, text string) string {
+	switch level {
+	case thresholds.Late:
+		return red(text)
+	case thresholds.Warn:
+		return yellow(text)
+	}
+	return green(text)
+}
+
+// ticketIDs returns the IDs of tickets, for --quiet
+func ticketIDs(tickets []map[string]interface{}) []string {
+	ids := make([]string, len(tickets))
+	for i, t := range tickets {
+		ids[i] = mapString(t, "ticket_id")
+	}
+	return ids
+}
+
+// priorityColor returns the table color for a priority, highlighting the
+// urgent ones
+func priorityColor(id int) tablewriter.Colors {
+	switch id {
+	case osticket.PriorityEmergency:
+		return tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+	case osticket.PriorityHigh:
+		return tablewriter.Colors{tablewriter.FgYellowColor}
+	}
+	return tablewriter.Colors{}
+}
+
+// sortByPriority orders tickets from the most to the least urgent
+// priority, keeping the server's order within a priority. Priorities
+// osTicket does not ship with come after Low, since their urgency is not
+// known, and tickets without a priority come last.
+func sortByPriority(tickets []map[string]interface{}) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return priorityRank(tickets[i]) > priorityRank(tickets[j])
+	})
+}
+
+// priorityRank orders a ticket's priority by urgency: shipped priorities
+// rank 2 (Low) to 5 (Emergency), other priorities 1 and none 0
+func priorityRank(t map[string]interface{}) int {
+	id := mapInt(t, "priority_id")
+	if _, ok := osticket.PriorityNames[id]; ok {
+		return id + 1
+	}
+	if id != 0 {
+		return 1
+	}
+	return 0
+}
+
+// nameOrID returns a ticket's enriched name field, or its ID when the
+// name is unknown
+func nameOrID(t map[string]interface{}, nameField, idField string) string {
+	if name := mapString(t, nameField); name != "" {
+		return name
+	}
+	if mapInt(t, idField) == 0 {
+		return ""
+	}
+	return mapString(t, idField)
+}
+
+// ticketStatus returns a ticket's enriched status name, which covers an
+// instance's custom statuses, or else the shipped status name
+func ticketStatus(t map[string]interface{}) string {
+	if name := mapString(t, "status_name"); name != "" {
+		return name
+	}
+	return statusName(mapInt(t, "status_id"))
+}
+
+// assignee describes who a ticket is assigned to: the agent, or else the
+// team
+func assignee(t map[string]interface{}) string {
+	if agent := nameOrID(t, "staff_name", "staff_id"); agent != "" {
+		return agent
+	}
+	if team := mapString(t, "team_name"); team != "" {
+		return "team " + team
+	}
+	if team := mapInt(t, "team_id"); team != 0 {
+		return fmt.Sprintf("team %d", team)
+	}
+	return ""
+}
+
+// displayTicketRows prints tickets as a table with their priority and
+// their age colored by the priority's threshold
+func (app *App) displayTicketRows(w io.Writer, tickets []map[string]interface{}) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Number", "Subject", "Status", "Priority", "Dept", "Assignee", "Age", "Created"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+
+	ages := app.ageThresholds()
+	now := time.Now()
+	for _, t := range tickets {
+		number := mapString(t, "number")
+		if number == "" {
+			number = mapString(t, "ticket_id")
+		}
+
+		ageText := ""
+		colors := make([]tablewriter.Colors, 8)
+		colors[3] = priorityColor(mapInt(t, "priority_id"))
+		if age, level, ok := ticketAge(ages, t, now); ok {
+			ageText = thresholds.Format(age)
+			colors[6] = ageColor(level)
+		}
+
+		table.Rich([]string{
+			number,
+			truncate(mapString(t, "subject"), 40),
+			ticketStatus(t),
+			mapString(t, "priority"),
+			nameOrID(t, "dept_name", "dept_id"),
+			assignee(t),
+			ageText,
+			mapString(t, "created"),
+		}, colors)
+	}
+
+	table.Render()
+	fmt.Fprintf(w, "\nTotal: %d ticket(s)\n", len(tickets))
+}
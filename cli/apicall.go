@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== API COMMANDS ====================
+
+func (app *App) apiCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Send raw requests to the osTicket API",
+	}
+
+	// api call
+	callCmd := &cobra.Command{
+		Use:   "call",
+		Short: "Send an arbitrary query and print the raw response",
+		Long: `Send any query/condition/sort/parameters request to the API plugin and print
+the response as returned, for plugin features the CLI does not wrap yet.
+
+  osticket api call --query ticket --condition all --sort status --param status=1
+  osticket api call --body request.json
+
+Parameter values that are valid JSON keep their type (--param id=5 sends a
+number, --param id='"5"' a string). Flags override fields read from --body.
+With --dry-run the request is printed instead of sent, e.g. to save it for
+--body.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			req, err := app.callRequest(cmd)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			method, _ := cmd.Flags().GetString("method")
+			method = strings.ToUpper(method)
+			if method != "GET" && method != "POST" {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --method %q: use GET or POST", method))
+				exit(1)
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				app.render(output.JSON, &output.Result{Value: req})
+				return
+			}
+
+			client := app.client(cmd.Context())
+			respBody, err := client.Call(cmd.Context(), method, *req)
+			if app.queued(err) {
+				return
+			}
+			var apiErr *osticket.Error
+			if errors.As(err, &apiErr) && len(apiErr.Body) > 0 {
+				// Show what the server sent for an HTTP error too
+				respBody = apiErr.Body
+			} else if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			result := &output.Result{Raw: respBody}
+			if json.Valid(respBody) {
+				result.Value = json.RawMessage(respBody)
+				app.render(output.JSON, result)
+			} else {
+				// Not JSON (e.g. a PHP error page); print it untouched
+				app.render(output.Raw, result)
+			}
+
+			if apiErr != nil {
+				exit(exitCode(apiErr))
+			}
+			if respErr := osticket.ResponseError(respBody); respErr != nil {
+				exit(exitCode(respErr))
+			}
+		},
+	}
+	callCmd.Flags().String("query", "", "Query (e.g. ticket, user, department)")
+	callCmd.Flags().String("condition", "", "Condition (e.g. all, specific, add)")
+	callCmd.Flags().String("sort", "", "Sort")
+	callCmd.Flags().StringArray("param", nil, "Request parameter as key=value (repeatable)")
+	callCmd.Flags().String("body", "", "Read the request from a JSON file ('-' for stdin)")
+	callCmd.Flags().String("method", "POST", "HTTP method (GET or POST)")
+	callCmd.Flags().Bool("dry-run", false, "Print the request instead of sending it")
+	cmd.AddCommand(callCmd)
+
+	// api describe
+	describeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Infer the fields of a query's response from a sample call",
+		Long: `Send a read-only query and list every field in the response with its JSON
+type, to see what a plugin version returns before writing filters or
+templates against it.
+
+  osticket api describe --query ticket --condition specific --sort id --param id=1`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			req, err := app.callRequest(cmd)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			if osticket.IsMutation(*req) {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("refusing to describe %q: it changes server state", req.Condition))
+				exit(1)
+			}
+
+			respBody, err := client.Call(cmd.Context(), "GET", *req)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			var sample interface{}
+			if err := json.Unmarshal(respBody, &sample); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("response is not JSON: %v", err))
+				exit(1)
+			}
+
+			fields := map[string]map[string]bool{}
+			inferFields("", sample, fields)
+			paths := make([]string, 0, len(fields))
+			for path := range fields {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			schema := make([]schemaField, len(paths))
+			for i, path := range paths {
+				schema[i] = schemaField{Field: path, Type: typeList(fields[path])}
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: schema,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"Field", "Type"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+					for _, f := range schema {
+						table.Append([]string{f.Field, f.Type})
+					}
+					table.Render()
+				},
+			})
+		},
+	}
+	describeCmd.Flags().String("query", "", "Query (e.g. ticket, user, department)")
+	describeCmd.Flags().String("condition", "", "Condition (e.g. all, specific)")
+	describeCmd.Flags().String("sort", "", "Sort")
+	describeCmd.Flags().StringArray("param", nil, "Request parameter as key=value (repeatable)")
+	describeCmd.Flags().String("body", "", "Read the request from a JSON file ('-' for stdin)")
+	cmd.AddCommand(describeCmd)
+
+	return cmd
+}
+
+// callRequest builds the request for api call from --body and the flags
+func (app *App) callRequest(cmd *cobra.Command) (*osticket.Request, error) {
+	var req osticket.Request
+
+	if path, _ := cmd.Flags().GetString("body"); path != "" {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(app.In)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read request: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("invalid request JSON in %s: %w", path, err)
+		}
+	}
+
+	if cmd.Flags().Changed("query") {
+		req.Query, _ = cmd.Flags().GetString("query")
+	}
+	if cmd.Flags().Changed("condition") {
+		req.Condition, _ = cmd.Flags().GetString("condition")
+	}
+	if cmd.Flags().Changed("sort") {
+		req.Sort, _ = cmd.Flags().GetString("sort")
+	}
+
+	params, _ := cmd.Flags().GetStringArray("param")
+	if len(params) > 0 {
+		if req.Parameters == nil {
+			req.Parameters = map[string]interface{}{}
+		}
+		if err := setParams(req.Parameters, "--param", params); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Query == "" || req.Condition == "" {
+		return nil, fmt.Errorf("a query and condition are required (--query/--condition or --body)")
+	}
+	return &req, nil
+}
+
+// schemaField is one field of an inferred response schema
+type schemaField struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+}
+
+// inferFields records the JSON types seen at each path of v. Array
+// elements share the path "<array>[]", so fields that vary between
+// elements list every type seen.
+func inferFields(path string, v interface{}, fields map[string]map[string]bool) {
+	var kind string
+	switch val := v.(type) {
+	case map[string]interface{}:
+		kind = "object"
+		for key, child := range val {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			inferFields(childPath, child, fields)
+		}
+	case []interface{}:
+		kind = "array"
+		for _, child := range val {
+			inferFields(path+"[]", child, fields)
+		}
+	case string:
+		kind = "string"
+	case float64:
+		kind = "number"
+	case bool:
+		kind = "boolean"
+	case nil:
+		kind = "null"
+	}
+
+	if path == "" {
+		return
+	}
+	if fields[path] == nil {
+		fields[path] = map[string]bool{}
+	}
+	fields[path][kind] = true
+}
+
+// typeList joins the types seen for a field, e.g. "number|string"
+func typeList(kinds map[string]bool) string {
+	list := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		list = append(list, kind)
+	}
+	sort.Strings(list)
+	return strings.Join(list, "|")
+}
@@ -0,0 +1,500 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/osticket-cli-go/internal/cache"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/credentials"
+	"github.com/osticket-cli-go/internal/log"
+	"github.com/osticket-cli-go/internal/offline"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// App holds what the commands of one command tree share: the global flag
+// values, the IO streams and how the API client is built. Commands read it
+// instead of package globals, so separate trees (e.g. in tests) can run
+// side by side in one process.
+type App struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	// NewClient builds the API client for a command; nil builds it from
+	// the config and global flags
+	NewClient func(ctx context.Context) osticket.OSTicketAPI
+
+	in *bufio.Reader
+
+	// Global flags
+	offline        bool
+	queueOnFailure bool
+	noCache        bool
+	debug          bool
+	profile        string
+	// logLevel backs --log-level; log is set up from it and the log_file
+	// config before a command runs
+	logLevel string
+	log      *slog.Logger
+	// faults backs the development-only --inject-faults
+	faults string
+	// record and replay are the cassette directories of --record and
+	// --replay
+	record string
+	replay string
+	// retries, retryWait, rateLimit, warnSlow and the timeouts are -1
+	// unless given on the command line
+	retries        int
+	retryWait      time.Duration
+	rateLimit      float64
+	warnSlow       time.Duration
+	timeout        time.Duration
+	connectTimeout time.Duration
+	readTimeout    time.Duration
+	// conn overrides the configured connection settings where set
+	conn   osticket.Connection
+	output string
+	fields []string
+	// jq backs --jq; query is the compiled expression
+	jq    string
+	query *output.Query
+	// jsonOutput and rawOutput back the deprecated --json and --raw flags
+	jsonOutput bool
+	rawOutput  bool
+	// recorder keeps the API responses for --output raw
+	recorder *osticket.Recorder
+	// quiet backs --quiet; stdout is where results go while Out discards
+	// everything else
+	quiet  bool
+	stdout io.Writer
+}
+
+// newApp creates an App on the process's standard streams
+func newApp() *App {
+	return &App{
+		In:  os.Stdin,
+		Out: color.Output,
+		Err: color.Error,
+	}
+}
+
+// Help groups of the top-level commands
+const (
+	groupTicket    = "ticket"
+	groupUser      = "user"
+	groupReporting = "reporting"
+	groupServer    = "server"
+	groupAdmin     = "admin"
+)
+
+// addGrouped adds commands to root under a help group
+func addGrouped(root *cobra.Command, group string, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.GroupID = group
+		root.AddCommand(c)
+	}
+}
+
+// stdin returns In, buffered for line prompts
+func (app *App) stdin() *bufio.Reader {
+	if app.in == nil {
+		app.in = bufio.NewReader(app.In)
+	}
+	return app.in
+}
+
+// rootCmd builds the command tree
+func (app *App) rootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "osticket",
+		Short: "CLI tool for interacting with osTicket",
+		Long: `CLI tool for interacting with osTicket.
+
+Run 'osticket examples' for recipes covering common tasks.`,
+		Version: "1.0.0",
+	}
+	rootCmd.SetIn(app.In)
+	rootCmd.SetOut(app.Out)
+	rootCmd.SetErr(app.Err)
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		loadErr := config.Load()
+		app.setupLog()
+		if loadErr != nil {
+			app.logger().Warn(loadErr.Error())
+		}
+		if err := config.SetProfile(app.profile); err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		overrides, err := app.credentialOverrides(cmd)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		config.SetOverrides(overrides)
+		if err := app.checkOutputFlag(); err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		if err := app.checkCassetteFlags(); err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(1)
+		}
+		if app.quiet && app.stdout == nil {
+			app.stdout, app.Out = app.Out, io.Discard
+		}
+	}
+	rootCmd.PersistentFlags().StringVar(&app.profile, "profile", "", "Configuration profile to use (env: "+config.EnvProfile+")")
+	rootCmd.PersistentFlags().String("url", "", "API base URL for this invocation only (overrides env and config)")
+	rootCmd.PersistentFlags().String("api-key", "", "API key for this invocation only (overrides env and config)")
+	rootCmd.PersistentFlags().String("api-key-file", "", "Read the API key for this invocation from a file")
+	rootCmd.PersistentFlags().Bool("api-key-stdin", false, "Read the API key for this invocation from stdin")
+	rootCmd.PersistentFlags().IntVar(&app.retries, "retries", -1, "Retries for failed requests (default from config, 2)")
+	rootCmd.PersistentFlags().DurationVar(&app.retryWait, "retry-wait", -1, "Base delay between retries, doubled each time (default from config, 500ms)")
+	rootCmd.PersistentFlags().Float64Var(&app.rateLimit, "rate-limit", -1, "Maximum requests per second, 0 for no limit (default from config, no limit)")
+	rootCmd.PersistentFlags().DurationVar(&app.warnSlow, "warn-slow", -1, "Warn about API calls slower than this, e.g. 2s (default from config, off)")
+	rootCmd.PersistentFlags().DurationVar(&app.timeout, "timeout", -1, "Time limit of each API request, 0 for none (default from config, 30s)")
+	rootCmd.PersistentFlags().DurationVar(&app.connectTimeout, "connect-timeout", -1, "Time limit for connecting to the server (default from config, 10s)")
+	rootCmd.PersistentFlags().DurationVar(&app.readTimeout, "read-timeout", -1, "Time limit for the server to start answering, 0 for none (default from config, none)")
+	rootCmd.PersistentFlags().BoolVar(&app.offline, "offline", false, "Serve reads from local snapshots and queue changes in the outbox")
+	rootCmd.PersistentFlags().BoolVar(&app.queueOnFailure, "queue-on-failure", false, "Queue changes in the outbox when the server cannot be reached, to send later with 'outbox flush'")
+	rootCmd.PersistentFlags().BoolVar(&app.noCache, "no-cache", false, "Fetch departments, topics, SLAs and staff from the server instead of the cache")
+	rootCmd.PersistentFlags().StringVar(&app.conn.Proxy, "proxy", "", "HTTP, HTTPS or SOCKS5 proxy URL (default from config, then HTTPS_PROXY)")
+	rootCmd.PersistentFlags().StringVar(&app.conn.CAFile, "ca-cert", "", "PEM CA bundle to trust in addition to the system CAs")
+	rootCmd.PersistentFlags().StringVar(&app.conn.CertFile, "client-cert", "", "PEM client certificate for TLS client authentication")
+	rootCmd.PersistentFlags().StringVar(&app.conn.KeyFile, "client-key", "", "PEM key of the client certificate")
+	rootCmd.PersistentFlags().BoolVar(&app.conn.Insecure, "insecure", false, "Skip TLS certificate verification (unsafe)")
+	rootCmd.PersistentFlags().BoolVar(&app.debug, "debug", false, "Log API requests and responses, with the API key redacted (env: "+config.EnvDebug+")")
+	logLevel := os.Getenv(config.EnvLogLevel)
+	if logLevel == "" {
+		logLevel = "warn"
+	}
+	rootCmd.PersistentFlags().StringVar(&app.logLevel, "log-level", logLevel, "Diagnostics to log: debug, info, warn or error (env: "+config.EnvLogLevel+")")
+	rootCmd.PersistentFlags().StringVar(&app.faults, "inject-faults", os.Getenv(config.EnvInjectFaults), "Development only: simulate a degraded API, e.g. p50-latency=2s,error-rate=0.1 (env: "+config.EnvInjectFaults+")")
+	rootCmd.PersistentFlags().MarkHidden("inject-faults")
+	rootCmd.PersistentFlags().StringVar(&app.record, "record", "", "Save the API requests and responses of this run to a cassette directory")
+	rootCmd.PersistentFlags().StringVar(&app.replay, "replay", "", "Answer API requests from a cassette directory made with --record, without a server")
+	app.addOutputFlags(rootCmd)
+
+	// Add commands, grouped in the help output
+	rootCmd.AddGroup(
+		&cobra.Group{ID: groupTicket, Title: "Ticket Commands:"},
+		&cobra.Group{ID: groupUser, Title: "User and Organization Commands:"},
+		&cobra.Group{ID: groupReporting, Title: "Reporting Commands:"},
+		&cobra.Group{ID: groupServer, Title: "Server Commands:"},
+		&cobra.Group{ID: groupAdmin, Title: "Administration Commands:"},
+	)
+	addGrouped(rootCmd, groupTicket, app.ticketCmd(), app.templateCmd(), app.cannedCmd(), app.taskCmd(), app.outboxCmd())
+	addGrouped(rootCmd, groupUser, app.userCmd(), app.orgCmd(), app.staffCmd())
+	addGrouped(rootCmd, groupReporting, app.exportGroupCmd(), app.surveyCmd(), app.handoffCmd())
+	addGrouped(rootCmd, groupServer, app.infoCmd(), app.apiCmd(), app.daemonCmd(), app.serveCmd())
+	addGrouped(rootCmd, groupAdmin, app.initCmd(), app.configCmd(), app.holdCmd(), app.retentionCmd(), app.importCmd(), app.adminCmd())
+	rootCmd.AddCommand(app.examplesCmd())
+	rootCmd.AddCommand(app.metaCmd())
+	rootCmd.AddCommand(app.versionCmd(rootCmd.Version))
+	app.addHelpSearch(rootCmd)
+	suggestSubcommands(rootCmd)
+	rootCmd.SetFlagErrorFunc(flagError)
+	app.registerCompletions(rootCmd)
+	return rootCmd
+}
+
+// client returns the API client for a command, built by NewClient when
+// set
+func (app *App) client(ctx context.Context) osticket.OSTicketAPI {
+	if app.NewClient != nil {
+		return app.NewClient(ctx)
+	}
+	return app.defaultClient(ctx)
+}
+
+// baseURL returns the endpoint a client sends to. Clients other than
+// *osticket.Client, such as fakes, are taken to use the configured one.
+func baseURL(client osticket.OSTicketAPI) string {
+	if c, ok := client.(*osticket.Client); ok {
+		return c.BaseURL
+	}
+	return config.GetBaseURL()
+}
+
+// defaultClient builds the API client from the config and global flags,
+// exiting when the CLI is not configured
+func (app *App) defaultClient(ctx context.Context) *osticket.Client {
+	if app.replay != "" {
+		return app.replayClient()
+	}
+	if !config.IsConfigured() {
+		if profile := config.GetProfile(); profile != "" {
+			fmt.Fprintln(app.Err, red(fmt.Sprintf("Profile %q not configured. Run: osticket config set --profile %s --url <url> --key <apiKey>", profile, profile)))
+		} else {
+			fmt.Fprintln(app.Err, red("CLI not configured. Run: osticket config set --url <url> --key <apiKey>"))
+		}
+		exit(1)
+	}
+	apiKey := config.GetAPIKey()
+	if config.UsesCredentialProvider() {
+		var err error
+		apiKey, err = credentials.Resolve(ctx, config.GetCredentialSpec())
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+	}
+
+	client, err := osticket.NewClient(config.GetBaseURL(), apiKey, append(app.timeouts(), osticket.WithConnection(app.connection()))...)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if app.faults != "" {
+		faults, err := osticket.ParseFaults(app.faults)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("--inject-faults: %v", err))
+			exit(1)
+		}
+		app.logger().Warn("injecting API faults: " + faults.String())
+		client.HTTPClient.Transport = faults.Transport(client.HTTPClient.Transport)
+	}
+	client.Store = newStore()
+	client.Offline = app.offline
+	client.QueueOnFailure = app.queueOnFailure
+	client.Retries = config.GetRetries()
+	client.RetryWait = config.GetRetryWait()
+	if app.retries >= 0 {
+		client.Retries = app.retries
+	}
+	if app.retryWait >= 0 {
+		client.RetryWait = app.retryWait
+	}
+	rate := config.GetRateLimit()
+	if app.rateLimit >= 0 {
+		rate = app.rateLimit
+	}
+	if rate > 0 {
+		client.Limiter = osticket.NewRateLimiter(rate)
+	}
+	if app.outputFormat("") == output.Raw {
+		if app.recorder == nil {
+			app.recorder = &osticket.Recorder{}
+		}
+		client.Recorder = app.recorder
+	}
+	client.SlowThreshold = config.GetWarnSlow()
+	if app.warnSlow >= 0 {
+		client.SlowThreshold = app.warnSlow
+	}
+	client.OnSlow = func(call osticket.SlowCall) {
+		app.logger().Warn(fmt.Sprintf("slow API call: %s took %s (budget %s)", call, call.Elapsed.Round(time.Millisecond), client.SlowThreshold))
+	}
+	client.Cache = cache.New(config.GetListCacheDir(), config.GetCacheTTL())
+	session, err := config.GetSession()
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(1)
+	}
+	redact := []string{"apikey"}
+	if session != nil {
+		// Set before debug logging wraps the transport, so login calls
+		// and the password they carry are never logged
+		session.HTTPClient = &http.Client{Transport: client.HTTPClient.Transport, Timeout: client.HTTPClient.Timeout}
+		client.Session = session
+		redact = append(redact, session.HeaderName())
+	}
+	if app.record != "" {
+		// Wrapped after the session is set up, so login calls are never
+		// recorded; cached lists are fetched so they are
+		client.HTTPClient.Transport = osticket.NewCassette(app.record).Record(client.HTTPClient.Transport)
+	}
+	if app.debug || config.DebugFromEnv() {
+		client.HTTPClient.Transport = &log.Transport{Base: client.HTTPClient.Transport, Log: app.logger(), Redact: redact}
+	}
+	client.Cache.Refresh = app.noCache || app.record != ""
+	client.Logger = app.logger()
+	return client
+}
+
+// checkCassetteFlags rejects --record and --replay where they cannot work
+func (app *App) checkCassetteFlags() error {
+	switch {
+	case app.record != "" && app.replay != "":
+		return fmt.Errorf("--record and --replay cannot be combined")
+	case (app.record != "" || app.replay != "") && app.offline:
+		return fmt.Errorf("--record and --replay cannot be combined with --offline")
+	case app.replay != "":
+		if info, err := os.Stat(app.replay); err != nil || !info.IsDir() {
+			return fmt.Errorf("--replay: %s is not a cassette directory", app.replay)
+		}
+	}
+	return nil
+}
+
+// replayClient builds a client answering from the --replay cassette. It
+// needs no configuration and never touches the network, the cache or the
+// offline snapshots.
+func (app *App) replayClient() *osticket.Client {
+	baseURL := config.GetBaseURL()
+	if baseURL == "" {
+		baseURL = "http://replay.invalid/"
+	}
+	client, err := osticket.NewClient(baseURL, "",
+		osticket.WithHTTPClient(&http.Client{Transport: osticket.NewCassette(app.replay).Replay()}),
+		osticket.WithRetries(0, 0),
+		osticket.WithLogger(app.logger()),
+	)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if app.outputFormat("") == output.Raw {
+		if app.recorder == nil {
+			app.recorder = &osticket.Recorder{}
+		}
+		client.Recorder = app.recorder
+	}
+	if app.debug || config.DebugFromEnv() {
+		client.HTTPClient.Transport = &log.Transport{Base: client.HTTPClient.Transport, Log: app.logger()}
+	}
+	return client
+}
+
+// setupLog creates the logger diagnostics go through: stderr, or the
+// log_file config as JSON lines. --debug lowers the level to debug.
+func (app *App) setupLog() {
+	level, err := log.ParseLevel(app.logLevel)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("--log-level: %v", err))
+		exit(1)
+	}
+	if (app.debug || config.DebugFromEnv()) && level > slog.LevelDebug {
+		level = slog.LevelDebug
+	}
+	opts := log.Options{Level: level, File: config.GetLogFile(), Console: app.Err, Label: consoleLabel}
+	logger, err := log.Open(opts)
+	if err != nil {
+		opts.File = ""
+		logger, _ = log.Open(opts)
+		logger.Warn(err.Error() + " (logging to stderr)")
+	}
+	app.log = logger
+	config.SetLogger(logger)
+}
+
+// logger returns the logger diagnostics go through. Before setupLog has
+// run, e.g. while completing, warnings go to Err.
+func (app *App) logger() *slog.Logger {
+	if app.log == nil {
+		app.log = slog.New(log.NewConsoleHandler(app.Err, slog.LevelWarn, consoleLabel))
+	}
+	return app.log
+}
+
+// consoleLabel colors the level prefix of diagnostics on stderr
+func consoleLabel(level slog.Level) string {
+	label := log.DefaultLabel(level)
+	switch {
+	case level >= slog.LevelError:
+		return red(label)
+	case level >= slog.LevelWarn:
+		return yellow(label)
+	}
+	return label
+}
+
+// connection returns the configured connection settings with the global
+// --proxy, --ca-cert, --client-cert, --client-key and --insecure flags
+// applied, warning when TLS verification is off
+func (app *App) connection() osticket.Connection {
+	conn := config.GetConnection()
+	if app.conn.Proxy != "" {
+		conn.Proxy = app.conn.Proxy
+	}
+	if app.conn.CAFile != "" {
+		conn.CAFile = app.conn.CAFile
+	}
+	if app.conn.CertFile != "" || app.conn.KeyFile != "" {
+		conn.CertFile, conn.KeyFile = app.conn.CertFile, app.conn.KeyFile
+	}
+	if app.conn.Insecure {
+		conn.Insecure = true
+	}
+	if conn.Insecure {
+		app.logger().Warn("TLS certificate verification is disabled")
+	}
+	return conn
+}
+
+// timeouts returns the client options for the configured timeouts, with
+// the global flags taking precedence
+func (app *App) timeouts() []osticket.Option {
+	pick := func(flag, configured time.Duration) time.Duration {
+		if flag >= 0 {
+			return flag
+		}
+		return configured
+	}
+	return []osticket.Option{
+		osticket.WithTimeout(pick(app.timeout, config.GetTimeout())),
+		osticket.WithConnectTimeout(pick(app.connectTimeout, config.GetConnectTimeout())),
+		osticket.WithReadTimeout(pick(app.readTimeout, config.GetReadTimeout())),
+	}
+}
+
+// credentialOverrides reads the global --url and --api-key* flags. The
+// root's persistent flags are looked up explicitly because config set has
+// local --url/--key flags of its own.
+func (app *App) credentialOverrides(cmd *cobra.Command) (config.Overrides, error) {
+	var o config.Overrides
+	flags := cmd.Root().PersistentFlags()
+
+	o.BaseURL, _ = flags.GetString("url")
+	apiKey, _ := flags.GetString("api-key")
+	keyFile, _ := flags.GetString("api-key-file")
+	keyStdin, _ := flags.GetBool("api-key-stdin")
+
+	sources := 0
+	for _, set := range []bool{apiKey != "", keyFile != "", keyStdin} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return o, fmt.Errorf("use only one of --api-key, --api-key-file and --api-key-stdin")
+	}
+
+	switch {
+	case apiKey != "":
+		o.APIKey, o.APIKeySource = apiKey, "flag:--api-key"
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return o, fmt.Errorf("could not read --api-key-file: %w", err)
+		}
+		o.APIKey, o.APIKeySource = strings.TrimSpace(string(data)), "flag:--api-key-file"
+	case keyStdin:
+		data, err := io.ReadAll(app.In)
+		if err != nil {
+			return o, fmt.Errorf("could not read API key from stdin: %w", err)
+		}
+		o.APIKey, o.APIKeySource = strings.TrimSpace(string(data)), "flag:--api-key-stdin"
+	}
+
+	if (keyFile != "" || keyStdin) && o.APIKey == "" {
+		return o, fmt.Errorf("API key input is empty")
+	}
+	return o, nil
+}
+
+// newStore opens the offline snapshot and outbox store for the active profile
+func newStore() *offline.Store {
+	return offline.NewStore(config.GetSnapshotDir(), config.GetOutboxDir())
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/osticket-cli-go/internal/checkpoint"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== BULK JOB TIME BUDGET ====================
+
+// exitIncomplete is the exit status of a bulk job stopped by --max-duration
+const exitIncomplete = 7
+
+// addBudgetFlags registers --max-duration, and --resume for jobs that
+// checkpoint their progress
+func addBudgetFlags(cmd *cobra.Command, resumable bool) {
+	cmd.Flags().Duration("max-duration", 0, "Stop starting new work after this long, e.g. 10m (0 = no limit)")
+	if resumable {
+		cmd.Flags().Bool("resume", false, "Continue from where a run stopped by --max-duration left off")
+	}
+}
+
+// budget tracks a bulk job's --max-duration
+type budget struct {
+	limit    time.Duration
+	deadline time.Time
+}
+
+// newBudget starts the clock for a job
+func newBudget(cmd *cobra.Command) *budget {
+	limit, _ := cmd.Flags().GetDuration("max-duration")
+	b := &budget{limit: limit}
+	if limit > 0 {
+		b.deadline = time.Now().Add(limit)
+	}
+	return b
+}
+
+// exceeded reports whether the job should stop starting new work
+func (b *budget) exceeded() bool {
+	return b.limit > 0 && time.Now().After(b.deadline)
+}
+
+// collectTickets fetches every page of a ticket search within the time
+// budget. When the budget runs out, progress is saved under job and
+// stopped is true; --resume continues from the saved offset.
+func (app *App) collectTickets(cmd *cobra.Command, job string, pageSize int, fetch func(osticket.Page) (*osticket.SimpleTicketResponse, error)) (data *osticket.SimpleTicketResponse, stopped bool, err error) {
+	stopped, err = app.pagesWithinBudget(cmd, job, func(offset int, stop func() bool) (next, total int, err error) {
+		data, next, err = osticket.CollectPagesFrom(pageSize, offset, stop, fetch)
+		if err != nil {
+			return next, 0, err
+		}
+		return next, data.Total, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return data, stopped, nil
+}
+
+// streamTickets is collectTickets handing each page to emit as it arrives
+// rather than returning every ticket at the end
+func (app *App) streamTickets(cmd *cobra.Command, job string, pageSize int, fetch func(osticket.Page) (*osticket.SimpleTicketResponse, error), emit func([]map[string]interface{}) error) (stopped bool, err error) {
+	return app.pagesWithinBudget(cmd, job, func(offset int, stop func() bool) (next, total int, err error) {
+		return osticket.StreamPages(pageSize, offset, stop, fetch, emit)
+	})
+}
+
+// pagesWithinBudget runs a paged fetch from the --resume checkpoint of job,
+// or the start, until it ends or the time budget runs out. fetch returns
+// the offset to resume from, -1 when done, and the total reported.
+func (app *App) pagesWithinBudget(cmd *cobra.Command, job string, fetch func(offset int, stop func() bool) (next, total int, err error)) (stopped bool, err error) {
+	store := checkpoint.NewStore(config.GetCheckpointDir())
+
+	offset := 0
+	if resume, _ := cmd.Flags().GetBool("resume"); resume {
+		cp, err := store.Load(job)
+		switch {
+		case errors.Is(err, checkpoint.ErrNotFound):
+			fmt.Fprintln(app.Err, yellow("No checkpoint found for this search, starting from the beginning"))
+		case err != nil:
+			return false, err
+		default:
+			offset = cp.Offset
+			fmt.Fprintf(app.Err, "Resuming at ticket %d (checkpoint from %s)\n", offset+1, cp.Created.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	b := newBudget(cmd)
+	next, total, err := fetch(offset, b.exceeded)
+	if err != nil {
+		return false, err
+	}
+
+	if next < 0 {
+		if err := store.Remove(job); err != nil {
+			app.logger().Warn("could not remove checkpoint: " + err.Error())
+		}
+		return false, nil
+	}
+
+	if err := store.Save(&checkpoint.Checkpoint{Job: job, Offset: next, Total: total}); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(app.Err, "%s stopped after %s: fetched tickets %d-%d", yellow("⚠ Time budget reached:"), b.limit, offset+1, next)
+	if total > next {
+		fmt.Fprintf(app.Err, " of %d, about %d remaining", total, total-next)
+	}
+	fmt.Fprintln(app.Err)
+	fmt.Fprintln(app.Err, "  Run the same command with --resume to continue.")
+	return true, nil
+}
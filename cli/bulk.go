@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/osticket-cli-go/internal/multierror"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// ==================== BULK JOB FAILURES ====================
+
+// addFailFastFlag registers --fail-fast for jobs that otherwise carry on
+// past items that fail
+func addFailFastFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("fail-fast", false, "Stop at the first item that fails instead of carrying on")
+}
+
+// newFailures starts collecting the failures of a job over total items
+// (0 when not known up front), honouring --fail-fast
+func newFailures(cmd *cobra.Command, what string, total int) *multierror.Errors {
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	errs := multierror.New(what, failFast)
+	errs.Total = total
+	return errs
+}
+
+// reportFailures ends a job that had failures, after its result has been
+// written: the failures go to stderr as a table, or as one JSON document
+// when the result is JSON, YAML or raw, and the job exits with status 1
+func (app *App) reportFailures(def string, errs *multierror.Errors) {
+	if errs.Len() == 0 {
+		return
+	}
+	switch app.outputFormat(def) {
+	case output.JSON, output.YAML, output.Raw:
+		data, err := json.MarshalIndent(errs, "", "  ")
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(1)
+		}
+		fmt.Fprintln(app.Err, string(data))
+	default:
+		fmt.Fprintln(app.Err)
+		errs.WriteTable(app.Err)
+		if errs.FailFast {
+			fmt.Fprintln(app.Err, yellow("Stopped at the first failure"))
+		}
+	}
+	exit(1)
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/markup"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== CANNED RESPONSES ====================
+
+func (app *App) cannedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "canned",
+		Short: "List canned responses for ticket replies",
+		Long: `Canned responses are the reply library agents use in the osTicket web
+UI. Send one with 'ticket reply <id> --canned <id|title>'. Needs an API
+plugin that answers the canned query.`,
+	}
+
+	// canned list
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List canned responses",
+		Example: `  osticket canned list
+  osticket canned list --dept Support -o json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			all, _ := cmd.Flags().GetBool("all")
+
+			data, err := client.GetCannedResponses(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			var depts []int
+			if values, _ := cmd.Flags().GetStringSlice("dept"); len(values) > 0 {
+				depts = app.deptFlagIDs(cmd, client, values)
+			}
+
+			responses := []osticket.CannedResponse{}
+			for _, r := range data.Responses {
+				// Responses of no department are offered in every one
+				if (r.IsEnabled || all) && (len(depts) == 0 || r.DeptID == 0 || containsInt(depts, r.DeptID)) {
+					responses = append(responses, r)
+				}
+			}
+			sort.SliceStable(responses, func(i, j int) bool {
+				return strings.ToLower(responses[i].Title) < strings.ToLower(responses[j].Title)
+			})
+			data = &osticket.CannedData{Total: len(responses), Responses: responses}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  responses,
+				Table: func(w io.Writer) {
+					if len(responses) == 0 {
+						fmt.Fprintln(w, yellow("No canned responses found"))
+						return
+					}
+					app.displayCanned(cmd.Context(), client, w, responses)
+				},
+			})
+		},
+	}
+	listCmd.Flags().StringSlice("dept", nil, "Only list responses offered in these departments, by name or ID (repeatable)")
+	listCmd.Flags().Bool("all", false, "Include disabled responses")
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// displayCanned prints canned responses with the start of their text
+func (app *App) displayCanned(ctx context.Context, client osticket.OSTicketAPI, w io.Writer, responses []osticket.CannedResponse) {
+	deptNames := map[int]string{}
+	if depts, err := client.GetDepartments(ctx); err == nil {
+		for _, d := range depts.Departments {
+			deptNames[d.ID] = d.Name
+		}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Title", "Department", "Response"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+	for _, r := range responses {
+		dept := "All"
+		if r.DeptID != 0 {
+			dept = deptNames[r.DeptID]
+			if dept == "" {
+				dept = strconv.Itoa(r.DeptID)
+			}
+		}
+		title := r.Title
+		if !r.IsEnabled {
+			title += " (disabled)"
+		}
+		text := strings.Join(strings.Fields(markup.ToText(r.Response)), " ")
+		table.Append([]string{strconv.Itoa(r.ID), title, dept, truncate(text, 50)})
+	}
+	table.Render()
+}
+
+// findCanned returns the enabled canned response with an ID or title,
+// matching titles case-insensitively
+func findCanned(responses []osticket.CannedResponse, ref string) (*osticket.CannedResponse, error) {
+	id, _ := strconv.Atoi(ref)
+	var found []osticket.CannedResponse
+	for _, r := range responses {
+		if r.IsEnabled && (id != 0 && r.ID == id || strings.EqualFold(strings.TrimSpace(r.Title), strings.TrimSpace(ref))) {
+			found = append(found, r)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, &validate.FieldError{Flag: "canned", Value: ref, Reason: "no such enabled canned response (see 'osticket canned list')"}
+	case 1:
+		return &found[0], nil
+	}
+	var ids []string
+	for _, r := range found {
+		ids = append(ids, strconv.Itoa(r.ID))
+	}
+	return nil, &validate.FieldError{Flag: "canned", Value: ref, Reason: fmt.Sprintf("%d responses have this title, use an ID (%s)", len(found), strings.Join(ids, ", "))}
+}
+
+// useCanned makes --canned the body of ticket reply: the response with its
+// variables filled in, sent as HTML
+func (app *App) useCanned(cmd *cobra.Command, client osticket.OSTicketAPI, ticketID int, ref string) {
+	if cmd.Flags().Changed("body") {
+		fmt.Fprintln(app.Err, red("Error:"), "--canned cannot be combined with --body")
+		exit(exitValidation)
+	}
+	staffID, _ := cmd.Flags().GetInt("staff-id")
+
+	data, err := client.GetCannedResponses(cmd.Context())
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error loading canned responses:"), err)
+		exit(exitCode(err))
+	}
+	canned := mustValidate(findCanned(data.Responses, ref))
+	body, missing, err := app.expandCanned(cmd.Context(), client, canned.Response, ticketID, staffID, app.templateVars(cmd))
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if len(missing) > 0 {
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("canned response %q needs --var for %s", canned.Title, strings.Join(missing, ", ")))
+		exit(exitValidation)
+	}
+	cmd.Flags().Set("body", body)
+	cmd.Flags().Set("body-format", markup.HTML)
+}
+
+// cannedVar matches the %{name} variables of canned responses
+var cannedVar = regexp.MustCompile(`%\{([^{}]+)\}`)
+
+// ticketVarFields maps %{ticket.*} variables to ticket fields; names come
+// from enrichment
+var ticketVarFields = map[string]string{
+	"id":          "ticket_id",
+	"number":      "number",
+	"subject":     "subject",
+	"priority":    "priority",
+	"status":      "status_name",
+	"dept":        "dept_name",
+	"topic":       "topic_name",
+	"staff":       "staff_name",
+	"team":        "team_name",
+	"create_date": "created",
+	"due_date":    "duedate",
+	"close_date":  "closed",
+}
+
+// expandCanned fills in a canned response's variables: --var values first,
+// then what the ticket, its user and the replying agent say. It returns
+// the variables that are neither, so a reply never goes out with %{...}
+// in it. Values are HTML-escaped, as the response is HTML.
+func (app *App) expandCanned(ctx context.Context, client osticket.OSTicketAPI, text string, ticketID, staffID int, given map[string]string) (expanded string, missing []string, err error) {
+	vars := map[string]string{}
+	for k, v := range given {
+		vars[strings.TrimSuffix(strings.TrimPrefix(k, "%{"), "}")] = v
+	}
+
+	var ticket map[string]interface{}
+	var user *osticket.User
+	var staff *osticket.Staff
+	loadTicket := func() map[string]interface{} {
+		if ticket == nil && err == nil {
+			var data *osticket.SimpleTicketResponse
+			if data, err = client.GetTicket(ctx, strconv.Itoa(ticketID)); err == nil && len(data.Tickets) > 0 {
+				ticket = data.Tickets[0]
+				app.addTicketNames(ctx, client, data.Tickets, false)
+			}
+		}
+		return ticket
+	}
+	loadUser := func() *osticket.User {
+		if user == nil && err == nil && loadTicket() != nil {
+			var data *osticket.UserData
+			if data, err = client.GetUserByID(ctx, mapString(ticket, "user_id")); err == nil && len(data.Users) > 0 {
+				user = &data.Users[0]
+			}
+		}
+		return user
+	}
+	loadStaff := func() *osticket.Staff {
+		if staff == nil && err == nil && staffID != 0 {
+			var data *osticket.StaffData
+			if data, err = client.GetStaff(ctx, staffID); err == nil && len(data.Staff) > 0 {
+				staff = &data.Staff[0]
+			}
+		}
+		return staff
+	}
+
+	// lookup returns a variable's value from the ticket, user or agent
+	lookup := func(name string) (string, bool) {
+		switch name {
+		case "ticket.name", "ticket.user.name", "recipient.name":
+			if u := loadUser(); u != nil {
+				return u.Name, true
+			}
+		case "ticket.name.first", "recipient.name.first":
+			if u := loadUser(); u != nil {
+				return strings.Fields(u.Name + " ")[0], u.Name != ""
+			}
+		case "ticket.email", "ticket.user.email", "recipient.email":
+			if u := loadUser(); u != nil {
+				return u.Email, true
+			}
+		case "staff.name":
+			if s := loadStaff(); s != nil {
+				return s.Name(), true
+			}
+		case "staff.name.first", "staff.firstname":
+			if s := loadStaff(); s != nil {
+				return s.Firstname, true
+			}
+		case "staff.name.last", "staff.lastname":
+			if s := loadStaff(); s != nil {
+				return s.Lastname, true
+			}
+		case "staff.email":
+			if s := loadStaff(); s != nil {
+				return s.Email, true
+			}
+		case "staff.username":
+			if s := loadStaff(); s != nil {
+				return s.Username, true
+			}
+		default:
+			field, ok := ticketVarFields[strings.TrimPrefix(name, "ticket.")]
+			if ok && strings.HasPrefix(name, "ticket.") && loadTicket() != nil {
+				if _, set := ticket[field]; set {
+					return apiTime(mapString(ticket, field)), true
+				}
+			}
+		}
+		return "", false
+	}
+
+	expanded = cannedVar.ReplaceAllStringFunc(text, func(m string) string {
+		name := strings.TrimSpace(m[2 : len(m)-1])
+		value, ok := vars[name]
+		if !ok {
+			value, ok = lookup(name)
+		}
+		if !ok {
+			if !containsString(missing, name) {
+				missing = append(missing, name)
+			}
+			return m
+		}
+		return html.EscapeString(value)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return expanded, missing, nil
+}
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cyan   = color.New(color.FgCyan).SprintFunc()
+	green  = color.New(color.FgGreen).SprintFunc()
+	yellow = color.New(color.FgYellow).SprintFunc()
+	red    = color.New(color.FgRed).SprintFunc()
+)
+
+// Options configure a command tree built by NewRootCommand. Nil streams
+// default to the process's standard streams.
+type Options struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	// NewClient builds the API client for a command, e.g. an
+	// osticketest.Fake in tests; nil builds it from the config and flags
+	NewClient func(ctx context.Context) osticket.OSTicketAPI
+}
+
+// NewRootCommand builds the osticket command tree, e.g. to mount it as a
+// subcommand of another CLI. A failing command prints its error to Err
+// and returns an *ExitError carrying the exit status the standalone CLI
+// would use, instead of exiting the process.
+func NewRootCommand(opts Options) *cobra.Command {
+	app := newApp()
+	if opts.In != nil {
+		app.In = opts.In
+	}
+	if opts.Out != nil {
+		app.Out = opts.Out
+	}
+	if opts.Err != nil {
+		app.Err = opts.Err
+	}
+	app.NewClient = opts.NewClient
+	root := app.rootCmd()
+	app.trapExits(root)
+	return root
+}
+
+// ExitError is returned by a command that failed, with the exit status
+// the standalone CLI would use (e.g. 7 for a bulk job stopped early)
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// exitPanic unwinds a failing command to the handler installed by
+// trapExits. err, when set, is printed to Err first.
+type exitPanic struct {
+	code int
+	err  error
+}
+
+// exit ends the running command with an exit status
+func exit(code int) {
+	panic(exitPanic{code: code})
+}
+
+// Exit statuses scripts can rely on to tell why a command failed. Other
+// failures, including usage errors, exit with 1.
+const (
+	exitNotFound   = 2
+	exitAuth       = 3
+	exitValidation = 4
+	exitNetwork    = 5
+	exitServer     = 6
+)
+
+// exitCode returns the exit status for a command failing with err
+func exitCode(err error) int {
+	var apiErr *osticket.Error
+	var fieldErr *validate.FieldError
+	switch {
+	case errors.As(err, &apiErr):
+		switch apiErr.Kind {
+		case osticket.ErrAuth:
+			return exitAuth
+		case osticket.ErrNotFound:
+			return exitNotFound
+		case osticket.ErrValidation:
+			return exitValidation
+		case osticket.ErrServer, osticket.ErrNotAPI:
+			return exitServer
+		}
+	case errors.As(err, &fieldErr):
+		return exitValidation
+	case osticket.IsNetworkError(err):
+		return exitNetwork
+	}
+	return 1
+}
+
+// trapExits wraps the run hooks of cmd and its subcommands so exit ends
+// the command with an *ExitError rather than unwinding further
+func (app *App) trapExits(cmd *cobra.Command) {
+	trap := func(run func(*cobra.Command, []string)) func(*cobra.Command, []string) error {
+		return func(c *cobra.Command, args []string) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				p, ok := r.(exitPanic)
+				if !ok {
+					panic(r)
+				}
+				if p.err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), p.err)
+				}
+				// The command has reported its failure already
+				c.SilenceErrors = true
+				c.SilenceUsage = true
+				err = &ExitError{Code: p.code}
+			}()
+			run(c, args)
+			return nil
+		}
+	}
+
+	if cmd.PersistentPreRun != nil {
+		cmd.PersistentPreRunE = trap(cmd.PersistentPreRun)
+		cmd.PersistentPreRun = nil
+	}
+	if cmd.Run != nil {
+		cmd.RunE = trap(cmd.Run)
+		cmd.Run = nil
+	}
+	for _, sub := range cmd.Commands() {
+		app.trapExits(sub)
+	}
+}
+
+// versionCmd prints the version without touching the configuration
+func (app *App) versionCmd(version string) *cobra.Command {
+	return &cobra.Command{
+		Use:              "version",
+		Short:            "Print the CLI version",
+		Args:             cobra.NoArgs,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(app.Out, "osticket version %s\n", version)
+		},
+	}
+}
+
+// ==================== HELPER FUNCTIONS ====================
+
+// setParams stores key=value pairs from a repeatable flag in params.
+// Numbers, booleans and other JSON values keep their JSON type.
+func setParams(params map[string]interface{}, flag string, pairs []string) error {
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid %s %q, expected key=value", flag, pair)
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		params[key] = parsed
+	}
+	return nil
+}
+
+// searchPage builds the requested page from --limit, --offset and --page,
+// and reports whether --all was given
+func (app *App) searchPage(cmd *cobra.Command) (osticket.Page, bool) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	offset, _ := cmd.Flags().GetInt("offset")
+	pageNum, _ := cmd.Flags().GetInt("page")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if limit < 0 || offset < 0 || pageNum < 0 {
+		fmt.Fprintln(app.Err, red("Error:"), "--limit, --offset and --page cannot be negative")
+		exit(1)
+	}
+	if pageNum > 0 {
+		if limit == 0 {
+			fmt.Fprintln(app.Err, red("Error:"), "--page requires --limit")
+			exit(1)
+		}
+		if cmd.Flags().Changed("offset") {
+			fmt.Fprintln(app.Err, red("Error:"), "--page and --offset cannot be combined")
+			exit(1)
+		}
+		offset = (pageNum - 1) * limit
+	}
+	if all && (offset > 0 || pageNum > 0) {
+		fmt.Fprintln(app.Err, red("Error:"), "--all cannot be combined with --offset or --page")
+		exit(1)
+	}
+
+	return osticket.Page{Limit: limit, Offset: offset}, all
+}
+
+// fetchTickets fetches one page, or every page when all is set. stopped
+// reports that --max-duration ended an --all search early.
+func (app *App) fetchTickets(cmd *cobra.Command, job string, page osticket.Page, all bool, fetch func(osticket.Page) (*osticket.SimpleTicketResponse, error)) (data *osticket.SimpleTicketResponse, stopped bool, err error) {
+	if all {
+		return app.collectTickets(cmd, job, page.Limit, fetch)
+	}
+	data, err = fetch(page)
+	return data, false, err
+}
+
+// loadAttachments reads the --attach files, exiting on the first error
+func (app *App) loadAttachments(paths []string) []osticket.Attachment {
+	maxSize := config.GetMaxAttachmentSize()
+	var attachments []osticket.Attachment
+	for _, path := range paths {
+		a, err := osticket.LoadAttachment(path, maxSize)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		attachments = append(attachments, *a)
+	}
+	return attachments
+}
+
+// mustValidate returns a validated flag value or exits with the error
+func mustValidate[T any](value T, err error) T {
+	if err != nil {
+		panic(exitPanic{code: exitValidation, err: err})
+	}
+	return value
+}
+
+// queued reports whether err means the request was queued in the outbox,
+// offline or with --queue-on-failure, printing a notice if so
+func (app *App) queued(err error) bool {
+	var qErr *osticket.QueuedError
+	if !errors.As(err, &qErr) {
+		return false
+	}
+
+	value := map[string]string{"status": "queued", "outbox_id": qErr.ID}
+	heading := "Offline: request queued in outbox"
+	if qErr.Cause != nil {
+		value["reason"] = qErr.Cause.Error()
+		heading = "Server unreachable: request queued in outbox"
+	}
+	app.render(output.Table, &output.Result{
+		Value: value,
+		IDs:   []string{qErr.ID},
+		Table: func(w io.Writer) {
+			fmt.Fprintln(w, yellow("\n⚠ "+heading))
+			fmt.Fprintf(w, "  Outbox ID: %s\n", qErr.ID)
+			fmt.Fprintln(w, "  Run 'osticket outbox flush' when connectivity returns.")
+		},
+	})
+	return true
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// readIDs reads one ID per line from a file or, for "-", stdin, skipping
+// blank lines and # comments
+func (app *App) readIDs(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(app.In)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read IDs: %w", err)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	return ids, nil
+}
+
+// dedupe removes repeated values, keeping the first of each
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,644 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/osticket-cli-go/pkg/osticket/osticketest"
+)
+
+// runCLI runs a command tree against a fake API with the test's own config
+// and returns what it printed and its exit status
+func runCLI(t *testing.T, fake *osticketest.Fake, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	return runCLIInput(t, fake, "", args...)
+}
+
+// configDirs keeps each test's config directory, so that the commands a
+// test runs share their config and state
+var configDirs = map[*testing.T]string{}
+
+// runCLIInput is runCLI with input for the command's prompts
+func runCLIInput(t *testing.T, fake *osticketest.Fake, input string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	if configDirs[t] == "" {
+		configDirs[t] = t.TempDir()
+		t.Cleanup(func() { delete(configDirs, t) })
+	}
+	t.Setenv(config.EnvConfigDir, configDirs[t])
+	for _, env := range []string{config.EnvBaseURL, config.EnvAPIKey, config.EnvProfile, config.EnvInjectFaults} {
+		t.Setenv(env, "")
+	}
+
+	var out, errOut bytes.Buffer
+	root := NewRootCommand(Options{
+		In:        strings.NewReader(input),
+		Out:       &out,
+		Err:       &errOut,
+		NewClient: func(ctx context.Context) osticket.OSTicketAPI { return fake },
+	})
+	root.SetArgs(args)
+	err := root.ExecuteContext(context.Background())
+
+	var exitErr *ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.Code
+	case err != nil:
+		code = 1
+	}
+	return out.String(), errOut.String(), code
+}
+
+// newFake returns a fake API with one user and one open ticket of theirs
+func newFake() *osticketest.Fake {
+	fake := osticketest.NewFake()
+	fake.Users = []osticket.User{{UserID: 12, Name: "Ann Example", Email: "ann@example.com", Created: "2026-01-05 08:00:00"}}
+	fake.Tickets = []map[string]interface{}{{
+		"ticket_id": 42, "number": "100042", "subject": "Printer on fire", "user_id": 12,
+		"status_id": 1, "priority_id": 3, "dept_id": 1, "created": "2026-10-01 09:15:00",
+	}}
+	return fake
+}
+
+func decodeJSON(t *testing.T, s string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, s)
+	}
+}
+
+func TestTicketGet(t *testing.T) {
+	stdout, stderr, code := runCLI(t, newFake(), "ticket", "get", "100042", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var data osticket.SimpleTicketResponse
+	decodeJSON(t, stdout, &data)
+	if len(data.Tickets) != 1 || data.Tickets[0]["subject"] != "Printer on fire" || data.Tickets[0]["priority"] != "High" {
+		t.Errorf("tickets = %v", data.Tickets)
+	}
+}
+
+func TestTicketThreadNotFound(t *testing.T) {
+	_, stderr, code := runCLI(t, newFake(), "ticket", "thread", "999999")
+	if code != exitNotFound {
+		t.Errorf("exit %d, want %d: %s", code, exitNotFound, stderr)
+	}
+}
+
+func TestTicketCreateByEmail(t *testing.T) {
+	fake := newFake()
+	_, stderr, code := runCLI(t, fake, "ticket", "create", "--title", "VPN drops", "--subject", "Every hour or so",
+		"--email", "ann@example.com", "--priority", "1")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if len(fake.Tickets) != 2 {
+		t.Fatalf("got %d tickets, want 2", len(fake.Tickets))
+	}
+	created := fake.Tickets[1]
+	if created["subject"] != "VPN drops" || created["user_id"] != 12 || created["priority_id"] != 1 {
+		t.Errorf("created ticket = %v", created)
+	}
+}
+
+func TestTicketReply(t *testing.T) {
+	fake := newFake()
+	_, stderr, code := runCLI(t, fake, "ticket", "reply", "42", "--body", "Have you tried turning it off?", "--staff-id", "1")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	thread := fake.Threads[42]
+	if len(thread) != 1 || thread[0].Type != "R" || thread[0].Body != "Have you tried turning it off?" {
+		t.Errorf("thread = %+v", thread)
+	}
+}
+
+func TestTicketReplyMissingTicket(t *testing.T) {
+	_, _, code := runCLI(t, newFake(), "ticket", "reply", "7", "--body", "Hello", "--staff-id", "1")
+	if code != exitNotFound {
+		t.Errorf("exit %d, want %d", code, exitNotFound)
+	}
+}
+
+func TestUserGet(t *testing.T) {
+	stdout, stderr, code := runCLI(t, newFake(), "user", "get", "--email", "ann@example.com", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Ann Example") {
+		t.Errorf("output does not show the user:\n%s", stdout)
+	}
+}
+
+func TestInfoDepartments(t *testing.T) {
+	fake := newFake()
+	fake.Departments = append(fake.Departments, osticket.Department{ID: 5, Name: "Billing"})
+
+	stdout, stderr, code := runCLI(t, fake, "info", "departments")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	for _, want := range []string{"Support", "Billing"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("table does not list %s:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, _ = runCLI(t, fake, "info", "departments", "-o", "json")
+	var data osticket.DepartmentData
+	decodeJSON(t, stdout, &data)
+	if data.Total != 2 {
+		t.Errorf("total = %d, want 2", data.Total)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"auth", &osticket.Error{Kind: osticket.ErrAuth, StatusCode: 401, Message: "Valid API key required"}, exitAuth},
+		{"server", &osticket.Error{Kind: osticket.ErrServer, StatusCode: 500, Message: "Internal Server Error"}, exitServer},
+		{"not the API", &osticket.Error{Kind: osticket.ErrNotAPI, Message: "got an HTML page"}, exitServer},
+		{"other", errors.New("boom"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFake()
+			fake.Errors = map[string]error{"GetDepartments": tt.err}
+			_, stderr, code := runCLI(t, fake, "info", "departments")
+			if code != tt.want {
+				t.Errorf("exit %d, want %d", code, tt.want)
+			}
+			if !strings.Contains(stderr, tt.err.Error()) {
+				t.Errorf("stderr does not show the error:\n%s", stderr)
+			}
+		})
+	}
+}
+
+func TestTicketGetBatchFailures(t *testing.T) {
+	fake := newFake()
+	fake.Tickets = append(fake.Tickets, map[string]interface{}{"ticket_id": 43, "number": "100043", "subject": "Toner low", "status_id": 1})
+
+	stdout, stderr, code := runCLI(t, fake, "ticket", "get-batch", "100042", "555", "100043", "-o", "json")
+	if code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	var tickets []map[string]interface{}
+	decodeJSON(t, stdout, &tickets)
+	if len(tickets) != 2 {
+		t.Errorf("printed %d tickets, want the 2 that exist", len(tickets))
+	}
+	var report struct {
+		Failed int `json:"failed"`
+		Total  int `json:"total"`
+		Errors []struct {
+			Item  string `json:"item"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	decodeJSON(t, stderr, &report)
+	if report.Failed != 1 || report.Total != 3 || len(report.Errors) != 1 || report.Errors[0].Item != "555" {
+		t.Errorf("failure report = %+v", report)
+	}
+
+	// --fail-fast stops after the batch with the failure
+	stdout, stderr, code = runCLI(t, fake, "ticket", "get-batch", "555", "100042", "100043", "--fail-fast", "--concurrency", "1", "-o", "table")
+	if code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if strings.Contains(stdout, "Printer on fire") {
+		t.Errorf("fetched tickets after the failure:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "1 of 3 ticket(s) failed, 2 not attempted") {
+		t.Errorf("stderr does not summarize the failures:\n%s", stderr)
+	}
+}
+
+func TestTicketOverdueEscalate(t *testing.T) {
+	fake := newFake()
+	fake.Tickets[0]["isoverdue"] = 1
+	fake.Tickets = append(fake.Tickets,
+		map[string]interface{}{"ticket_id": 43, "number": "100043", "subject": "Toner low", "status_id": 1, "priority_id": 4, "dept_id": 1, "isoverdue": 1, "created": "2026-09-01 10:00:00"},
+		map[string]interface{}{"ticket_id": 44, "number": "100044", "subject": "New laptop", "status_id": 1, "priority_id": 2, "dept_id": 1, "created": "2026-08-01 10:00:00"},
+	)
+
+	stdout, stderr, code := runCLI(t, fake, "ticket", "overdue", "--escalate", "--notify", "Still open", "--staff-id", "1", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var report struct {
+		Tickets []map[string]interface{} `json:"tickets"`
+		Actions []overdueAction          `json:"actions"`
+	}
+	decodeJSON(t, stdout, &report)
+	if len(report.Tickets) != 2 || report.Tickets[0]["number"] != "100043" {
+		t.Errorf("tickets = %v, want 100043 then 100042", report.Tickets)
+	}
+	if fake.Tickets[0]["priority_id"] != 4 {
+		t.Errorf("High ticket escalated to priority %v, want 4", fake.Tickets[0]["priority_id"])
+	}
+	if fake.Tickets[1]["priority_id"] != 4 || report.Actions[0].Result != "skipped" {
+		t.Errorf("emergency ticket: priority %v, actions %+v", fake.Tickets[1]["priority_id"], report.Actions)
+	}
+	if len(fake.Threads[42]) != 1 || len(fake.Threads[43]) != 1 || len(fake.Threads[44]) != 0 {
+		t.Errorf("notes posted: %v", fake.Threads)
+	}
+
+	_, _, code = runCLI(t, fake, "ticket", "overdue", "--escalate")
+	if code != exitValidation {
+		t.Errorf("exit %d without --staff-id, want %d", code, exitValidation)
+	}
+}
+
+func TestTicketSearchNDJSON(t *testing.T) {
+	fake := newFake()
+	for id := 43; id <= 46; id++ {
+		fake.Tickets = append(fake.Tickets, map[string]interface{}{
+			"ticket_id": id, "number": fmt.Sprint(100000 + id), "subject": "Toner low", "status_id": 1,
+			"created": fmt.Sprintf("2026-10-%02d 09:00:00", id-40),
+		})
+	}
+
+	stdout, stderr, code := runCLI(t, fake, "ticket", "search", "--status", "1", "--all", "--limit", "2", "-o", "ndjson")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want one per ticket:\n%s", len(lines), stdout)
+	}
+	for _, line := range lines {
+		var ticket map[string]interface{}
+		decodeJSON(t, line, &ticket)
+		if ticket["ticket_id"] == nil {
+			t.Errorf("line is not a ticket: %s", line)
+		}
+	}
+	pages := 0
+	for _, call := range fake.Calls() {
+		if call == "GetTicketsByStatus" {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+}
+
+func TestJQAndFields(t *testing.T) {
+	stdout, stderr, code := runCLI(t, newFake(), "ticket", "get", "100042", "--jq", ".tickets[0].subject")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if stdout != "Printer on fire\n" {
+		t.Errorf("--jq printed %q", stdout)
+	}
+
+	stdout, stderr, code = runCLI(t, newFake(), "ticket", "search", "--status", "1", "-o", "json", "--fields", "number,subject")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var tickets []map[string]interface{}
+	decodeJSON(t, stdout, &tickets)
+	if len(tickets) != 1 || len(tickets[0]) != 2 || tickets[0]["number"] != "100042" {
+		t.Errorf("tickets = %v, want only number and subject", tickets)
+	}
+
+	if _, _, code := runCLI(t, newFake(), "ticket", "get", "100042", "--jq", ".tickets["); code == 0 {
+		t.Error("invalid --jq expression did not fail")
+	}
+	if _, _, code := runCLI(t, newFake(), "ticket", "get", "100042", "--jq", ".", "-o", "csv"); code == 0 {
+		t.Error("--jq with csv output did not fail")
+	}
+}
+
+func TestTicketSearchSortFilter(t *testing.T) {
+	fake := newFake()
+	fake.Tickets = append(fake.Tickets,
+		map[string]interface{}{"ticket_id": 43, "number": "100043", "subject": "Toner low", "status_id": 1, "priority_id": 1, "created": "2026-09-01 10:00:00"},
+		map[string]interface{}{"ticket_id": 44, "number": "100044", "subject": "New laptop", "status_id": 1, "priority_id": 3, "created": "2026-10-05 10:00:00"},
+	)
+
+	stdout, stderr, code := runCLI(t, fake, "ticket", "search", "--status", "1", "-o", "json",
+		"--filter", "priority_id>=3", "--filter", "created>2026-09-30", "--sort", "created", "--desc")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var data osticket.SimpleTicketResponse
+	decodeJSON(t, stdout, &data)
+	var numbers []string
+	for _, ticket := range data.Tickets {
+		numbers = append(numbers, ticket["number"].(string))
+	}
+	if strings.Join(numbers, ",") != "100044,100042" || data.Total != 2 {
+		t.Errorf("got %v (total %d), want 100044,100042", numbers, data.Total)
+	}
+
+	for _, args := range [][]string{{"--sort", "size"}, {"--filter", "priority"}, {"--desc"}} {
+		if _, _, code := runCLI(t, fake, append([]string{"ticket", "search", "--status", "1"}, args...)...); code != exitValidation {
+			t.Errorf("%v: exit %d, want %d", args, code, exitValidation)
+		}
+	}
+}
+
+func TestTicketWizard(t *testing.T) {
+	// An editor that leaves the body empty falls back to a prompt
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "true")
+	fake := newFake()
+	input := strings.Join([]string{"nobody@example.com", "ann@example.com", "", "", "3", "VPN drops", "Every hour or so", "y"}, "\n") + "\n"
+
+	stdout, stderr, code := runCLIInput(t, fake, input, "ticket", "new", "--interactive")
+	if code != 0 {
+		t.Fatalf("exit %d: %s\n%s", code, stderr, stdout)
+	}
+	if !strings.Contains(stdout, "No user found for nobody@example.com") || !strings.Contains(stdout, "Summary:") {
+		t.Errorf("prompts missing from output:\n%s", stdout)
+	}
+	if len(fake.Tickets) != 2 {
+		t.Fatalf("got %d tickets, want 2", len(fake.Tickets))
+	}
+	created := fake.Tickets[1]
+	if created["subject"] != "VPN drops" || created["user_id"] != 12 || created["priority_id"] != 3 || created["dept_id"] != 1 {
+		t.Errorf("created ticket = %v", created)
+	}
+}
+
+func TestTicketWizardEntitlement(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "true")
+	hook := filepath.Join(t.TempDir(), "entitlement")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho '{\"tier\": \"gold\"}'\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	fake := newFake()
+	fake.SLAs = append(fake.SLAs, osticket.SLA{ID: 2, Name: "Premium"})
+	input := strings.Join([]string{"ann@example.com", "", "", "3", "VPN drops", "Every hour or so", "y"}, "\n") + "\n"
+
+	// The SLA the tier maps to must exist, as it must for flags
+	if _, stderr, code := runCLI(t, fake, "config", "entitlement", "command", hook, "--tier-field", "support_tier", "--sla", "gold=Platinum"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if _, stderr, code := runCLIInput(t, fake, input, "ticket", "new", "--interactive"); code != exitValidation || len(fake.Tickets) != 1 {
+		t.Fatalf("unknown entitlement SLA: exit %d with %d tickets, want %d and 1: %s", code, len(fake.Tickets), exitValidation, stderr)
+	}
+
+	if _, stderr, code := runCLI(t, fake, "config", "entitlement", "command", hook, "--tier-field", "support_tier", "--sla", "gold=Premium"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	stdout, stderr, code := runCLIInput(t, fake, input, "ticket", "new", "--interactive")
+	if code != 0 {
+		t.Fatalf("exit %d: %s\n%s", code, stderr, stdout)
+	}
+	if !strings.Contains(stdout, "Entitlement: gold") || len(fake.Tickets) != 2 {
+		t.Fatalf("no entitlement in output:\n%s", stdout)
+	}
+	created := fake.Tickets[1]
+	if created["sla_id"] != 2 || created["support_tier"] != "gold" || created["priority_id"] != 3 {
+		t.Errorf("created ticket = %v", created)
+	}
+}
+
+func TestTicketReplyEdit(t *testing.T) {
+	// The editor types on the empty first line, above the quote
+	editor := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nsed '1s/^/Fixed, please confirm./' \"$1\" > \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+	fake := newFake()
+	fake.Threads[42] = []osticket.ThreadEntry{
+		{Type: osticket.ThreadMessage, Poster: "Ann Example", Body: "<p>It is <b>still</b> on fire</p>", Format: "html", Created: "2026-10-01 09:15:00"},
+		{Type: osticket.ThreadNote, Poster: "Bob", Body: "Internal only", Created: "2026-10-01 10:00:00"},
+	}
+
+	_, stderr, code := runCLI(t, fake, "ticket", "reply", "42", "--edit", "--staff-id", "1")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	want := "Fixed, please confirm.\n\nOn 2026-10-01 09:15:00, Ann Example wrote:\n> It is still on fire"
+	if got := fake.Threads[42][2].Body; got != want {
+		t.Errorf("reply body = %q, want %q", got, want)
+	}
+
+	if _, _, code := runCLI(t, fake, "ticket", "reply", "42", "--staff-id", "1"); code != exitValidation {
+		t.Errorf("exit %d without --body or --edit, want %d", code, exitValidation)
+	}
+}
+
+func TestBodyFormat(t *testing.T) {
+	fake := newFake()
+	_, stderr, code := runCLI(t, fake, "ticket", "reply", "42", "--staff-id", "1", "--body-format", "markdown",
+		"--body", "**Fixed** in [v2.1](https://example.com/changelog)\n\n<script>alert(1)</script>")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	want := `<p><strong>Fixed</strong> in <a href="https://example.com/changelog" rel="nofollow">v2.1</a></p>`
+	if got := fake.Threads[42][0].Body; got != want {
+		t.Errorf("reply body = %q, want %q", got, want)
+	}
+
+	// HTML bodies read as text in the thread
+	stdout, stderr, code := runCLI(t, fake, "ticket", "thread", "42", "-o", "table")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Fixed in v2.1 (https://example.com/changelog)") || strings.Contains(stdout, "<p>") {
+		t.Errorf("thread does not show the reply as text:\n%s", stdout)
+	}
+
+	if _, _, code := runCLI(t, fake, "ticket", "reply", "42", "--staff-id", "1", "--body", "x", "--body-format", "rtf"); code != exitValidation {
+		t.Errorf("exit %d for an unknown format, want %d", code, exitValidation)
+	}
+}
+
+func TestTicketReplyCanned(t *testing.T) {
+	fake := newFake()
+	fake.Canned = []osticket.CannedResponse{
+		{ID: 3, Title: "Password reset", IsEnabled: true, Response: "<p>Hi %{recipient.name.first},</p><p>Ticket #%{ticket.number} is reset; allow %{eta}.</p><p>%{staff.name}</p>"},
+		{ID: 4, Title: "Old greeting", Response: "Hello"},
+	}
+
+	stdout, stderr, code := runCLI(t, fake, "canned", "list", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var list osticket.CannedData
+	decodeJSON(t, stdout, &list)
+	if list.Total != 1 || list.Responses[0].ID != 3 {
+		t.Errorf("canned list = %+v, want only the enabled response", list)
+	}
+
+	_, stderr, code = runCLI(t, fake, "ticket", "reply", "42", "--canned", "password RESET", "--staff-id", "1")
+	if code != exitValidation || !strings.Contains(stderr, "needs --var for eta") {
+		t.Errorf("exit %d without --var eta, want %d: %s", code, exitValidation, stderr)
+	}
+
+	_, stderr, code = runCLI(t, fake, "ticket", "reply", "42", "--canned", "3", "--var", "eta=<1 hour", "--staff-id", "1")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	want := "<p>Hi Ann,</p><p>Ticket #100042 is reset; allow &lt;1 hour.</p><p>Admin User</p>"
+	if got := fake.Threads[42][0].Body; got != want {
+		t.Errorf("reply body = %q, want %q", got, want)
+	}
+}
+
+func TestTaskCommands(t *testing.T) {
+	fake := newFake()
+
+	stdout, stderr, code := runCLI(t, fake, "task", "create", "42", "--title", "Order fuser", "--description", "**Bay 3**", "--body-format", "markdown", "--due", "2026-11-02", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var created struct {
+		TaskID int `json:"task_id"`
+	}
+	decodeJSON(t, stdout, &created)
+	if created.TaskID != 1 || len(fake.Tasks) != 1 {
+		t.Fatalf("task create = %+v, tasks %+v", created, fake.Tasks)
+	}
+	if task := fake.Tasks[0]; task.Description != "<p><strong>Bay 3</strong></p>" || task.DueDate != "2026-11-02 00:00:00" {
+		t.Errorf("created task = %+v", task)
+	}
+
+	_, stderr, code = runCLI(t, fake, "task", "create", "42", "--title", "Late", "--due", "next week")
+	if code != exitValidation || !strings.Contains(stderr, "--due") {
+		t.Errorf("exit %d with a bad --due, want %d: %s", code, exitValidation, stderr)
+	}
+
+	if _, stderr, code = runCLI(t, fake, "task", "assign", "1", "--team", "Level I Support"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if _, stderr, code = runCLI(t, fake, "task", "close", "1", "--staff-id", "1"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+
+	stdout, _, _ = runCLI(t, fake, "task", "list", "42", "-o", "json")
+	var list osticket.TaskData
+	decodeJSON(t, stdout, &list)
+	if list.Total != 0 {
+		t.Errorf("task list = %+v, want no open tasks", list)
+	}
+	stdout, _, _ = runCLI(t, fake, "task", "list", "42", "--all", "-o", "json")
+	decodeJSON(t, stdout, &list)
+	if list.Total != 1 || list.Tasks[0].IsOpen || list.Tasks[0].TeamID != 1 {
+		t.Errorf("task list --all = %+v, want the closed task assigned to team 1", list)
+	}
+
+	stdout, _, _ = runCLI(t, fake, "task", "list", "42", "--all")
+	if !strings.Contains(stdout, "Level I Support") || !strings.Contains(stdout, "Closed") {
+		t.Errorf("task table missing team or status:\n%s", stdout)
+	}
+}
+
+func TestExportImportTickets(t *testing.T) {
+	source := newFake()
+	source.Users = append(source.Users, osticket.User{UserID: 13, Name: "Bob Example", Email: "bob@example.com"})
+	source.Tickets[0]["status_id"] = 2
+	source.Tickets = append(source.Tickets, map[string]interface{}{
+		"ticket_id": 43, "number": "100043", "subject": "Toner low", "user_id": 13,
+		"status_id": 1, "priority_id": 2, "dept_id": 1, "created": "2026-10-02 10:00:00",
+	})
+	source.Threads[42] = []osticket.ThreadEntry{
+		{ID: 1, Type: "M", Poster: "Ann Example", Body: "It is smoking", Created: "2026-10-01 09:15:00"},
+		{ID: 2, Type: "R", Poster: "Admin User", Body: "Unplug it", Created: "2026-10-01 09:20:00"},
+		{ID: 3, Type: "N", Poster: "Admin User", Title: "Vendor", Body: "Case 77", Created: "2026-10-01 09:30:00"},
+	}
+	source.Threads[43] = []osticket.ThreadEntry{{ID: 1, Type: "M", Poster: "Bob Example", Body: "Please order toner"}}
+
+	dump := filepath.Join(t.TempDir(), "dump.jsonl")
+	if _, stderr, code := runCLI(t, source, "export", "tickets", "--out", dump); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	stdout, stderr, code := runCLI(t, source, "export", "tickets", "--out", dump, "-o", "json")
+	if code != 0 || !strings.Contains(stderr, "Resuming: 2 ticket(s)") || !strings.Contains(stdout, `"exported": 0`) {
+		t.Errorf("second export (exit %d) did not resume:\n%s%s", code, stdout, stderr)
+	}
+
+	target := osticketest.NewFake()
+	target.Users = []osticket.User{{UserID: 5, Name: "Bob Example", Email: "bob@example.com"}}
+	target.Errors = map[string]error{"AddInternalNote": &osticket.Error{Kind: osticket.ErrServer, Message: "down"}}
+	if _, _, code := runCLI(t, target, "import", "tickets", dump, "--staff-id", "1"); code != 1 {
+		t.Fatalf("exit %d with failing notes, want 1", code)
+	}
+	target.Errors = nil
+	if _, stderr, code := runCLI(t, target, "import", "tickets", dump, "--staff-id", "1"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+
+	if len(target.Tickets) != 2 || len(target.Users) != 2 {
+		t.Fatalf("target has %d tickets and %d users, want 2 of each", len(target.Tickets), len(target.Users))
+	}
+	var ids importMap
+	data, err := os.ReadFile(strings.TrimSuffix(dump, ".jsonl") + ".map.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		t.Fatal(err)
+	}
+	if ids.Users[13] != 5 || ids.Tickets[42] == nil || !ids.Tickets[42].Done {
+		t.Fatalf("mapping = %s", data)
+	}
+	newID := ids.Tickets[42].ID
+	thread := target.Threads[newID]
+	if len(thread) != 3 || thread[0].Body != "It is smoking" || thread[1].Type != "N" ||
+		thread[1].Title != "Response from Admin User, 2026-10-01 09:20:00" || thread[2].Body != "Vendor\n\nCase 77" {
+		t.Errorf("imported thread = %+v", thread)
+	}
+	for _, ticket := range target.Tickets {
+		if mapInt(ticket, "ticket_id") == newID && mapInt(ticket, "status_id") != 2 {
+			t.Errorf("imported ticket status = %v, want 2 (Resolved)", ticket["status_id"])
+		}
+	}
+}
+
+func TestUserHold(t *testing.T) {
+	fake := newFake()
+	if _, stderr, code := runCLI(t, fake, "hold", "add", "--user", "ann@example.com", "--reason", "Case 2026-117"); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+
+	_, stderr, code := runCLI(t, fake, "ticket", "delete", "42", "--yes")
+	if code != 1 || !strings.Contains(stderr, "under legal hold (user ann@example.com: Case 2026-117)") {
+		t.Errorf("delete of a held ticket: exit %d: %s", code, stderr)
+	}
+	if status := mapInt(fake.Tickets[0], "status_id"); status == osticket.StatusDeleted {
+		t.Errorf("held ticket was deleted")
+	}
+
+	policy := filepath.Join(t.TempDir(), "retention.yaml")
+	if err := os.WriteFile(policy, []byte("rules:\n  - status: 1\n    older_than: 1d\n    action: archive\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, code := runCLI(t, fake, "retention", "apply", "--policy", policy, "--dry-run", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	var plan struct {
+		Items []struct {
+			TicketID int    `json:"ticket_id"`
+			Hold     string `json:"hold"`
+		} `json:"items"`
+	}
+	decodeJSON(t, stdout, &plan)
+	if len(plan.Items) != 1 || plan.Items[0].TicketID != 42 || plan.Items[0].Hold != "user ann@example.com" {
+		t.Errorf("plan items = %+v", plan.Items)
+	}
+}
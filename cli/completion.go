@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ==================== SHELL COMPLETION ====================
+
+// completionTimeout bounds the server lookups made while completing, so a
+// slow or unreachable server does not hang the shell
+const completionTimeout = 5 * time.Second
+
+// completionFuncs complete flag values from the cached server lists. Each
+// flag is completed on every command that defines it.
+var completionFuncs = map[string]func(app *App, cmd *cobra.Command, flag *pflag.Flag) []string{
+	"dept":     (*App).completeIDFlag,
+	"topic":    (*App).completeIDFlag,
+	"sla":      (*App).completeIDFlag,
+	"team":     (*App).completeIDFlag,
+	"status":   (*App).completeIDFlag,
+	"staff-id": (*App).completeStaff,
+	"username": (*App).completeStaff,
+	"template": (*App).completeTemplate,
+}
+
+// registerCompletions adds dynamic value completion to the flags of cmd
+// and its subcommands
+func (app *App) registerCompletions(cmd *cobra.Command) {
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		complete, ok := completionFuncs[f.Name]
+		if !ok {
+			return
+		}
+		cmd.RegisterFlagCompletionFunc(f.Name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return complete(app, cmd, f), cobra.ShellCompDirectiveNoFileComp
+		})
+	})
+	for _, sub := range cmd.Commands() {
+		app.registerCompletions(sub)
+	}
+}
+
+// completeIDFlag completes --dept, --topic, --sla, --team and --status.
+// Flags taking a name are completed with names, numeric flags with IDs
+// described by their name.
+func (app *App) completeIDFlag(cmd *cobra.Command, flag *pflag.Flag) []string {
+	var choices []validate.Choice
+	if flag.Name == "status" {
+		choices = statusChoices
+		if cmd.HasParent() && cmd.Parent().Name() == "user" {
+			choices = userStatusChoices
+		}
+	} else {
+		client := app.completionClient(cmd)
+		if client == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(completionContext(cmd), completionTimeout)
+		defer cancel()
+		var err error
+		if choices, err = idChoices(ctx, client, flag.Name); err != nil {
+			return nil
+		}
+	}
+
+	byName := flag.Value.Type() == "string"
+	values := make([]string, 0, len(choices))
+	for _, c := range choices {
+		if byName {
+			values = append(values, strings.ToLower(c.Name))
+		} else {
+			values = append(values, fmt.Sprintf("%d\t%s", c.ID, c.Name))
+		}
+	}
+	return values
+}
+
+// completeStaff completes --staff-id with IDs described by the agent's
+// name and --username with usernames
+func (app *App) completeStaff(cmd *cobra.Command, flag *pflag.Flag) []string {
+	client := app.completionClient(cmd)
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(completionContext(cmd), completionTimeout)
+	defer cancel()
+	data, err := client.GetStaffList(ctx)
+	if err != nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(data.Staff))
+	for _, s := range data.Staff {
+		name := strings.TrimSpace(s.Firstname + " " + s.Lastname)
+		if flag.Name == "username" {
+			values = append(values, s.Username+"\t"+name)
+		} else {
+			values = append(values, strconv.Itoa(s.StaffID)+"\t"+s.Username+" ("+name+")")
+		}
+	}
+	return values
+}
+
+// completionClient builds an API client for completing a value. Completion
+// skips the root's pre-run, so the config is loaded here; any problem
+// means no suggestions rather than an error in the shell.
+func (app *App) completionClient(cmd *cobra.Command) (client osticket.OSTicketAPI) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(exitPanic); !ok {
+				panic(r)
+			}
+			client = nil
+		}
+	}()
+
+	// Warnings would end up mixed into the shell's suggestions
+	app.Err = io.Discard
+	if err := config.Load(); err != nil {
+		return nil
+	}
+	if err := config.SetProfile(app.profile); err != nil {
+		return nil
+	}
+	overrides, err := app.credentialOverrides(cmd)
+	if err != nil {
+		return nil
+	}
+	config.SetOverrides(overrides)
+
+	client = app.client(completionContext(cmd))
+	if c, ok := client.(*osticket.Client); ok {
+		c.Retries = 0
+	}
+	return client
+}
+
+// completionContext returns the command's context, which is unset when
+// cobra completes flags of a command it has not executed
+func completionContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
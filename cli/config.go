@@ -0,0 +1,701 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/credentials"
+	"github.com/osticket-cli-go/internal/entitlement"
+	"github.com/osticket-cli-go/internal/mail"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== CONFIG COMMANDS ====================
+
+func (app *App) configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage CLI configuration",
+	}
+
+	// config set
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set configuration values",
+		Example: `  osticket config set --url https://helpdesk.example.com/ost_wbs/ --key YOUR_API_KEY
+  osticket config set --profile staging --url https://staging.example.com/ost_wbs/ --key STAGING_KEY
+  osticket config set --retries 4 --retry-wait 1s --cache-ttl 30m
+  osticket config set --timeout 3m --connect-timeout 5s`,
+		Run: func(cmd *cobra.Command, args []string) {
+			url, _ := cmd.Flags().GetString("url")
+			key, _ := cmd.Flags().GetString("key")
+			useKeyring, _ := cmd.Flags().GetBool("keyring")
+			maxAttachment, _ := cmd.Flags().GetString("max-attachment-size")
+			retries, _ := cmd.Flags().GetInt("retries")
+			retryWait, _ := cmd.Flags().GetString("retry-wait")
+			ageThresholds, _ := cmd.Flags().GetStringArray("age-threshold")
+			cacheTTL, _ := cmd.Flags().GetString("cache-ttl")
+			rateLimit, _ := cmd.Flags().GetFloat64("rate-limit")
+			warnSlow, _ := cmd.Flags().GetString("warn-slow")
+			timeout, _ := cmd.Flags().GetString("timeout")
+			connectTimeout, _ := cmd.Flags().GetString("connect-timeout")
+			readTimeout, _ := cmd.Flags().GetString("read-timeout")
+			logFile, _ := cmd.Flags().GetString("log-file")
+			proxy, _ := cmd.Flags().GetString("proxy")
+			caCert, _ := cmd.Flags().GetString("ca-cert")
+			clientCert, _ := cmd.Flags().GetString("client-cert")
+			clientKey, _ := cmd.Flags().GetString("client-key")
+			insecure, _ := cmd.Flags().GetBool("insecure")
+			connChanged := false
+			for _, name := range []string{"proxy", "ca-cert", "client-cert", "client-key", "insecure"} {
+				connChanged = connChanged || cmd.Flags().Changed(name)
+			}
+
+			if url != "" {
+				if err := config.SetBaseURL(url); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting URL:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Base URL set"))
+			}
+			if key != "" {
+				store, err := config.SetAPIKey(key, useKeyring)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error setting API key:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ API key stored in "+store))
+			}
+			if maxAttachment != "" {
+				if err := config.SetMaxAttachmentSize(maxAttachment); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting attachment size limit:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Attachment size limit set"))
+			}
+			if cmd.Flags().Changed("retries") {
+				if err := config.SetRetries(retries); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting retries:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Retries set"))
+			}
+			if retryWait != "" {
+				if err := config.SetRetryWait(retryWait); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting retry wait:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Retry wait set"))
+			}
+			if cacheTTL != "" {
+				if err := config.SetCacheTTL(cacheTTL); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting cache TTL:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Cache TTL set"))
+			}
+			if cmd.Flags().Changed("rate-limit") {
+				if err := config.SetRateLimit(rateLimit); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting rate limit:"), err)
+					exit(1)
+				}
+				if rateLimit == 0 {
+					fmt.Fprintln(app.Out, green("✓ Rate limit removed"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ Rate limit set"))
+				}
+			}
+			if warnSlow != "" {
+				if err := config.SetWarnSlow(warnSlow); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting slow call warning:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Slow call warning set"))
+			}
+			if timeout != "" {
+				if err := config.SetTimeout(timeout); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting timeout:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Request timeout set"))
+			}
+			if connectTimeout != "" {
+				if err := config.SetConnectTimeout(connectTimeout); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting connect timeout:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Connect timeout set"))
+			}
+			if readTimeout != "" {
+				if err := config.SetReadTimeout(readTimeout); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting read timeout:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Read timeout set"))
+			}
+			if cmd.Flags().Changed("log-file") {
+				if err := config.SetLogFile(logFile); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting log file:"), err)
+					exit(1)
+				}
+				if logFile == "" {
+					fmt.Fprintln(app.Out, green("✓ Log file removed; logging to stderr"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ Log file set"))
+				}
+			}
+			for _, pair := range ageThresholds {
+				priority, age, ok := strings.Cut(pair, "=")
+				if !ok {
+					fmt.Fprintln(app.Err, red("Error setting age threshold:"), fmt.Sprintf("invalid --age-threshold %q: use priority=duration", pair))
+					exit(1)
+				}
+				if err := config.SetAgeThreshold(priority, age); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting age threshold:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green(fmt.Sprintf("✓ Age threshold for %s set", priority)))
+			}
+			if cmd.Flags().Changed("proxy") {
+				if err := config.SetProxy(proxy); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting proxy:"), err)
+					exit(1)
+				}
+				if proxy == "" {
+					fmt.Fprintln(app.Out, green("✓ Proxy removed"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ Proxy set"))
+				}
+			}
+			if cmd.Flags().Changed("ca-cert") {
+				if err := config.SetCACert(caCert); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting CA bundle:"), err)
+					exit(1)
+				}
+				if caCert == "" {
+					fmt.Fprintln(app.Out, green("✓ CA bundle removed"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ CA bundle set"))
+				}
+			}
+			if cmd.Flags().Changed("client-cert") || cmd.Flags().Changed("client-key") {
+				if (clientCert == "") != (clientKey == "") {
+					fmt.Fprintln(app.Err, red("Error setting client certificate:"), "--client-cert and --client-key must be given together")
+					exit(1)
+				}
+				if err := config.SetClientCert(clientCert, clientKey); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting client certificate:"), err)
+					exit(1)
+				}
+				if clientCert == "" {
+					fmt.Fprintln(app.Out, green("✓ Client certificate removed"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ Client certificate set"))
+				}
+			}
+			if cmd.Flags().Changed("insecure") {
+				if err := config.SetInsecure(insecure); err != nil {
+					fmt.Fprintln(app.Err, red("Error setting TLS verification:"), err)
+					exit(1)
+				}
+				if insecure {
+					fmt.Fprintln(app.Out, yellow("✓ TLS certificate verification disabled"))
+				} else {
+					fmt.Fprintln(app.Out, green("✓ TLS certificate verification enabled"))
+				}
+			}
+			if url == "" && key == "" && maxAttachment == "" && retryWait == "" && cacheTTL == "" && !cmd.Flags().Changed("retries") && !cmd.Flags().Changed("rate-limit") && warnSlow == "" && timeout == "" && connectTimeout == "" && readTimeout == "" && !cmd.Flags().Changed("log-file") && len(ageThresholds) == 0 && !connChanged {
+				fmt.Fprintln(app.Out, yellow("Please provide --url, --key, --max-attachment-size, --retries, --retry-wait, --rate-limit, --warn-slow, --timeout, --connect-timeout, --read-timeout, --cache-ttl, --log-file, --age-threshold or a connection flag (--proxy, --ca-cert, --client-cert/--client-key, --insecure)"))
+			}
+		},
+	}
+	setCmd.Flags().String("url", "", "osTicket API base URL")
+	setCmd.Flags().String("key", "", "osTicket API key")
+	setCmd.Flags().String("max-attachment-size", "", "Attachment upload limit (e.g. 10MB)")
+	setCmd.Flags().Int("retries", 2, "Retries for failed requests")
+	setCmd.Flags().String("retry-wait", "", "Base delay between retries (e.g. 500ms)")
+	setCmd.Flags().Float64("rate-limit", 0, "Maximum requests per second, e.g. 5 or 0.5 (0 for no limit)")
+	setCmd.Flags().String("warn-slow", "", "Warn about API calls slower than this (e.g. 2s, 0 to disable)")
+	setCmd.Flags().String("timeout", "", "Time limit of each API request (e.g. 2m, 0 for none)")
+	setCmd.Flags().String("connect-timeout", "", "Time limit for connecting to the server (e.g. 5s)")
+	setCmd.Flags().String("read-timeout", "", "Time limit for the server to start answering (e.g. 90s, 0 for none)")
+	setCmd.Flags().String("log-file", "", "Log diagnostics to this file as JSON lines instead of stderr (empty to remove)")
+	setCmd.Flags().String("cache-ttl", "", "How long department, topic, SLA and staff lists are cached (e.g. 30m, 0 to disable)")
+	setCmd.Flags().StringArray("age-threshold", nil, "Age after which tickets of a priority are late, as priority=duration (e.g. emergency=30m, repeatable)")
+	setCmd.Flags().String("proxy", "", "Proxy URL for this profile (http, https or socks5; empty to use HTTPS_PROXY)")
+	setCmd.Flags().String("ca-cert", "", "PEM CA bundle trusted for this profile (empty to remove)")
+	setCmd.Flags().String("client-cert", "", "PEM client certificate for this profile (empty to remove)")
+	setCmd.Flags().String("client-key", "", "PEM key of the client certificate")
+	setCmd.Flags().Bool("insecure", false, "Skip TLS certificate verification for this profile (unsafe)")
+	setCmd.Flags().Bool("keyring", false, "Require the OS keyring instead of falling back to the encrypted file")
+	cmd.AddCommand(setCmd)
+
+	// config show
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show current configuration",
+		Example: `  osticket config show
+  osticket --profile staging config show`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(app.Out, "\n"+cyan("Configuration:"))
+			profile := config.GetProfile()
+			if profile == "" {
+				profile = "(default)"
+			}
+			fmt.Fprintf(app.Out, "  Profile: %s\n", profile)
+			url := config.GetBaseURL()
+			key := config.GetAPIKey()
+			urlSource, keySource := config.GetConfigSource()
+
+			urlDisplay := url
+			if url == "" {
+				urlDisplay = "(not set)"
+			}
+			keyDisplay := key
+			if config.UsesCredentialProvider() {
+				keyDisplay = "(resolved at runtime)"
+			} else if key == "" {
+				keyDisplay = "(not set)"
+			} else if len(key) > 12 {
+				keyDisplay = key[:8] + "..." + key[len(key)-4:]
+			}
+			fmt.Fprintf(app.Out, "  Base URL: %s [%s]\n", urlDisplay, urlSource)
+			fmt.Fprintf(app.Out, "  API Key:  %s [%s]\n", keyDisplay, keySource)
+			if session := config.GetSessionSpec(); session.LoginURL != "" {
+				fmt.Fprintf(app.Out, "  Session login: %s at %s [%s]\n", session.Username, session.LoginURL, session.Store)
+			}
+			fmt.Fprintf(app.Out, "  Max attachment size: %d bytes\n", config.GetMaxAttachmentSize())
+			fmt.Fprintf(app.Out, "  Retries: %d (base wait %s)\n", config.GetRetries(), config.GetRetryWait())
+			if rate := config.GetRateLimit(); rate > 0 {
+				fmt.Fprintf(app.Out, "  Rate limit: %g requests/s\n", rate)
+			} else {
+				fmt.Fprintln(app.Out, "  Rate limit: none")
+			}
+			fmt.Fprintf(app.Out, "  Timeouts: %s (connect %s, read %s)\n", timeoutText(config.GetTimeout(), "none"), timeoutText(config.GetConnectTimeout(), "default"), timeoutText(config.GetReadTimeout(), "none"))
+			if budget := config.GetWarnSlow(); budget > 0 {
+				fmt.Fprintf(app.Out, "  Slow call warning: over %s\n", budget)
+			}
+			fmt.Fprintf(app.Out, "  Cache TTL: %s\n", config.GetCacheTTL())
+			if logFile := config.GetLogFile(); logFile != "" {
+				fmt.Fprintf(app.Out, "  Log file: %s\n", logFile)
+			}
+			conn := config.GetConnection()
+			if conn.Proxy != "" {
+				fmt.Fprintf(app.Out, "  Proxy: %s\n", conn.Proxy)
+			}
+			if conn.CAFile != "" {
+				fmt.Fprintf(app.Out, "  CA bundle: %s\n", conn.CAFile)
+			}
+			if conn.CertFile != "" {
+				fmt.Fprintf(app.Out, "  Client certificate: %s (key %s)\n", conn.CertFile, conn.KeyFile)
+			}
+			if conn.Insecure {
+				fmt.Fprintf(app.Out, "  TLS verification: %s\n", yellow("disabled"))
+			}
+			if ages, err := config.GetAgeThresholds(); err != nil {
+				fmt.Fprintf(app.Out, "  Age thresholds: %s\n", yellow(err.Error()))
+			} else {
+				fmt.Fprintf(app.Out, "  Age thresholds: low %s, normal %s, high %s, emergency %s\n",
+					ages.Late(thresholds.Low), ages.Late(thresholds.Normal), ages.Late(thresholds.High), ages.Late(thresholds.Emergency))
+			}
+			if hook := config.GetEntitlementSpec(); hook.Kind != "" {
+				target := hook.Command
+				if hook.Kind == entitlement.KindHTTP {
+					target = hook.URL
+				}
+				fmt.Fprintf(app.Out, "  Entitlement hook: %s %s\n", hook.Kind, target)
+			}
+			fmt.Fprintf(app.Out, "  Config file: %s\n", config.GetConfigPath())
+			fmt.Fprintf(app.Out, "  Cache dir:   %s\n", config.GetCacheDir())
+			fmt.Fprintf(app.Out, "  State dir:   %s\n", config.GetStateDir())
+			if config.NeedsMigration() {
+				fmt.Fprintln(app.Out, yellow("  Using legacy ~/.osticket-cli; run 'osticket config migrate' to move to XDG directories"))
+			}
+			if len(config.PlaintextAPIKeys()) > 0 {
+				fmt.Fprintln(app.Out, yellow("  API keys stored in plaintext; run 'osticket config migrate' to encrypt them"))
+			}
+			fmt.Fprintf(app.Out, "\n  Environment variables:\n")
+			fmt.Fprintf(app.Out, "    %s\n", config.EnvBaseURL)
+			fmt.Fprintf(app.Out, "    %s\n", config.EnvAPIKey)
+			fmt.Fprintf(app.Out, "    %s\n", config.EnvProfile)
+			fmt.Fprintf(app.Out, "    %s\n", config.EnvDebug)
+			fmt.Fprintf(app.Out, "    %s\n", config.EnvConfigDir)
+			fmt.Fprintf(app.Out, "    %s, %s, %s\n\n", config.EnvXDGConfigHome, config.EnvXDGCacheHome, config.EnvXDGStateHome)
+		},
+	}
+	cmd.AddCommand(showCmd)
+
+	// config test
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Check that the base URL is the API plugin's endpoint",
+		Long: `Send a read-only call to the profile's base URL and check that the API
+plugin answers it. When the URL answers with something else, such as the
+web UI's HTML pages, the site is searched for the endpoint as by
+'osticket init'; --fix stores the endpoint found.`,
+		Example: `  osticket config test
+  osticket --profile staging config test --fix
+  osticket config test --path /helpdesk/api/wbs/`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "config test cannot run in offline mode")
+				exit(1)
+			}
+			paths, _ := cmd.Flags().GetStringArray("path")
+			fix, _ := cmd.Flags().GetBool("fix")
+			if err := app.testEndpoint(cmd.Context(), paths, fix); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+		},
+	}
+	testCmd.Flags().Bool("fix", false, "Store the endpoint found when the base URL is not the API's")
+	testCmd.Flags().StringArray("path", nil, "Custom endpoint path to try first (repeatable)")
+	cmd.AddCommand(testCmd)
+
+	// config profiles
+	profilesCmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "List configured profiles",
+		Run: func(cmd *cobra.Command, args []string) {
+			active := config.GetProfile()
+			marker := func(name string) string {
+				if name == active {
+					return green("* ")
+				}
+				return "  "
+			}
+
+			fmt.Fprintln(app.Out, marker("")+"(default)")
+			for _, name := range config.ListProfiles() {
+				fmt.Fprintln(app.Out, marker(name)+name)
+			}
+		},
+	}
+	cmd.AddCommand(profilesCmd)
+
+	// config credentials
+	credsCmd := &cobra.Command{
+		Use:   "credentials <none|command|vault|aws> [command]",
+		Short: "Fetch the API key from a credential provider instead of storing it",
+		Long: `Fetch the API key from an external secret store each time a command runs,
+so it never lives in a file.
+
+  osticket config credentials command "pass show osticket"
+  osticket config credentials vault --path secret/data/osticket --field api_key
+  osticket config credentials aws --secret-id osticket/api --region us-east-1
+  osticket config credentials none`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			spec := credentials.Spec{Provider: args[0]}
+			if spec.Provider == "none" {
+				spec.Provider = ""
+			}
+			if len(args) == 2 {
+				if spec.Provider != credentials.ProviderCommand {
+					fmt.Fprintln(app.Err, red("Error:"), "only the command provider takes an argument")
+					exit(1)
+				}
+				spec.Command = args[1]
+			}
+			spec.Addr, _ = cmd.Flags().GetString("addr")
+			spec.Path, _ = cmd.Flags().GetString("path")
+			spec.Field, _ = cmd.Flags().GetString("field")
+			spec.SecretID, _ = cmd.Flags().GetString("secret-id")
+			spec.Region, _ = cmd.Flags().GetString("region")
+
+			if err := config.SetCredentialSpec(spec); err != nil {
+				fmt.Fprintln(app.Err, red("Error setting credential provider:"), err)
+				exit(1)
+			}
+			if spec.Provider == "" {
+				fmt.Fprintln(app.Out, green("✓ Credential provider removed"))
+				return
+			}
+			fmt.Fprintln(app.Out, green("✓ Credential provider set to "+spec.Provider))
+		},
+	}
+	credsCmd.Flags().String("addr", "", "Vault address (default $VAULT_ADDR)")
+	credsCmd.Flags().String("path", "", "Vault secret path (e.g. secret/data/osticket)")
+	credsCmd.Flags().String("field", "", "Field holding the key in a Vault or JSON AWS secret")
+	credsCmd.Flags().String("secret-id", "", "AWS Secrets Manager secret name or ARN")
+	credsCmd.Flags().String("region", "", "AWS region")
+	cmd.AddCommand(credsCmd)
+
+	// config entitlement
+	entitlementCmd := &cobra.Command{
+		Use:   "entitlement <none|command|http> [command|url]",
+		Short: "Check the requester's support contract when creating tickets",
+		Long: `Set a hook that ticket create asks about the requester's support contract.
+A command runs through the shell with the requester's email as its last
+argument and in $OSTICKET_EMAIL; a URL is fetched with GET and an email
+query parameter.
+Either answers with JSON:
+
+  {"tier": "gold", "expires": "2027-03-31", "fields": {"contract": "C-1042"}}
+
+The tier and expiry date are stamped into the custom form fields named by
+--tier-field and --expiry-field, and "fields" are set as they are; values
+given with --field win. --sla maps a tier to the SLA (ID or name) the
+ticket gets unless --sla is given to ticket create; map "expired" to pick
+the SLA of contracts past their expiry date. Empty output, {}, or HTTP
+404 mean no contract.`,
+		Example: `  osticket config entitlement command "/usr/local/bin/entitlement-lookup" --tier-field support_tier --expiry-field contract_expiry --sla gold=Premium --sla expired=Basic
+  osticket config entitlement http https://crm.example.com/entitlements --tier-field support_tier --sla gold=2 --sla silver=3
+  osticket config entitlement none`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			spec := entitlement.Spec{Kind: args[0]}
+			if spec.Kind == "none" {
+				spec.Kind = ""
+			}
+			if len(args) == 2 {
+				switch spec.Kind {
+				case entitlement.KindCommand:
+					spec.Command = args[1]
+				case entitlement.KindHTTP:
+					spec.URL = args[1]
+				default:
+					fmt.Fprintln(app.Err, red("Error:"), "only the command and http hooks take an argument")
+					exit(exitValidation)
+				}
+			}
+			spec.TierField, _ = cmd.Flags().GetString("tier-field")
+			spec.ExpiryField, _ = cmd.Flags().GetString("expiry-field")
+			slas, _ := cmd.Flags().GetStringArray("sla")
+			for _, pair := range slas {
+				tier, sla, ok := strings.Cut(pair, "=")
+				if !ok || tier == "" || sla == "" {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --sla %q: use tier=SLA", pair))
+					exit(exitValidation)
+				}
+				if spec.SLAs == nil {
+					spec.SLAs = map[string]string{}
+				}
+				spec.SLAs[tier] = sla
+			}
+
+			if err := config.SetEntitlementSpec(spec); err != nil {
+				fmt.Fprintln(app.Err, red("Error setting entitlement hook:"), err)
+				exit(exitValidation)
+			}
+			if spec.Kind == "" {
+				fmt.Fprintln(app.Out, green("✓ Entitlement hook removed"))
+				return
+			}
+			fmt.Fprintln(app.Out, green("✓ Entitlement hook set to "+spec.Kind))
+		},
+	}
+	entitlementCmd.Flags().String("tier-field", "", "Custom form field that receives the support tier")
+	entitlementCmd.Flags().String("expiry-field", "", "Custom form field that receives the contract expiry date")
+	entitlementCmd.Flags().StringArray("sla", nil, "SLA for a tier as tier=SLA ID or name (repeatable; tier \"expired\" for lapsed contracts)")
+	cmd.AddCommand(entitlementCmd)
+
+	// config session
+	sessionCmd := &cobra.Command{
+		Use:   "session [none]",
+		Short: "Log in to an API gateway that issues session tokens",
+		Long: `Some gateways in front of osTicket want a short-lived session token,
+issued by a login call, on every request. With a session login set, the CLI
+posts {"username", "password"} to the login URL, sends the returned token
+with each request ("Authorization: Bearer <token>" unless --header names
+another header) and logs in again when the token expires or is rejected
+with HTTP 401. The password and the cached token are kept in the OS keyring
+or the encrypted secrets file. The API key is still sent when one is set.
+
+  osticket config session --login-url https://gw.example.com/auth/login --username noc-bot --password-stdin < pw.txt
+  osticket config session none`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				if args[0] != "none" {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("unknown argument %q: use none to remove the session login", args[0]))
+					exit(1)
+				}
+				if err := config.ClearSession(); err != nil {
+					fmt.Fprintln(app.Err, red("Error removing session login:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Session login removed"))
+				return
+			}
+
+			var spec config.SessionSpec
+			spec.LoginURL, _ = cmd.Flags().GetString("login-url")
+			spec.Username, _ = cmd.Flags().GetString("username")
+			spec.TokenField, _ = cmd.Flags().GetString("token-field")
+			spec.Header, _ = cmd.Flags().GetString("header")
+			password, _ := cmd.Flags().GetString("password")
+			if stdin, _ := cmd.Flags().GetBool("password-stdin"); stdin {
+				data, err := io.ReadAll(app.In)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error reading password:"), err)
+					exit(1)
+				}
+				password = strings.TrimRight(string(data), "\r\n")
+			}
+
+			store, err := config.SetSession(spec, password)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error setting session login:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Session login set; password stored in "+store))
+		},
+	}
+	sessionCmd.Flags().String("login-url", "", "URL of the gateway's login call")
+	sessionCmd.Flags().String("username", "", "Login username")
+	sessionCmd.Flags().String("password", "", "Login password (prefer --password-stdin)")
+	sessionCmd.Flags().Bool("password-stdin", false, "Read the login password from stdin")
+	sessionCmd.Flags().String("token-field", osticket.DefaultTokenField, "Field of the login response holding the token")
+	sessionCmd.Flags().String("header", osticket.DefaultSessionHeader, "Header the token is sent in")
+	sessionCmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	cmd.AddCommand(sessionCmd)
+
+	// config smtp
+	smtpCmd := &cobra.Command{
+		Use:   "smtp [name [none]]",
+		Short: "Add, list or remove SMTP profiles for sending mail",
+		Long: `SMTP profiles name the mail servers commands such as survey send mail
+through (--smtp-profile). They are shared by every configuration profile.
+The password is kept in the OS keyring or the encrypted secrets file.
+Without arguments the SMTP profiles are listed.
+
+  osticket config smtp corp --host smtp.example.com --port 587 --from "Support <support@example.com>" --username mailer --password-stdin < pw.txt
+  osticket config smtp relay --host localhost --port 25 --security none --from support@example.com
+  osticket config smtp corp none`,
+		Args: cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				names := config.ListSMTPProfiles()
+				if len(names) == 0 {
+					fmt.Fprintln(app.Out, yellow("No SMTP profiles"))
+					return
+				}
+				for _, name := range names {
+					p, _ := config.GetSMTPProfile(name)
+					fmt.Fprintf(app.Out, "  %s: %s via %s:%d\n", name, p.From, p.Host, p.Port)
+				}
+				return
+			}
+
+			name := args[0]
+			if len(args) == 2 {
+				if args[1] != "none" {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("unknown argument %q: use none to remove the SMTP profile", args[1]))
+					exit(1)
+				}
+				if err := config.RemoveSMTPProfile(name); err != nil {
+					fmt.Fprintln(app.Err, red("Error removing SMTP profile:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ SMTP profile "+name+" removed"))
+				return
+			}
+
+			var p config.SMTPProfile
+			p.Host, _ = cmd.Flags().GetString("host")
+			p.Port, _ = cmd.Flags().GetInt("port")
+			p.From, _ = cmd.Flags().GetString("from")
+			p.Username, _ = cmd.Flags().GetString("username")
+			p.Security, _ = cmd.Flags().GetString("security")
+			password, _ := cmd.Flags().GetString("password")
+			if stdin, _ := cmd.Flags().GetBool("password-stdin"); stdin {
+				data, err := io.ReadAll(app.In)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error reading password:"), err)
+					exit(1)
+				}
+				password = strings.TrimRight(string(data), "\r\n")
+			}
+
+			store, err := config.SetSMTPProfile(name, p, password)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error setting SMTP profile:"), err)
+				exit(1)
+			}
+			if store != "" {
+				fmt.Fprintln(app.Out, green("✓ SMTP profile "+name+" set; password stored in "+store))
+				return
+			}
+			fmt.Fprintln(app.Out, green("✓ SMTP profile "+name+" set"))
+		},
+	}
+	smtpCmd.Flags().String("host", "", "SMTP server host")
+	smtpCmd.Flags().Int("port", 587, "SMTP server port")
+	smtpCmd.Flags().String("from", "", "Sender address, e.g. \"Support <support@example.com>\"")
+	smtpCmd.Flags().String("username", "", "SMTP login username (none for an open relay)")
+	smtpCmd.Flags().String("password", "", "SMTP login password (prefer --password-stdin)")
+	smtpCmd.Flags().Bool("password-stdin", false, "Read the SMTP password from stdin")
+	smtpCmd.Flags().String("security", mail.StartTLS, "Connection security: starttls, tls or none")
+	smtpCmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+	cmd.AddCommand(smtpCmd)
+
+	// config migrate
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move ~/.osticket-cli to XDG directories and encrypt plaintext API keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			if !config.NeedsMigration() && len(config.PlaintextAPIKeys()) == 0 {
+				fmt.Fprintln(app.Out, yellow("Nothing to migrate"))
+				return
+			}
+
+			if config.NeedsMigration() {
+				moves, err := config.Migrate()
+				for _, m := range moves {
+					fmt.Fprintf(app.Out, "  %s\n", m)
+				}
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error migrating config:"), err)
+					exit(1)
+				}
+				fmt.Fprintln(app.Out, green("✓ Configuration migrated to "+config.GetConfigDir()))
+			}
+
+			migrated, err := config.EncryptAPIKeys()
+			for _, m := range migrated {
+				fmt.Fprintf(app.Out, "  API key %s\n", m)
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error encrypting API keys:"), err)
+				exit(1)
+			}
+			if len(migrated) > 0 {
+				fmt.Fprintln(app.Out, green("✓ Plaintext API keys removed from "+config.GetConfigPath()))
+			}
+		},
+	}
+	cmd.AddCommand(migrateCmd)
+	cmd.AddCommand(app.ignoreCmd())
+
+	// config clear
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear configuration for the active profile",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := config.Clear(); err != nil {
+				fmt.Fprintln(app.Err, red("Error clearing config:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Configuration cleared"))
+		},
+	}
+	cmd.AddCommand(clearCmd)
+
+	return cmd
+}
+
+// timeoutText shows a configured timeout, or zero when it is 0
+func timeoutText(d time.Duration, zero string) string {
+	if d == 0 {
+		return zero
+	}
+	return d.String()
+}
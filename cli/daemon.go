@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/daemon"
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/metrics"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== DAEMON ====================
+
+// Webhook payload formats
+const (
+	payloadJSON  = "json"
+	payloadSlack = "slack"
+)
+
+// daemonOptions are the filters and webhook of a daemon run
+type daemonOptions struct {
+	webhook string
+	payload string
+	status  int
+	depts   []int
+	ages    *thresholds.Thresholds
+	muted   *ignore.Matcher
+	// metrics is nil unless --metrics-listen is given
+	metrics *daemonMetrics
+}
+
+// daemonMetrics are the metrics served on --metrics-listen
+type daemonMetrics struct {
+	registry        *metrics.Registry
+	open            *metrics.Gauge
+	overdue         *metrics.Gauge
+	pending         *metrics.Gauge
+	lastPoll        *metrics.Gauge
+	apiErrors       *metrics.Counter
+	apiLatency      *metrics.Histogram
+	webhookFailures *metrics.Counter
+}
+
+func newDaemonMetrics() *daemonMetrics {
+	r := metrics.NewRegistry()
+	return &daemonMetrics{
+		registry:        r,
+		open:            r.Gauge("osticket_tickets_open", "Open tickets the daemon polls, by department.", "department_id", "department"),
+		overdue:         r.Gauge("osticket_tickets_overdue", "Open tickets osTicket flags overdue, by department.", "department_id", "department"),
+		pending:         r.Gauge("osticket_daemon_pending_events", "Events waiting for the webhook to accept them."),
+		lastPoll:        r.Gauge("osticket_daemon_last_poll_timestamp_seconds", "Time of the last successful poll, in seconds since the epoch."),
+		apiErrors:       r.Counter("osticket_api_errors_total", "API requests that failed or got an HTTP error status, by call and reason (HTTP status or \"network\").", "call", "reason"),
+		apiLatency:      r.Histogram("osticket_api_request_duration_seconds", "Latency of API requests, retries included, by call.", metrics.DefaultBuckets, "call"),
+		webhookFailures: r.Counter("osticket_webhook_failures_total", "Webhook deliveries that failed."),
+	}
+}
+
+// observeCall records an API request, as the client's OnCall
+func (m *daemonMetrics) observeCall(call osticket.Call) {
+	name := call.Request.Query + "/" + call.Request.Condition
+	m.apiLatency.Observe(call.Elapsed.Seconds(), name)
+	switch {
+	case call.Err != nil:
+		m.apiErrors.Inc(name, "network")
+	case call.StatusCode >= 400:
+		m.apiErrors.Inc(name, strconv.Itoa(call.StatusCode))
+	}
+}
+
+// webhookFailed counts a failed webhook delivery; m may be nil
+func (m *daemonMetrics) webhookFailed() {
+	if m != nil {
+		m.webhookFailures.Inc()
+	}
+}
+
+// updateQueue sets the ticket gauges from the tickets of a poll, limited
+// to depts when given. Every department gets a value, 0 included, so
+// alerts on it resolve.
+func (m *daemonMetrics) updateQueue(ctx context.Context, client osticket.OSTicketAPI, tickets []map[string]interface{}, depts []int) error {
+	deptData, err := client.GetDepartments(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load departments for metrics: %w", err)
+	}
+	closed, err := closedStatuses(ctx, client)
+	if err != nil {
+		return fmt.Errorf("could not load statuses for metrics: %w", err)
+	}
+	names := map[int]string{}
+	for _, d := range deptData.Departments {
+		if len(depts) == 0 || containsInt(depts, d.ID) {
+			names[d.ID] = d.Name
+		}
+	}
+	open, overdue := map[int]int{}, map[int]int{}
+	for _, t := range tickets {
+		id := mapInt(t, "dept_id")
+		if closed[mapInt(t, "status_id")] || (len(depts) > 0 && !containsInt(depts, id)) {
+			continue
+		}
+		open[id]++
+		if mapInt(t, "isoverdue") == 1 {
+			overdue[id]++
+		}
+		if _, ok := names[id]; !ok {
+			names[id] = ""
+		}
+	}
+	m.open.Reset()
+	m.overdue.Reset()
+	for id, name := range names {
+		m.open.Set(float64(open[id]), strconv.Itoa(id), name)
+		m.overdue.Set(float64(overdue[id]), strconv.Itoa(id), name)
+	}
+	m.lastPoll.Set(float64(time.Now().Unix()))
+	return nil
+}
+
+func (app *App) daemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Forward new and updated tickets to a webhook",
+		Long: `Poll the API and POST an event to --webhook for every ticket created or
+updated since the previous poll, turning the CLI into a bridge for chat
+and automation tools (osTicket has no outbound webhooks).
+
+The tickets seen and any events the webhook did not accept are kept in
+the state directory, so a restarted daemon reports what changed while it
+was down and retries undelivered events in order. The very first poll only
+records the current queue. With --once the daemon polls a single time and
+exits, for running from cron; it exits 1 while events are undelivered.
+
+Events are the JSON objects of 'osticket ticket watch --output json'. A
+Slack incoming webhook (hooks.slack.com) gets a {"text": ...} message
+instead; --payload picks the format explicitly. Tickets matching the
+profile's ignore rules are skipped unless --no-ignore is given. Progress is
+logged at the info level (see --log-level).
+
+--metrics-listen serves Prometheus metrics on /metrics at that address:
+open and overdue tickets by department among the tickets polled (ignore
+rules aside), API request latency and errors, webhook failures, pending
+events and the time of the last poll.`,
+		Example: `  osticket daemon --interval 60s --webhook https://hooks.slack.com/services/T000/B000/XXXX
+  osticket daemon --once --webhook https://automation.example.com/osticket --dept 3
+  osticket daemon --webhook https://automation.example.com/osticket --metrics-listen 127.0.0.1:9464`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "daemon cannot run in offline mode")
+				exit(1)
+			}
+			if app.queueOnFailure {
+				fmt.Fprintln(app.Err, red("Error:"), "daemon cannot run with --queue-on-failure")
+				exit(1)
+			}
+			interval, _ := cmd.Flags().GetDuration("interval")
+			once, _ := cmd.Flags().GetBool("once")
+			statePath, _ := cmd.Flags().GetString("state-file")
+			noIgnore, _ := cmd.Flags().GetBool("no-ignore")
+			var o daemonOptions
+			o.webhook, _ = cmd.Flags().GetString("webhook")
+			o.payload, _ = cmd.Flags().GetString("payload")
+			o.status, _ = cmd.Flags().GetInt("status")
+			o.depts, _ = cmd.Flags().GetIntSlice("dept")
+			metricsAddr, _ := cmd.Flags().GetString("metrics-listen")
+
+			if interval < time.Second {
+				fmt.Fprintln(app.Err, red("Error:"), "--interval must be at least 1s")
+				exit(exitValidation)
+			}
+			u, err := url.Parse(o.webhook)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				fmt.Fprintln(app.Err, red("Error:"), "--webhook must be an http or https URL")
+				exit(exitValidation)
+			}
+			switch o.payload {
+			case "":
+				o.payload = payloadJSON
+				if u.Hostname() == "hooks.slack.com" {
+					o.payload = payloadSlack
+				}
+			case payloadJSON, payloadSlack:
+			default:
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --payload %q: use %s or %s", o.payload, payloadJSON, payloadSlack))
+				exit(exitValidation)
+			}
+			if metricsAddr != "" && once {
+				fmt.Fprintln(app.Err, red("Error:"), "--metrics-listen cannot be used with --once")
+				exit(exitValidation)
+			}
+			if statePath == "" {
+				statePath = config.GetDaemonStatePath()
+			}
+			state, err := daemon.Load(statePath)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+
+			ctx := cmd.Context()
+			client := app.client(ctx)
+			o.ages = app.ageThresholds()
+			if !noIgnore {
+				o.muted = app.ignoreMatcher()
+			}
+			if metricsAddr != "" {
+				o.metrics = newDaemonMetrics()
+				if c, ok := client.(*osticket.Client); ok {
+					c.OnCall = o.metrics.observeCall
+				}
+				ln, err := net.Listen("tcp", metricsAddr)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+				mux := http.NewServeMux()
+				mux.Handle("/metrics", o.metrics.registry.Handler())
+				srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+				go func() {
+					<-ctx.Done()
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					srv.Shutdown(shutdownCtx)
+				}()
+				go func() {
+					if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						app.logger().Error("metrics server stopped: " + err.Error())
+					}
+				}()
+				app.logger().Info("serving metrics", "url", "http://"+ln.Addr().String()+"/metrics")
+			}
+			app.logger().Info("daemon started", "webhook", u.Host, "payload", o.payload, "interval", interval, "state", statePath)
+
+			for {
+				err := app.daemonPoll(ctx, client, state, o)
+				if saveErr := state.Save(statePath); saveErr != nil {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("could not save daemon state: %v", saveErr))
+					exit(1)
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				if once {
+					if err != nil {
+						fmt.Fprintln(app.Err, red("Error:"), err)
+						exit(exitCode(err))
+					}
+					if len(state.Pending) > 0 {
+						fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("%d event(s) not delivered; they are retried on the next run", len(state.Pending)))
+						exit(1)
+					}
+					return
+				}
+				if err != nil {
+					app.logger().Warn(fmt.Sprintf("%v (retrying in %s)", err, interval))
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+	cmd.Flags().Duration("interval", 60*time.Second, "Time between polls")
+	cmd.Flags().String("webhook", "", "URL to POST each event to (required)")
+	cmd.Flags().String("payload", "", "Webhook payload: json or slack (default slack for hooks.slack.com, else json)")
+	cmd.Flags().Bool("once", false, "Poll once and exit, for cron")
+	cmd.Flags().Int("status", 1, "Only forward tickets with this status (0=all, 1=open, 2=resolved, 3=closed)")
+	cmd.Flags().IntSlice("dept", nil, "Only forward tickets in these department IDs (repeatable)")
+	cmd.Flags().Bool("no-ignore", false, "Forward tickets matching the ignore rules too")
+	cmd.Flags().String("metrics-listen", "", "Serve Prometheus metrics on this address, e.g. 127.0.0.1:9464 (default off)")
+	cmd.Flags().String("state-file", "", "State file, for running several daemons on one profile (default in the state directory)")
+	cmd.MarkFlagRequired("webhook")
+	return cmd
+}
+
+// daemonPoll delivers the events queued by earlier polls, then polls the
+// tickets once and forwards what changed. Events the webhook refuses are
+// queued in state; the error is that of the ticket poll.
+func (app *App) daemonPoll(ctx context.Context, client osticket.OSTicketAPI, state *daemon.State, o daemonOptions) error {
+	if o.metrics != nil {
+		defer func() { o.metrics.pending.Set(float64(len(state.Pending))) }()
+	}
+	delivered, failed := 0, 0
+	for len(state.Pending) > 0 {
+		d := &state.Pending[0]
+		d.Attempts++
+		if err := postWebhookBody(ctx, o.webhook, d.Body); err != nil {
+			// Keep the queue in order: later events wait for this one
+			d.Error = err.Error()
+			o.metrics.webhookFailed()
+			app.logger().Warn("webhook failed: "+err.Error(), "pending", len(state.Pending))
+			return nil
+		}
+		state.Pending = state.Pending[1:]
+		delivered++
+	}
+
+	data, err := client.GetTicketsByStatus(ctx, o.status, osticket.Page{})
+	if err != nil {
+		return err
+	}
+	baseline := state.Tickets == nil
+	if baseline {
+		state.Tickets = map[int]string{}
+	}
+	events := diffTickets(state.Tickets, data.Tickets, o.depts, o.ages)
+	// Forget tickets that no longer match, e.g. closed ones, so the state
+	// does not grow forever; a ticket matching again is reported as new
+	current := map[int]bool{}
+	for _, t := range data.Tickets {
+		current[mapInt(t, "ticket_id")] = true
+	}
+	for id := range state.Tickets {
+		if !current[id] {
+			delete(state.Tickets, id)
+		}
+	}
+	state.Polled = time.Now()
+	if o.metrics != nil {
+		if err := o.metrics.updateQueue(ctx, client, data.Tickets, o.depts); err != nil {
+			app.logger().Warn(err.Error())
+		}
+	}
+	if baseline {
+		app.logger().Info(fmt.Sprintf("recorded %d ticket(s); forwarding changes from the next poll", len(state.Tickets)))
+		return nil
+	}
+
+	for _, ev := range events {
+		if o.muted != nil {
+			if _, skip := o.muted.Match(ignore.Ticket{
+				Number:  ev.Number,
+				Subject: ev.Subject,
+				Sender:  ev.Sender,
+				DeptID:  ev.DeptID,
+			}); skip {
+				continue
+			}
+		}
+		body, err := webhookPayload(o.payload, ev)
+		if err != nil {
+			return err
+		}
+		d := daemon.Delivery{Body: body, Queued: ev.Seen}
+		if len(state.Pending) == 0 {
+			d.Attempts++
+			err := postWebhookBody(ctx, o.webhook, body)
+			if err == nil {
+				delivered++
+				continue
+			}
+			d.Error = err.Error()
+			o.metrics.webhookFailed()
+			app.logger().Warn("webhook failed: " + err.Error())
+		}
+		failed++
+		if dropped := state.Queue(d); dropped > 0 {
+			app.logger().Warn(fmt.Sprintf("dropped %d undelivered event(s): more than %d pending", dropped, daemon.MaxPending))
+		}
+	}
+	app.logger().Info("polled", "tickets", len(state.Tickets), "events", len(events), "delivered", delivered, "queued", failed, "pending", len(state.Pending))
+	return nil
+}
+
+// webhookPayload formats an event as the webhook expects it
+func webhookPayload(format string, ev watchEvent) ([]byte, error) {
+	if format == payloadSlack {
+		return json.Marshal(map[string]string{"text": slackText(ev)})
+	}
+	return json.Marshal(ev)
+}
+
+// slackText describes an event as a Slack message, e.g. "New ticket
+// #123456: Printer on fire (dept 1, Open, age 2h)"
+func slackText(ev watchEvent) string {
+	kind := "New"
+	if ev.Type == watchUpdated {
+		kind = "Updated"
+	}
+	details := []string{fmt.Sprintf("dept %d", ev.DeptID), ticketStatus(ev.Ticket)}
+	if ev.Age != "" {
+		details = append(details, "age "+ev.Age)
+	}
+	// Slack reads &, < and > as markup
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return escape.Replace(fmt.Sprintf("%s ticket #%s: %s (%s)", kind, ev.Number, ev.Subject, strings.Join(details, ", ")))
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/markup"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== EDITOR BODIES ====================
+
+// editScissors separates what the user writes from the help below it;
+// everything from this line on is dropped
+const editScissors = "# ------------------------ >8 ------------------------"
+
+// addEditFlags adds --edit and --no-quote to a command with --body
+func addEditFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("edit", false, "Write the body in $VISUAL or $EDITOR, starting from --body and a quote of the last message")
+	cmd.Flags().Bool("no-quote", false, "Do not quote the last message with --edit")
+}
+
+// messageBody returns the body of a reply, note or closing message: --body,
+// or with --edit what the user saved in their editor. what names the body
+// in messages, e.g. "reply". Only notes may quote an internal note.
+func (app *App) messageBody(cmd *cobra.Command, client osticket.OSTicketAPI, ticketID int, what string) string {
+	body, _ := cmd.Flags().GetString("body")
+	edit, _ := cmd.Flags().GetBool("edit")
+	if !edit {
+		if body == "" {
+			fmt.Fprintln(app.Err, red("Error:"), "--body or --edit is required")
+			exit(exitValidation)
+		}
+		return app.convertBody(cmd, body)
+	}
+
+	format := app.bodyFormat(cmd)
+	initial := body
+	if noQuote, _ := cmd.Flags().GetBool("no-quote"); !noQuote {
+		if e := app.lastThreadEntry(cmd.Context(), client, ticketID, what == "note"); e != nil {
+			initial = strings.TrimRight(initial, "\n") + "\n\n" + quoteEntry(*e)
+		}
+	}
+	initial += fmt.Sprintf("\n%s\n# Write the %s above this line; everything below it is ignored.\n# An empty %s sends nothing.\n", editScissors, what, what)
+
+	suffix := ".txt"
+	switch format {
+	case markup.Markdown:
+		suffix = ".md"
+	case markup.HTML:
+		suffix = ".html"
+	}
+	edited, err := editText(initial, suffix)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(1)
+	}
+	if i := strings.Index(edited, editScissors); i >= 0 {
+		edited = edited[:i]
+	}
+	edited = strings.TrimSpace(edited)
+	if edited == "" {
+		fmt.Fprintln(app.Err, yellow(fmt.Sprintf("Empty %s: nothing sent", what)))
+		exit(1)
+	}
+	return app.convertBody(cmd, edited)
+}
+
+// lastThreadEntry returns the ticket's latest message or response, or
+// latest entry of any kind with notes. Failures are warnings, as the
+// quote is only a convenience.
+func (app *App) lastThreadEntry(ctx context.Context, client osticket.OSTicketAPI, ticketID int, notes bool) *osticket.ThreadEntry {
+	data, err := client.GetTicketThread(ctx, strconv.Itoa(ticketID))
+	if err != nil {
+		app.logger().Warn(fmt.Sprintf("could not load the thread to quote: %v", err))
+		return nil
+	}
+	for i := len(data.Entries) - 1; i >= 0; i-- {
+		e := data.Entries[i]
+		if e.Type == osticket.ThreadMessage || e.Type == osticket.ThreadResponse || notes {
+			return &e
+		}
+	}
+	return nil
+}
+
+// quoteEntry quotes a thread entry as an email reply does
+func quoteEntry(e osticket.ThreadEntry) string {
+	poster := e.Poster
+	if poster == "" {
+		poster = "(unknown)"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "On %s, %s wrote:\n", e.Created, poster)
+	for _, line := range strings.Split(entryText(e), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// addBodyFormatFlag adds --body-format to a command that sends a body
+func addBodyFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("body-format", markup.Text, "Format of the body: "+strings.Join(markup.Formats, ", ")+" (Markdown is sent as HTML, HTML is sanitized)")
+}
+
+// bodyFormat returns --body-format, exiting when it is not a known format
+func (app *App) bodyFormat(cmd *cobra.Command) string {
+	format, _ := cmd.Flags().GetString("body-format")
+	format = strings.ToLower(format)
+	if format == "md" {
+		format = markup.Markdown
+	}
+	if !containsString(markup.Formats, format) {
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --body-format %q: use %s", format, strings.Join(markup.Formats, ", ")))
+		exit(exitValidation)
+	}
+	return format
+}
+
+// convertBody converts a body from its --body-format to what osTicket
+// receives
+func (app *App) convertBody(cmd *cobra.Command, body string) string {
+	converted, err := markup.Convert(body, app.bodyFormat(cmd))
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitValidation)
+	}
+	return converted
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== NAME ENRICHMENT ====================
+
+// enrichedFields maps a ticket's ID fields to the field its resolved name
+// is stored in
+var enrichedFields = map[string]string{
+	"dept_id":   "dept_name",
+	"topic_id":  "topic_name",
+	"staff_id":  "staff_name",
+	"team_id":   "team_name",
+	"status_id": "status_name",
+}
+
+// addEnrichFlag registers --enrich on a command listing tickets
+func addEnrichFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("enrich", false, "Add department, help topic, agent, team and status names to tickets (default on for table output)")
+}
+
+// enrichTickets adds the names of the tickets' department, help topic,
+// assigned agent and team, and status as dept_name, topic_name, staff_name,
+// team_name and status_name, from the cached server lists. Names are added to table
+// output unless --enrich=false, and to other formats with --enrich. A
+// failed lookup leaves the IDs alone; it is only reported when --enrich
+// was given.
+func (app *App) enrichTickets(cmd *cobra.Command, client osticket.OSTicketAPI, tickets []map[string]interface{}) {
+	enrich, _ := cmd.Flags().GetBool("enrich")
+	if !cmd.Flags().Changed("enrich") {
+		enrich = app.outputFormat(output.JSON) == output.Table
+	}
+	if !enrich {
+		return
+	}
+	app.addTicketNames(cmd.Context(), client, tickets, cmd.Flags().Changed("enrich"))
+}
+
+// addTicketNames adds the name fields of enrichedFields to tickets. A
+// failed lookup leaves the IDs alone and is reported when warn is set.
+func (app *App) addTicketNames(ctx context.Context, client osticket.OSTicketAPI, tickets []map[string]interface{}, warn bool) {
+	if len(tickets) == 0 {
+		return
+	}
+	for idField, nameField := range enrichedFields {
+		if !anyID(tickets, idField) {
+			continue
+		}
+		names, err := ticketNames(ctx, client, idField)
+		if err != nil {
+			if warn {
+				app.logger().Warn(fmt.Sprintf("could not resolve %s names: %v", strings.TrimSuffix(idField, "_id"), err))
+			}
+			continue
+		}
+		for _, t := range tickets {
+			if name, ok := names[mapInt(t, idField)]; ok {
+				t[nameField] = name
+			}
+		}
+	}
+}
+
+// anyID reports whether a ticket has a non-zero value for field
+func anyID(tickets []map[string]interface{}, field string) bool {
+	for _, t := range tickets {
+		if mapInt(t, field) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ticketNames returns the names for the IDs of a ticket field
+func ticketNames(ctx context.Context, client osticket.OSTicketAPI, field string) (map[int]string, error) {
+	names := map[int]string{}
+	if field == "staff_id" {
+		data, err := client.GetStaffList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range data.Staff {
+			name := strings.TrimSpace(s.Firstname + " " + s.Lastname)
+			if name == "" {
+				name = s.Username
+			}
+			names[s.StaffID] = name
+		}
+		return names, nil
+	}
+
+	choices, err := idChoices(ctx, client, strings.TrimSuffix(field, "_id"))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range choices {
+		names[c.ID] = c.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ==================== EXAMPLES ====================
+
+// exampleTopic is a set of recipes printed by 'osticket examples'
+type exampleTopic struct {
+	name    string
+	summary string
+	text    string
+}
+
+var exampleTopics = []exampleTopic{
+	{"setup", "Configure the server, API key and profiles", `  # Point the CLI at your helpdesk (the key goes to the OS keyring)
+  osticket config set --url https://helpdesk.example.com/ost_wbs/ --key YOUR_API_KEY
+
+  # A second server under its own profile
+  osticket config set --profile staging --url https://staging.example.com/ost_wbs/ --key STAGING_KEY
+  osticket --profile staging info departments
+
+  # One-off credentials, e.g. in CI
+  OSTICKET_BASE_URL=https://helpdesk.example.com/ost_wbs/ OSTICKET_API_KEY=... osticket ticket search --status 1`},
+	{"tickets", "Create, reply to and close tickets", `  osticket ticket create --title "VPN down" --subject "Cannot connect since 9am" --user-id 42 --dept Support
+  osticket ticket reply 12345 --body "Restarted the gateway, please retry." --staff-id 1
+  osticket ticket assign 12345 --staff-id 7 --comment "Network team"
+  osticket ticket close 12345 --body "Confirmed working." --staff-id 1`},
+	{"search", "Find tickets and page through results", `  # Open tickets of one user
+  osticket ticket search --status 1 --email user@example.com
+
+  # Full-text search over a date range, as CSV
+  osticket ticket search --term invoice --from 2024-01-01 --to 2024-03-31 -o csv
+
+  # Every ticket, fetched page by page
+  osticket ticket search --all --limit 200 -o json > tickets.json`},
+	{"export", "Export tickets to files, object storage or a warehouse", `  # Nightly delta as CSV
+  osticket ticket export --since-last-run -o csv --dest exports/
+
+  # Straight to S3 with a signed checksum manifest
+  osticket ticket export -o csv --dest s3://warehouse/osticket/ --sse aws:kms --sign gpg
+
+  # Upsert into Postgres
+  osticket export warehouse --dsn postgres://etl@db.example.com/analytics --since-last-run`},
+	{"watch", "Follow new and updated tickets", `  osticket ticket watch --interval 1m
+  osticket ticket watch --dept 2 --dept 5 --notify
+  osticket ticket watch -o json --webhook https://chat.example.com/hooks/support`},
+	{"offline", "Work without a connection and sync later", `  # Reads come from local snapshots, changes are queued
+  osticket --offline ticket reply 12345 --body "On it." --staff-id 1
+
+  # Review and send the queue once back online
+  osticket outbox list
+  osticket outbox flush`},
+	{"retention", "Apply retention policies and legal holds", `  # Protect a ticket and a user first
+  osticket hold add --ticket 100042 --reason "Case 2026-117"
+  osticket hold add --user jane@example.com
+
+  # Review what the policy selects, then apply that plan
+  osticket retention apply --policy retention.yaml --dry-run
+  osticket retention apply --policy retention.yaml --staff-id 1`},
+	{"troubleshooting", "Debug connection and API problems", `  # Log requests and responses, with the API key redacted
+  osticket --debug ticket get 12345
+
+  # Behind a proxy or with a private CA
+  osticket --proxy http://proxy.example.com:3128 --ca-cert ca.pem info departments
+
+  # Call an endpoint the CLI has no command for
+  osticket api call --query ticket --condition all --sort status --param status=1`},
+}
+
+func (app *App) examplesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "examples [topic]",
+		Short: "Show recipes for common tasks",
+		Long: `Show ready-to-run command recipes for a topic. Without a topic the
+available topics are listed.`,
+		Example: `  osticket examples
+  osticket examples search`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			topics := make([]string, len(exampleTopics))
+			for i, t := range exampleTopics {
+				topics[i] = t.name + "\t" + t.summary
+			}
+			return topics, cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				fmt.Fprintln(app.Out, "Topics:")
+				for _, t := range exampleTopics {
+					fmt.Fprintf(app.Out, "  %-16s %s\n", t.name, t.summary)
+				}
+				fmt.Fprintln(app.Out, "\nRun 'osticket examples <topic>' to show one.")
+				return
+			}
+
+			for _, t := range exampleTopics {
+				if strings.EqualFold(t.name, args[0]) {
+					fmt.Fprintln(app.Out, cyan(t.summary+":"))
+					fmt.Fprintln(app.Out)
+					fmt.Fprintln(app.Out, t.text)
+					return
+				}
+			}
+			names := make([]string, len(exampleTopics))
+			for i, t := range exampleTopics {
+				names[i] = t.name
+			}
+			fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("unknown topic %q (available: %s)", args[0], strings.Join(names, ", ")))
+			exit(1)
+		},
+	}
+}
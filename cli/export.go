@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/dest"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/sign"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET EXPORT ====================
+
+// exportState is the cursor persisted between delta exports. Tickets
+// changed at exactly Cursor are listed in CursorIDs so the next run skips
+// them without missing others changed in the same second.
+type exportState struct {
+	Cursor    string    `json:"cursor"`
+	CursorIDs []int     `json:"cursor_ids,omitempty"`
+	LastRun   time.Time `json:"last_run"`
+	Exported  int       `json:"exported"`
+}
+
+func (app *App) exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export tickets for loading into other systems",
+		Long: `Export every ticket, or with --since-last-run only the tickets created or
+updated since the previous run. The cursor is kept in a state file
+(--state) and only advances once the export has been written, so a failed
+run is simply repeated the next time.
+
+  osticket ticket export --since-last-run --state export.state -o csv > delta.csv
+
+--dest writes to a file or streams to object storage instead of stdout; a
+destination ending in "/" gets a timestamped file name:
+
+  osticket ticket export -o csv --dest s3://warehouse/osticket/ --sse aws:kms
+  osticket ticket export -o json --dest gs://exports/osticket/tickets.json
+
+--checksum writes a SHA-256 manifest (<file>.sha256, checkable with
+"sha256sum -c") next to the export, and --sign also signs the manifest
+with gpg (<file>.sha256.asc) or minisign (<file>.sha256.minisig):
+
+  osticket ticket export -o csv --dest exports/ --sign gpg --sign-key compliance@example.com
+
+Tickets are compared against the cursor on the client, so every run still
+reads the full ticket list from the API.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			status, _ := cmd.Flags().GetInt("status")
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+			sinceLastRun, _ := cmd.Flags().GetBool("since-last-run")
+			statePath, _ := cmd.Flags().GetString("state")
+			target, _ := cmd.Flags().GetString("dest")
+			var destOpts dest.Options
+			destOpts.SSE, _ = cmd.Flags().GetString("sse")
+			destOpts.KMSKey, _ = cmd.Flags().GetString("kms-key")
+			if err := destOpts.Validate(target); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			checksum, _ := cmd.Flags().GetBool("checksum")
+			signTool, _ := cmd.Flags().GetString("sign")
+			signKey, _ := cmd.Flags().GetString("sign-key")
+			if signTool != "" {
+				if _, err := sign.Extension(signTool); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				checksum = true
+			}
+			if checksum && target == "" {
+				fmt.Fprintln(app.Err, red("Error:"), "--checksum and --sign require --dest")
+				exit(1)
+			}
+			if (from == "") != (to == "") {
+				fmt.Fprintln(app.Err, red("Error:"), "--from and --to must be used together")
+				exit(1)
+			}
+			if app.outputFormat(output.JSON) == output.Raw {
+				fmt.Fprintln(app.Err, red("Error:"), "ticket export does not support --output raw")
+				exit(1)
+			}
+			if statePath == "" {
+				statePath = config.GetExportStatePath("export")
+			}
+
+			var state exportState
+			if sinceLastRun {
+				var err error
+				state, err = loadExportState(statePath)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				if state.Cursor == "" {
+					fmt.Fprintln(app.Err, yellow("No previous export found, exporting all tickets"))
+				}
+			}
+
+			client := app.client(cmd.Context())
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				if from != "" {
+					return client.GetTicketsByDateRange(cmd.Context(), from, to, p)
+				}
+				return client.GetTicketsByStatus(cmd.Context(), status, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			tickets := data.Tickets
+			if sinceLastRun {
+				tickets = changedSince(tickets, state)
+			}
+			result := &osticket.SimpleTicketResponse{Total: len(tickets), Tickets: tickets}
+			out := &output.Result{
+				Value: result,
+				Rows:  result.Tickets,
+				Table: func(w io.Writer) { app.displayTicketRows(w, result.Tickets) },
+			}
+			if target == "" {
+				app.render(output.JSON, out)
+			} else {
+				path, sum, err := app.writeExport(cmd.Context(), target, destOpts, out)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				fmt.Fprintf(app.Err, "%s %d ticket(s) written to %s\n", green("✓"), len(tickets), path)
+				if checksum {
+					if err := app.writeManifest(cmd.Context(), path, sum, destOpts, signTool, signKey); err != nil {
+						fmt.Fprintln(app.Err, red("Error:"), err)
+						exit(exitCode(err))
+					}
+				}
+			}
+
+			if !sinceLastRun {
+				return
+			}
+			next := advanceCursor(state, tickets)
+			if err := saveExportState(statePath, next); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving export state:"), err)
+				exit(1)
+			}
+			fmt.Fprintf(app.Err, "Exported %d changed ticket(s); cursor at %s\n", len(tickets), next.Cursor)
+		},
+	}
+	cmd.Flags().Int("status", 0, "Filter by status (0=all, 1=open, 2=resolved, 3=closed)")
+	cmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	cmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	cmd.Flags().Bool("since-last-run", false, "Only export tickets created or updated since the previous run")
+	cmd.Flags().String("state", "", "State file holding the export cursor (default in the state directory)")
+	cmd.Flags().String("dest", "", "Write to a file, s3://bucket/key or gs://bucket/key instead of stdout")
+	cmd.Flags().String("sse", "", "S3 server-side encryption: AES256 or aws:kms")
+	cmd.Flags().String("kms-key", "", "KMS key for S3 (aws:kms) or GCS uploads")
+	cmd.Flags().Bool("checksum", false, "Write a SHA-256 manifest next to the export")
+	cmd.Flags().String("sign", "", "Sign the manifest with gpg or minisign (implies --checksum)")
+	cmd.Flags().String("sign-key", "", "gpg key ID or minisign secret key file (default: the tool's default key)")
+	return cmd
+}
+
+// writeExport writes an export to a destination and returns where it went
+// and the SHA-256 of what was written. Nothing is left at the destination
+// if writing fails.
+func (app *App) writeExport(ctx context.Context, target string, opts dest.Options, r *output.Result) (string, []byte, error) {
+	format := app.outputFormat(output.JSON)
+	ext := format
+	if format == output.Table {
+		ext = "txt"
+	}
+	path := dest.Resolve(target, fmt.Sprintf("tickets-%s.%s", time.Now().UTC().Format("20060102T150405Z"), ext))
+
+	w, err := dest.Create(ctx, path, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	hash := sha256.New()
+	r.Fields = app.fields
+	if err := output.Write(io.MultiWriter(w, hash), format, r); err != nil {
+		w.Abort()
+		return "", nil, err
+	}
+	if err := w.Close(); err != nil {
+		return "", nil, fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return path, hash.Sum(nil), nil
+}
+
+// writeManifest writes the checksum manifest of an export next to it and,
+// with a signing tool, a detached signature of the manifest
+func (app *App) writeManifest(ctx context.Context, path string, sum []byte, opts dest.Options, tool, key string) error {
+	manifest := sign.Manifest(sum, path)
+	if err := app.writeFile(ctx, path+sign.ManifestExt, manifest, opts); err != nil {
+		return err
+	}
+	if tool == "" {
+		return nil
+	}
+	sig, err := sign.Sign(ctx, tool, key, manifest)
+	if err != nil {
+		return err
+	}
+	ext, _ := sign.Extension(tool)
+	return app.writeFile(ctx, path+sign.ManifestExt+ext, sig, opts)
+}
+
+// writeFile writes data to a destination
+func (app *App) writeFile(ctx context.Context, path string, data []byte, opts dest.Options) error {
+	w, err := dest.Create(ctx, path, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Abort()
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	fmt.Fprintf(app.Err, "%s %s written\n", green("✓"), path)
+	return nil
+}
+
+// ticketChanged returns when a ticket was last created or updated. API
+// timestamps ("2006-01-02 15:04:05") sort correctly as strings.
+func ticketChanged(t map[string]interface{}) string {
+	latest := mapString(t, "created")
+	for _, key := range []string{"updated", "lastupdate"} {
+		if v := mapString(t, key); v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// changedSince returns the tickets changed after the state's cursor
+func changedSince(tickets []map[string]interface{}, state exportState) []map[string]interface{} {
+	var changed []map[string]interface{}
+	for _, t := range tickets {
+		stamp := ticketChanged(t)
+		if stamp > state.Cursor || stamp == state.Cursor && !containsInt(state.CursorIDs, mapInt(t, "ticket_id")) {
+			changed = append(changed, t)
+		}
+	}
+	return changed
+}
+
+// advanceCursor moves the cursor to the newest exported change
+func advanceCursor(state exportState, exported []map[string]interface{}) exportState {
+	next := state
+	next.LastRun = time.Now()
+	next.Exported = len(exported)
+	for _, t := range exported {
+		stamp := ticketChanged(t)
+		switch {
+		case stamp > next.Cursor:
+			next.Cursor = stamp
+			next.CursorIDs = []int{mapInt(t, "ticket_id")}
+		case stamp == next.Cursor:
+			next.CursorIDs = append(next.CursorIDs, mapInt(t, "ticket_id"))
+		}
+	}
+	return next
+}
+
+// loadExportState reads a state file; a missing file is the zero state
+func loadExportState(path string) (exportState, error) {
+	var state exportState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("could not read export state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid export state in %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveExportState writes a state file atomically
+func saveExportState(path string, state exportState) error {
+	return writeJSONFile(path, state)
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/osticket-cli-go/internal/handoff"
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/retention"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== SHIFT HANDOFF ====================
+
+func (app *App) handoffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "handoff",
+		Short: "Summarize a shift for the next on-call",
+		Long: `Print a shift handoff summary ready to paste into the team channel:
+
+  New tickets       created within --since
+  Escalations       open tickets osTicket flagged overdue, or past their
+                    priority's age threshold (see 'osticket config set
+                    --age-threshold')
+  Open emergencies  open tickets of emergency priority
+  Due soon          open tickets whose due date is within --due-within
+
+--format md prints Markdown, --format slack Slack's mrkdwn. With --output
+json or yaml the summary is printed as data instead. Tickets matching the
+profile's ignore rules are left out unless --no-ignore is given.`,
+		Example: `  osticket handoff --since 8h --format md
+  osticket handoff --since 12h --format slack --dept 1 --dept 3 | pbcopy
+  osticket handoff -o json | jq '.emergencies | length'`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceValue, _ := cmd.Flags().GetString("since")
+			dueValue, _ := cmd.Flags().GetString("due-within")
+			format, _ := cmd.Flags().GetString("format")
+			depts, _ := cmd.Flags().GetIntSlice("dept")
+			noIgnore, _ := cmd.Flags().GetBool("no-ignore")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+
+			if format != handoff.Markdown && format != handoff.Slack {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --format %q: use %s or %s", format, handoff.Markdown, handoff.Slack))
+				exit(exitValidation)
+			}
+			since, err := retention.ParseAge(sinceValue)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), "--since: "+err.Error())
+				exit(exitValidation)
+			}
+			dueWithin, err := retention.ParseAge(dueValue)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), "--due-within: "+err.Error())
+				exit(exitValidation)
+			}
+
+			ctx := cmd.Context()
+			client := app.client(ctx)
+			closed, err := closedStatuses(ctx, client)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error loading statuses:"), err)
+				exit(exitCode(err))
+			}
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				return client.GetTicketsByStatus(ctx, 0, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			var tickets []map[string]interface{}
+			var muted *ignore.Matcher
+			if !noIgnore {
+				muted = app.ignoreMatcher()
+			}
+			for _, t := range data.Tickets {
+				if len(depts) > 0 && !containsInt(depts, mapInt(t, "dept_id")) {
+					continue
+				}
+				if muted != nil {
+					if _, skip := muted.Match(ignore.Ticket{
+						Number:  mapString(t, "number"),
+						Subject: mapString(t, "subject"),
+						Sender:  mapString(t, "email"),
+						DeptID:  mapInt(t, "dept_id"),
+					}); skip {
+						continue
+					}
+				}
+				tickets = append(tickets, t)
+			}
+
+			now := time.Now()
+			ages := app.ageThresholds()
+			sections := buildHandoff(tickets, closed, ages, now, now.Add(-since), now.Add(dueWithin))
+			listed := sections.tickets()
+			app.addTicketNames(ctx, client, listed, false)
+			convert := func(list []map[string]interface{}) []handoff.Ticket {
+				out := []handoff.Ticket{}
+				for _, t := range list {
+					out = append(out, handoffTicket(t, ages, now))
+				}
+				return out
+			}
+			sum := &handoff.Summary{
+				Generated:   now,
+				Since:       now.Add(-since),
+				DueBefore:   now.Add(dueWithin),
+				New:         convert(sections.new),
+				Escalations: convert(sections.escalations),
+				Emergencies: convert(sections.emergencies),
+				DueSoon:     convert(sections.dueSoon),
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: sum,
+				IDs:   ticketIDs(listed),
+				Table: func(w io.Writer) {
+					handoff.Write(w, format, sum)
+				},
+			})
+		},
+	}
+	cmd.Flags().String("since", "8h", "Length of the shift, e.g. 8h or 1d")
+	cmd.Flags().String("due-within", "4h", "List open tickets due within this period")
+	cmd.Flags().String("format", handoff.Markdown, "Summary format: md or slack")
+	cmd.Flags().IntSlice("dept", nil, "Only include tickets in these department IDs (repeatable)")
+	cmd.Flags().Bool("no-ignore", false, "Include tickets matching the ignore rules")
+	cmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	return cmd
+}
+
+// handoffSections are the tickets of each section of a handoff
+type handoffSections struct {
+	new, escalations, emergencies, dueSoon []map[string]interface{}
+}
+
+// tickets returns every ticket listed, once
+func (s handoffSections) tickets() []map[string]interface{} {
+	seen := map[int]bool{}
+	var all []map[string]interface{}
+	for _, list := range [][]map[string]interface{}{s.new, s.escalations, s.emergencies, s.dueSoon} {
+		for _, t := range list {
+			if id := mapInt(t, "ticket_id"); !seen[id] {
+				seen[id] = true
+				all = append(all, t)
+			}
+		}
+	}
+	return all
+}
+
+// buildHandoff sorts tickets into the sections of the handoff: created
+// after since, open and late (emergencies apart), open emergencies, and
+// open with a due date before dueBefore
+func buildHandoff(tickets []map[string]interface{}, closed map[int]bool, ages *thresholds.Thresholds, now, since, dueBefore time.Time) handoffSections {
+	var s handoffSections
+	for _, t := range tickets {
+		if created, ok := parseAPITime(mapString(t, "created")); ok && !created.Before(since) {
+			s.new = append(s.new, t)
+		}
+		if closed[mapInt(t, "status_id")] {
+			continue
+		}
+		if mapInt(t, "priority_id") == osticket.PriorityEmergency {
+			s.emergencies = append(s.emergencies, t)
+		} else if _, level, ok := ticketAge(ages, t, now); (ok && level == thresholds.Late) || mapInt(t, "isoverdue") == 1 {
+			s.escalations = append(s.escalations, t)
+		}
+		if due, ok := dueDate(t); ok && due.After(now) && due.Before(dueBefore) {
+			s.dueSoon = append(s.dueSoon, t)
+		}
+	}
+
+	byCreated := func(list []map[string]interface{}) {
+		sort.SliceStable(list, func(i, j int) bool {
+			return mapString(list[i], "created") < mapString(list[j], "created")
+		})
+	}
+	byCreated(s.new)
+	byCreated(s.escalations)
+	byCreated(s.emergencies)
+	sort.SliceStable(s.dueSoon, func(i, j int) bool {
+		a, _ := dueDate(s.dueSoon[i])
+		b, _ := dueDate(s.dueSoon[j])
+		return a.Before(b)
+	})
+	return s
+}
+
+// handoffTicket converts an API ticket for the summary
+func handoffTicket(t map[string]interface{}, ages *thresholds.Thresholds, now time.Time) handoff.Ticket {
+	ht := handoff.Ticket{
+		ID:       mapInt(t, "ticket_id"),
+		Number:   mapString(t, "number"),
+		Subject:  mapString(t, "subject"),
+		Status:   ticketStatus(t),
+		Priority: mapString(t, "priority"),
+		Dept:     mapString(t, "dept_name"),
+		Assignee: assignee(t),
+		Overdue:  mapInt(t, "isoverdue") == 1,
+	}
+	if ht.Number == "" {
+		ht.Number = strconv.Itoa(ht.ID)
+	}
+	// Without resolved names, say what the IDs are
+	if id := mapInt(t, "dept_id"); ht.Dept == "" && id != 0 {
+		ht.Dept = fmt.Sprintf("dept %d", id)
+	}
+	if id := mapInt(t, "staff_id"); mapString(t, "staff_name") == "" && id != 0 {
+		ht.Assignee = fmt.Sprintf("agent %d", id)
+	}
+	if ht.Priority == "" {
+		ht.Priority = osticket.PriorityNames[mapInt(t, "priority_id")]
+	}
+	if age, _, ok := ticketAge(ages, t, now); ok {
+		ht.Age = thresholds.Format(age)
+	}
+	if due, ok := dueDate(t); ok {
+		ht.Due = &due
+	}
+	return ht
+}
+
+// dueDate returns a ticket's due date, or else the one its SLA sets
+func dueDate(t map[string]interface{}) (time.Time, bool) {
+	if due, ok := parseAPITime(mapString(t, "duedate")); ok {
+		return due, true
+	}
+	return parseAPITime(mapString(t, "est_duedate"))
+}
+
+// parseAPITime parses an API timestamp in local time
+func parseAPITime(s string) (time.Time, bool) {
+	at, err := time.ParseInLocation(thresholds.TimeLayout, s, time.Local)
+	return at, err == nil
+}
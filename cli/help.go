@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ==================== HELP AND SUGGESTIONS ====================
+
+// addHelpSearch adds 'help search' to the root's help command
+func (app *App) addHelpSearch(root *cobra.Command) {
+	root.InitDefaultHelpCmd()
+	for _, c := range root.Commands() {
+		if c.Name() == "help" {
+			c.AddCommand(app.helpSearchCmd())
+		}
+	}
+}
+
+func (app *App) helpSearchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search <keyword>...",
+		Short: "Find commands and flags by keyword",
+		Long: `Search the names and descriptions of every command and flag. All
+keywords must match, ignoring case.
+
+  osticket help search attachment
+  osticket help search staff id`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keywords := make([]string, len(args))
+			for i, a := range args {
+				keywords[i] = strings.ToLower(a)
+			}
+			matches := func(texts ...string) bool {
+				text := strings.ToLower(strings.Join(texts, " "))
+				for _, k := range keywords {
+					if !strings.Contains(text, k) {
+						return false
+					}
+				}
+				return true
+			}
+
+			found := 0
+			var search func(c *cobra.Command)
+			search = func(c *cobra.Command) {
+				for _, sub := range c.Commands() {
+					if !sub.IsAvailableCommand() {
+						continue
+					}
+					var flags []*pflag.Flag
+					sub.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+						if !f.Hidden && matches(f.Name, f.Usage) {
+							flags = append(flags, f)
+						}
+					})
+					if matches(sub.CommandPath(), sub.Short, sub.Long) || len(flags) > 0 {
+						found++
+						fmt.Fprintf(app.Out, "%s  %s\n", cyan(sub.CommandPath()), sub.Short)
+						for _, f := range flags {
+							fmt.Fprintf(app.Out, "    --%-20s %s\n", f.Name, f.Usage)
+						}
+					}
+					search(sub)
+				}
+			}
+
+			root := cmd.Root()
+			var global []*pflag.Flag
+			root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+				if !f.Hidden && matches(f.Name, f.Usage) {
+					global = append(global, f)
+				}
+			})
+			if len(global) > 0 {
+				found++
+				fmt.Fprintln(app.Out, cyan("Global flags"))
+				for _, f := range global {
+					fmt.Fprintf(app.Out, "    --%-20s %s\n", f.Name, f.Usage)
+				}
+			}
+			search(root)
+
+			if found == 0 {
+				fmt.Fprintln(app.Out, yellow(fmt.Sprintf("No commands or flags match %q", strings.Join(args, " "))))
+			}
+		},
+	}
+}
+
+// groupingAnnotation marks commands that only group subcommands, which
+// suggestSubcommands makes runnable
+const groupingAnnotation = "osticket_grouping"
+
+// suggestSubcommands makes commands that only group subcommands, such as
+// 'ticket', reject unknown subcommands with suggestions instead of
+// printing their help. Cobra only does this for the root.
+func suggestSubcommands(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		if sub.HasSubCommands() && !sub.Runnable() {
+			sub.Args = func(c *cobra.Command, args []string) error {
+				if len(args) == 0 {
+					return nil
+				}
+				if c.SuggestionsMinimumDistance <= 0 {
+					c.SuggestionsMinimumDistance = 2
+				}
+				// Like the root, point at --help rather than printing it
+				c.SilenceUsage = true
+				return fmt.Errorf("unknown command %q for %q%s\nRun '%s --help' for usage.", args[0], c.CommandPath(), didYouMean(c.SuggestionsFor(args[0])), c.CommandPath())
+			}
+			sub.Run = func(c *cobra.Command, args []string) {
+				c.Help()
+			}
+			if sub.Annotations == nil {
+				sub.Annotations = map[string]string{}
+			}
+			sub.Annotations[groupingAnnotation] = "true"
+		}
+		suggestSubcommands(sub)
+	}
+}
+
+// flagError adds the closest flag names to an unknown flag error
+func flagError(cmd *cobra.Command, err error) error {
+	name, ok := strings.CutPrefix(err.Error(), "unknown flag: --")
+	if !ok {
+		return err
+	}
+
+	var choices []validate.Choice
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden {
+			choices = append(choices, validate.Choice{Name: f.Name})
+		}
+	})
+	var names []string
+	for _, c := range validate.Similar(name, choices, 3) {
+		names = append(names, "--"+c.Name)
+	}
+	if len(names) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w%s", err, didYouMean(names))
+}
+
+// didYouMean formats suggestions the way cobra does for unknown commands
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	s := "\n\nDid you mean this?\n"
+	for _, name := range suggestions {
+		s += "\t" + name + "\n"
+	}
+	return s
+}
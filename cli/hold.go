@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/hold"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== LEGAL HOLDS ====================
+
+func (app *App) holdCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hold",
+		Short: "Manage legal holds",
+		Long: `Legal holds protect tickets from retention and other destructive
+commands, which refuse to touch a held ticket. A hold covers one ticket
+(by ID or number) or every ticket of a user. Holds are stored per profile.
+
+  osticket hold add --ticket 100042 --reason "Case 2026-117"
+  osticket hold add --user jane@example.com
+  osticket hold remove --user jane@example.com`,
+	}
+
+	// hold add
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Place a ticket or user under legal hold",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			h := holdFlags(cmd)
+			if h.Kind == hold.User {
+				h.UserID = app.userIDByEmail(cmd.Context(), app.client(cmd.Context()), h.Value)
+			}
+			h.Reason, _ = cmd.Flags().GetString("reason")
+			h.Added = time.Now()
+			h.AddedBy = operatorName()
+
+			holds := app.loadHolds()
+			if !holds.Add(h) {
+				fmt.Fprintln(app.Out, yellow(h.String()+" is already on hold"))
+				return
+			}
+			app.saveHolds(holds)
+			fmt.Fprintln(app.Out, green("✓ Legal hold placed on "+h.String()))
+		},
+	}
+	addHoldFlags(addCmd)
+	addCmd.Flags().String("reason", "", "Reason for the hold, e.g. a case reference")
+	cmd.AddCommand(addCmd)
+
+	// hold remove
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Release a legal hold",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			h := holdFlags(cmd)
+			holds := app.loadHolds()
+			if !holds.Remove(h.Kind, h.Value) {
+				fmt.Fprintln(app.Out, yellow("No legal hold on "+h.String()))
+				return
+			}
+			app.saveHolds(holds)
+			fmt.Fprintln(app.Out, green("✓ Legal hold released on "+h.String()))
+		},
+	}
+	addHoldFlags(removeCmd)
+	cmd.AddCommand(removeCmd)
+
+	// hold list
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List legal holds",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			holds := app.loadHolds()
+			app.render(output.Table, &output.Result{
+				Value: holds,
+				Rows:  holds.Holds,
+				Table: func(w io.Writer) {
+					displayHolds(w, holds.Holds)
+				},
+			})
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// addHoldFlags registers the target flags shared by hold add and remove
+func addHoldFlags(cmd *cobra.Command) {
+	cmd.Flags().String("ticket", "", "Ticket ID or number")
+	cmd.Flags().String("user", "", "User email; covers all of the user's tickets")
+	cmd.MarkFlagsOneRequired("ticket", "user")
+	cmd.MarkFlagsMutuallyExclusive("ticket", "user")
+}
+
+// holdFlags reads the target flags of hold add and remove
+func holdFlags(cmd *cobra.Command) hold.Hold {
+	if ticket, _ := cmd.Flags().GetString("ticket"); ticket != "" {
+		return hold.Hold{Kind: hold.Ticket, Value: ticket}
+	}
+	email, _ := cmd.Flags().GetString("user")
+	return hold.Hold{Kind: hold.User, Value: mustValidate(validate.Email("user", email))}
+}
+
+// loadHolds reads the active profile's legal holds, exiting on error so a
+// destructive command never runs without them
+func (app *App) loadHolds() *hold.List {
+	holds, err := hold.Load(config.GetHoldPath())
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	return holds
+}
+
+// activeHolds is loadHolds for commands that check tickets against the
+// holds. It looks up the user ID of user holds saved without one, exiting
+// when that fails rather than let a hold match nothing.
+func (app *App) activeHolds(ctx context.Context, client osticket.OSTicketAPI) *hold.List {
+	holds := app.loadHolds()
+	resolved := false
+	for i, h := range holds.Holds {
+		if h.Kind == hold.User && h.UserID == 0 {
+			holds.Holds[i].UserID = app.userIDByEmail(ctx, client, h.Value)
+			resolved = true
+		}
+	}
+	if resolved {
+		app.saveHolds(holds)
+	}
+	return holds
+}
+
+func (app *App) saveHolds(holds *hold.List) {
+	if err := holds.Save(config.GetHoldPath()); err != nil {
+		fmt.Fprintln(app.Err, red("Error saving legal holds:"), err)
+		exit(1)
+	}
+}
+
+func displayHolds(w io.Writer, holds []hold.Hold) {
+	if len(holds) == 0 {
+		fmt.Fprintln(w, yellow("No legal holds"))
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	header := []string{"Kind", "Value", "Reason", "Added", "By"}
+	table.SetHeader(header)
+	colors := make([]tablewriter.Colors, len(header))
+	for i := range colors {
+		colors[i] = tablewriter.Colors{tablewriter.FgCyanColor}
+	}
+	table.SetHeaderColor(colors...)
+	for _, h := range holds {
+		table.Append([]string{h.Kind, h.Value, h.Reason, h.Added.Local().Format("2006-01-02 15:04"), h.AddedBy})
+	}
+	table.Render()
+}
+
+// checkNotHeld fetches a ticket by ID or number and exits when it is under
+// legal hold, for commands that archive or delete tickets
+func (app *App) checkNotHeld(ctx context.Context, client osticket.OSTicketAPI, id string) map[string]interface{} {
+	data, err := client.GetTicket(ctx, id)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if len(data.Tickets) == 0 {
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("ticket %s not found", id))
+		exit(exitNotFound)
+	}
+	t := data.Tickets[0]
+
+	target := hold.Target{TicketID: mapInt(t, "ticket_id"), Number: mapString(t, "number"), UserID: mapInt(t, "user_id")}
+	if h, ok := app.activeHolds(ctx, client).Match(target); ok {
+		msg := fmt.Sprintf("ticket %s is under legal hold (%s", id, h)
+		if h.Reason != "" {
+			msg += ": " + h.Reason
+		}
+		fmt.Fprintln(app.Err, red("Error:"), msg+")")
+		fmt.Fprintf(app.Err, "Release it first with: osticket hold remove --%s %s\n", h.Kind, h.Value)
+		exit(1)
+	}
+	return t
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// ==================== IGNORE RULES ====================
+
+func (app *App) ignoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ignore",
+		Short: "Manage tickets that watch, notify and webhook modes skip",
+		Long: `Ignore rules stop known noise, such as test tickets or monitoring
+self-tests, from reaching the terminal, desktop notifications or webhooks
+in 'ticket watch'. Rules are stored per profile.
+
+  osticket config ignore add --subject '^\[monitor\]' --sender @nagios.example.com
+  osticket config ignore add --dept 7 --number 100042`,
+	}
+
+	// config ignore add
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add ignore rules",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			rules := config.GetIgnoreRules()
+			rules.Add(ignoreFlags(cmd))
+			if err := config.SetIgnoreRules(rules); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving ignore rules:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Ignore rules saved"))
+		},
+	}
+	addIgnoreFlags(addCmd)
+	cmd.AddCommand(addCmd)
+
+	// config ignore remove
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove ignore rules",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			rules := config.GetIgnoreRules()
+			removed := rules.Remove(ignoreFlags(cmd))
+			if removed == 0 {
+				fmt.Fprintln(app.Out, yellow("No matching ignore rules"))
+				return
+			}
+			if err := config.SetIgnoreRules(rules); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving ignore rules:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green(fmt.Sprintf("✓ Removed %d ignore rule(s)", removed)))
+		},
+	}
+	addIgnoreFlags(removeCmd)
+	cmd.AddCommand(removeCmd)
+
+	// config ignore list
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List ignore rules",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			rules := config.GetIgnoreRules()
+
+			type ruleRow struct {
+				Kind  string `json:"kind"`
+				Value string `json:"value"`
+			}
+			var rows []ruleRow
+			for _, n := range rules.Numbers {
+				rows = append(rows, ruleRow{"number", n})
+			}
+			for _, s := range rules.Subjects {
+				rows = append(rows, ruleRow{"subject", s})
+			}
+			for _, s := range rules.Senders {
+				rows = append(rows, ruleRow{"sender", s})
+			}
+			for _, d := range rules.Departments {
+				rows = append(rows, ruleRow{"dept", strconv.Itoa(d)})
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: rules,
+				Rows:  rows,
+				Table: func(w io.Writer) {
+					if len(rows) == 0 {
+						fmt.Fprintln(w, yellow("No ignore rules"))
+						return
+					}
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"Kind", "Value"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+					for _, r := range rows {
+						table.Append([]string{r.Kind, r.Value})
+					}
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// addIgnoreFlags registers the rule flags shared by ignore add and remove
+func addIgnoreFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("number", nil, "Ticket number (repeatable)")
+	cmd.Flags().StringArray("subject", nil, "Regular expression matched against the subject (repeatable)")
+	cmd.Flags().StringArray("sender", nil, "Sender email, or @domain for a whole domain (repeatable)")
+	cmd.Flags().IntSlice("dept", nil, "Department ID (repeatable)")
+	cmd.MarkFlagsOneRequired("number", "subject", "sender", "dept")
+}
+
+// ignoreFlags reads the rule flags of ignore add and remove
+func ignoreFlags(cmd *cobra.Command) ignore.Rules {
+	var r ignore.Rules
+	r.Numbers, _ = cmd.Flags().GetStringArray("number")
+	r.Subjects, _ = cmd.Flags().GetStringArray("subject")
+	r.Senders, _ = cmd.Flags().GetStringArray("sender")
+	r.Departments, _ = cmd.Flags().GetIntSlice("dept")
+	return r
+}
+
+// ignoreMatcher compiles the active profile's ignore rules, exiting when
+// a stored rule is invalid
+func (app *App) ignoreMatcher() *ignore.Matcher {
+	m, err := config.GetIgnoreRules().Compile()
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error in ignore rules:"), err)
+		exit(1)
+	}
+	return m
+}
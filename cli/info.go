@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// ==================== INFO COMMANDS ====================
+
+func (app *App) infoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Get system information",
+	}
+
+	// info departments
+	deptCmd := &cobra.Command{
+		Use:   "departments",
+		Short: "List all departments",
+		Example: `  osticket info departments
+  osticket --no-cache info departments -o json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetDepartments(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Departments,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Name"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, dept := range data.Departments {
+						table.Append([]string{strconv.Itoa(dept.ID), dept.Name})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(deptCmd)
+
+	// info topics
+	topicsCmd := &cobra.Command{
+		Use:     "topics",
+		Short:   "List all help topics",
+		Example: `  osticket info topics`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetTopics(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Topics,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Topic"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, topic := range data.Topics {
+						table.Append([]string{strconv.Itoa(topic.TopicID), topic.Topic})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(topicsCmd)
+
+	// info sla
+	slaCmd := &cobra.Command{
+		Use:     "sla",
+		Short:   "List all SLA plans",
+		Example: `  osticket info sla`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetSLAs(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.SLA,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Name", "Grace Period"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, sla := range data.SLA {
+						table.Append([]string{
+							strconv.Itoa(sla.ID),
+							sla.Name,
+							strconv.Itoa(sla.GracePeriod),
+						})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(slaCmd)
+
+	// info teams
+	teamsCmd := &cobra.Command{
+		Use:   "teams",
+		Short: "List all teams",
+		Long: `List the agent teams of this instance, with the IDs and names that
+ticket assign --team and ticket close --team take.`,
+		Example: `  osticket info teams`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetTeams(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Teams,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Name"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, team := range data.Teams {
+						table.Append([]string{strconv.Itoa(team.ID), team.Name})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(teamsCmd)
+
+	// info priorities
+	prioritiesCmd := &cobra.Command{
+		Use:   "priorities",
+		Short: "List all ticket priorities",
+		Long: `List the ticket priorities of this instance, most urgent first, with the
+IDs that ticket create --priority takes.`,
+		Example: `  osticket info priorities`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetPriorities(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			sort.SliceStable(data.Priorities, func(i, j int) bool {
+				return data.Priorities[i].Urgency < data.Priorities[j].Urgency
+			})
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Priorities,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Name", "Description", "Urgency"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, p := range data.Priorities {
+						table.Append([]string{
+							strconv.Itoa(p.ID),
+							p.Name,
+							p.Description,
+							strconv.Itoa(p.Urgency),
+						})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(prioritiesCmd)
+
+	// info statuses
+	statusesCmd := &cobra.Command{
+		Use:   "statuses",
+		Short: "List all ticket statuses",
+		Long: `List the ticket statuses of this instance, including custom ones such as
+"On Hold", with the IDs that --status takes and the state each one maps to.`,
+		Example: `  osticket info statuses`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetStatuses(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Statuses,
+				Table: func(w io.Writer) {
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Name", "State"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, s := range data.Statuses {
+						table.Append([]string{
+							strconv.Itoa(s.ID),
+							s.Name,
+							s.State,
+						})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(statusesCmd)
+
+	// info server
+	serverCmd := &cobra.Command{
+		Use:   "server",
+		Short: "Show server version and which API features respond",
+		Long: `Run a set of read-only probe queries and report the osTicket and API plugin
+versions (when the plugin exposes them) along with which features respond and
+how long each took. Useful to attach to support escalations.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			info, err := client.ServerInfo(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: info,
+				Rows:  info.Probes,
+				Table: func(w io.Writer) {
+					unknown := func(s string) string {
+						if s == "" {
+							return yellow("unknown")
+						}
+						return s
+					}
+					fmt.Fprintf(w, "%s %s\n", cyan("Server:"), info.BaseURL)
+					fmt.Fprintf(w, "%s %s\n", cyan("osTicket:"), unknown(info.OSTicketVersion))
+					fmt.Fprintf(w, "%s %s\n", cyan("API plugin:"), unknown(info.PluginVersion))
+					fmt.Fprintf(w, "%s %s\n", cyan("PHP:"), unknown(info.PHPVersion))
+					fmt.Fprintf(w, "%s %s\n", cyan("Database:"), unknown(info.Database))
+					for _, key := range info.ExtraKeys() {
+						fmt.Fprintf(w, "%s %s\n", cyan(key+":"), info.Extra[key])
+					}
+					fmt.Fprintln(w)
+
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"Feature", "Query", "Status", "Latency"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+
+					for _, probe := range info.Probes {
+						status := green("ok")
+						if !probe.OK {
+							status = red(truncate(probe.Error, 50))
+						}
+						table.Append([]string{
+							probe.Name,
+							probe.Query + "/" + probe.Condition,
+							status,
+							probe.Latency.Round(time.Millisecond).String(),
+						})
+					}
+
+					table.Render()
+				},
+			})
+		},
+	}
+	cmd.AddCommand(serverCmd)
+
+	return cmd
+}
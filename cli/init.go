@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== SETUP ====================
+
+// probeTimeout bounds each request while looking for the API endpoint, so
+// a candidate that hangs does not stall the search
+const probeTimeout = 10 * time.Second
+
+func (app *App) initCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init [site-url]",
+		Short: "Set up the CLI for an osTicket site",
+		Long: `Set up the active profile from just the site's address, such as
+https://support.example.com. init tries the address itself and then the
+paths the API plugin is commonly installed at (/ost_wbs/, /api/ost_wbs/,
+/api/ and others) with a read-only call, and stores the first that answers
+as the plugin, with the API key.
+
+Use --path for an endpoint at a custom route, such as one behind a
+gateway; it is tried before the common paths. The site and key are
+prompted for when not given; the key may also come from --api-key or
+` + config.EnvAPIKey + `.
+
+When an endpoint answers but rejects the key, its URL is stored and the
+key is not. init exits with status 2 when no endpoint is found, and 5 when
+the site cannot be reached.`,
+		Example: `  osticket init https://support.example.com --key YOUR_API_KEY
+  osticket init support.example.com --path /helpdesk/api/wbs/
+  osticket --profile staging init https://staging.example.com`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			key, _ := cmd.Flags().GetString("key")
+			paths, _ := cmd.Flags().GetStringArray("path")
+			useKeyring, _ := cmd.Flags().GetBool("keyring")
+
+			var site string
+			var err error
+			if len(args) == 1 {
+				site = args[0]
+			} else if site, err = app.promptRequired("osTicket site URL"); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			if key == "" {
+				key = config.GetAPIKey()
+			}
+			if key == "" {
+				if key, err = app.promptRequired("API key"); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+			}
+
+			fmt.Fprintf(app.Out, "Looking for the API plugin under %s...\n", site)
+			d, err := app.discover(cmd.Context(), site, key, paths)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitValidation)
+			}
+			switch {
+			case d.URL == "" && d.Err != nil:
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("could not reach %s: %v", site, d.Err))
+				exit(exitCode(d.Err))
+			case d.URL == "":
+				fmt.Fprintln(app.Err, red("Error:"), "no API plugin endpoint found; check that the plugin is installed and enabled, or give its path with --path")
+				exit(exitNotFound)
+			}
+
+			if err := config.SetBaseURL(d.URL); err != nil {
+				fmt.Fprintln(app.Err, red("Error setting URL:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Base URL set to "+d.URL))
+			if d.Err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("the endpoint rejected the probe: %v", d.Err))
+				fmt.Fprintln(app.Err, "The API key was not stored. Fix it, then run: osticket config set --key <apiKey>")
+				exit(exitCode(d.Err))
+			}
+
+			store, err := config.SetAPIKey(key, useKeyring)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error setting API key:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ API key stored in "+store))
+		},
+	}
+	cmd.Flags().String("key", "", "osTicket API key (prompted for when not set)")
+	cmd.Flags().StringArray("path", nil, "Custom endpoint path to try first (repeatable)")
+	cmd.Flags().Bool("keyring", false, "Require the OS keyring instead of falling back to the encrypted file")
+	return cmd
+}
+
+// discover looks for the API endpoint under a site, over the profile's
+// connection, printing each candidate tried
+func (app *App) discover(ctx context.Context, site, key string, paths []string) (*osticket.Discovery, error) {
+	d, err := osticket.Discover(ctx, site, key, paths,
+		osticket.WithConnection(app.connection()), osticket.WithTimeout(probeTimeout))
+	if err != nil {
+		return nil, err
+	}
+	for _, attempt := range d.Attempts {
+		switch {
+		case attempt.URL == d.URL:
+			fmt.Fprintf(app.Out, "  %s %s\n", green("✓"), attempt.URL)
+		case attempt.Plugin:
+			fmt.Fprintf(app.Out, "  %s %s: %s\n", yellow("?"), attempt.URL, attempt.Error)
+		default:
+			fmt.Fprintf(app.Out, "  %s %s: %s\n", red("✗"), attempt.URL, truncate(attempt.Error, 120))
+		}
+	}
+	return d, nil
+}
+
+// testEndpoint checks the profile's endpoint. When it answers with
+// something other than the plugin, it looks under the site for the
+// endpoint and, with fix, stores the one found.
+func (app *App) testEndpoint(ctx context.Context, paths []string, fix bool) error {
+	client := app.client(ctx)
+	start := time.Now()
+	err := client.Ping(ctx)
+	if err == nil {
+		fmt.Fprintf(app.Out, "%s %s answered as the API plugin in %s\n", green("✓"), baseURL(client), time.Since(start).Round(time.Millisecond))
+		return nil
+	}
+	fmt.Fprintf(app.Out, "%s %s: %v\n", red("✗"), baseURL(client), err)
+
+	// Searching the site needs the endpoint and key of the HTTP client
+	real, ok := client.(*osticket.Client)
+	var apiErr *osticket.Error
+	if !ok || !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrNotAPI && apiErr.Kind != osticket.ErrNotFound {
+		return err
+	}
+	site := osticket.SiteURL(real.BaseURL)
+	fmt.Fprintf(app.Out, "\nLooking for the API plugin under %s...\n", site)
+	d, derr := app.discover(ctx, site, real.APIKey, paths)
+	if derr != nil {
+		return derr
+	}
+	switch {
+	case d.URL == "" && d.Err != nil:
+		return err
+	case d.URL == "":
+		fmt.Fprintln(app.Out, yellow("No API plugin endpoint found; check that the plugin is installed and enabled, or give its path with --path"))
+		return err
+	case !fix:
+		fmt.Fprintf(app.Out, "The API plugin answers at %s. Run 'osticket config test --fix' to store it.\n", d.URL)
+		return err
+	}
+	if serr := config.SetBaseURL(d.URL); serr != nil {
+		return serr
+	}
+	fmt.Fprintln(app.Out, green("✓ Base URL set to "+d.URL))
+	return d.Err
+}
@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ==================== CLI SCHEMA ====================
+
+// Schema describes the command tree for programs driving the CLI
+type Schema struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	GlobalFlags []FlagSchema    `json:"global_flags"`
+	Commands    []CommandSchema `json:"commands"`
+}
+
+// CommandSchema describes one command. Commands that only group others
+// are not Runnable.
+type CommandSchema struct {
+	Path     string       `json:"path"`
+	Usage    string       `json:"usage"`
+	Short    string       `json:"short"`
+	Long     string       `json:"long,omitempty"`
+	Example  string       `json:"example,omitempty"`
+	Group    string       `json:"group,omitempty"`
+	Aliases  []string     `json:"aliases,omitempty"`
+	Runnable bool         `json:"runnable"`
+	Args     []ArgSchema  `json:"args"`
+	Flags    []FlagSchema `json:"flags"`
+	// OneRequired and Exclusive list flag sets of which at least one,
+	// or at most one, must be given
+	OneRequired [][]string `json:"one_required,omitempty"`
+	Exclusive   [][]string `json:"exclusive,omitempty"`
+}
+
+// ArgSchema is a positional argument, taken from the command's usage line
+type ArgSchema struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Repeated bool   `json:"repeated"`
+}
+
+// FlagSchema describes a flag. Type is the pflag type, e.g. string, int,
+// bool, duration, stringArray or intSlice; Default is typed for bool and
+// numeric flags and a string otherwise.
+type FlagSchema struct {
+	Name       string      `json:"name"`
+	Shorthand  string      `json:"shorthand,omitempty"`
+	Type       string      `json:"type"`
+	Default    interface{} `json:"default"`
+	Usage      string      `json:"usage"`
+	Required   bool        `json:"required"`
+	Repeatable bool        `json:"repeatable"`
+	Deprecated string      `json:"deprecated,omitempty"`
+}
+
+// Cobra's annotations for MarkFlagsOneRequired and
+// MarkFlagsMutuallyExclusive
+const (
+	oneRequiredAnnotation = "cobra_annotation_one_required"
+	exclusiveAnnotation   = "cobra_annotation_mutually_exclusive"
+)
+
+func (app *App) metaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Describe the CLI itself",
+	}
+
+	// meta schema
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print every command, argument and flag as JSON",
+		Long: `Print a description of every command: its usage, positional arguments and
+flags with their type, default and whether they are required. Tools that
+wrap the CLI (web forms, scripts, agents) can build invocations from it
+instead of parsing help text.
+
+  osticket meta schema > osticket-schema.json
+  osticket meta schema | jq '.commands[] | select(.path == "osticket ticket create") | .flags'
+
+Global flags apply to every command and are listed once.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			schema := buildSchema(cmd.Root())
+			app.render(output.JSON, &output.Result{
+				Value: schema,
+				Rows:  schema.Commands,
+				Table: func(w io.Writer) { displaySchema(w, schema) },
+			})
+		},
+	}
+	cmd.AddCommand(schemaCmd)
+
+	return cmd
+}
+
+// buildSchema describes root and every available command below it
+func buildSchema(root *cobra.Command) *Schema {
+	schema := &Schema{
+		Name:        root.Name(),
+		Version:     root.Version,
+		GlobalFlags: flagSchemas(root.PersistentFlags()),
+		Commands:    []CommandSchema{},
+	}
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, sub := range c.Commands() {
+			if !sub.IsAvailableCommand() && sub.Name() != "help" {
+				continue
+			}
+			schema.Commands = append(schema.Commands, CommandSchema{
+				Path:        sub.CommandPath(),
+				Usage:       sub.UseLine(),
+				Short:       sub.Short,
+				Long:        sub.Long,
+				Example:     sub.Example,
+				Group:       sub.GroupID,
+				Aliases:     sub.Aliases,
+				Runnable:    sub.Runnable() && sub.Annotations[groupingAnnotation] == "",
+				Args:        argSchemas(sub.Use),
+				Flags:       flagSchemas(sub.LocalNonPersistentFlags()),
+				OneRequired: flagGroups(sub, oneRequiredAnnotation),
+				Exclusive:   flagGroups(sub, exclusiveAnnotation),
+			})
+			walk(sub)
+		}
+	}
+	walk(root)
+	return schema
+}
+
+// argSchemas parses the arguments of a Use line such as
+// "drop [id...]" or "add-user <orgId>"
+func argSchemas(use string) []ArgSchema {
+	args := []ArgSchema{}
+	fields := strings.Fields(use)
+	for _, f := range fields[1:] {
+		required := strings.HasPrefix(f, "<")
+		if !required && !strings.HasPrefix(f, "[") {
+			continue
+		}
+		name := strings.Trim(f, "<>[]")
+		repeated := strings.HasSuffix(name, "...")
+		args = append(args, ArgSchema{
+			Name:     strings.TrimSuffix(name, "..."),
+			Required: required,
+			Repeated: repeated,
+		})
+	}
+	return args
+}
+
+// flagSchemas describes the visible flags of a set
+func flagSchemas(flags *pflag.FlagSet) []FlagSchema {
+	out := []FlagSchema{}
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden && f.Deprecated == "" {
+			return
+		}
+		typ := f.Value.Type()
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		out = append(out, FlagSchema{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       typ,
+			Default:    flagDefault(typ, f.DefValue),
+			Usage:      f.Usage,
+			Required:   required,
+			Repeatable: strings.HasSuffix(typ, "Slice") || strings.HasSuffix(typ, "Array") || typ == "strings",
+			Deprecated: f.Deprecated,
+		})
+	})
+	return out
+}
+
+// flagDefault converts a flag's default to its JSON type where it has one
+func flagDefault(typ, value string) interface{} {
+	switch typ {
+	case "bool":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "int", "int64", "int32", "uint", "uint64":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "float64", "float32":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	if value == "[]" {
+		return []string{}
+	}
+	return value
+}
+
+// flagGroups returns the flag groups a command marked with annotation
+func flagGroups(cmd *cobra.Command, annotation string) [][]string {
+	seen := map[string]bool{}
+	var groups [][]string
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		for _, group := range f.Annotations[annotation] {
+			if seen[group] {
+				continue
+			}
+			seen[group] = true
+			names := strings.Split(group, " ")
+			sort.Strings(names)
+			groups = append(groups, names)
+		}
+	})
+	return groups
+}
+
+// displaySchema lists the commands with their arguments and flag names
+func displaySchema(w io.Writer, schema *Schema) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Command", "Args", "Flags"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+	for _, c := range schema.Commands {
+		if !c.Runnable {
+			continue
+		}
+		args := make([]string, len(c.Args))
+		for i, a := range c.Args {
+			args[i] = a.Name
+		}
+		flags := make([]string, len(c.Flags))
+		for i, f := range c.Flags {
+			flags[i] = "--" + f.Name
+		}
+		table.Append([]string{c.Path, strings.Join(args, " "), strings.Join(flags, " ")})
+	}
+	table.Render()
+	fmt.Fprintf(w, "\n%d global flags apply to every command; use -o json for the full schema.\n", len(schema.GlobalFlags))
+}
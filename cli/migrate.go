@@ -0,0 +1,560 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/markup"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET MIGRATION ====================
+
+// dumpRecord is one line of a ticket dump: the ticket with the names of
+// its department, help topic, status and priority, its thread, and its
+// user
+type dumpRecord struct {
+	Ticket map[string]interface{} `json:"ticket"`
+	Thread []osticket.ThreadEntry `json:"thread"`
+	User   *dumpUser              `json:"user,omitempty"`
+}
+
+// dumpUser is the user a ticket belongs to, matched by email on import
+type dumpUser struct {
+	ID    int    `json:"user_id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// importMap records what an import has done, so a repeated import carries
+// on where the previous one stopped. It maps the dump's IDs to the new
+// instance's.
+type importMap struct {
+	Tickets map[int]*importedTicket `json:"tickets"`
+	Users   map[int]int             `json:"users"`
+}
+
+// importedTicket is the progress of one ticket: its new ID, how many of
+// its thread entries exist there, and whether it is complete
+type importedTicket struct {
+	ID      int  `json:"ticket_id"`
+	Entries int  `json:"entries"`
+	Done    bool `json:"done"`
+}
+
+// exportTicketsCmd is 'export tickets'
+func (app *App) exportTicketsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tickets",
+		Short: "Dump tickets with their threads and users for a migration or backup",
+		Long: `Write tickets to a file of newline-delimited JSON, one ticket per line with
+its thread and user, to load into another instance with 'osticket import
+tickets'. Department, help topic, status and priority names are included
+so the import can match them by name.
+
+  osticket export tickets --from 2024-01-01 --to 2024-12-31 --out dump.jsonl
+
+An interrupted export is resumed by running it again: tickets already in
+--out are skipped. --restart starts a new dump instead.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			status, _ := cmd.Flags().GetInt("status")
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+			path, _ := cmd.Flags().GetString("out")
+			restart, _ := cmd.Flags().GetBool("restart")
+			if (from == "") != (to == "") {
+				fmt.Fprintln(app.Err, red("Error:"), "--from and --to must be used together")
+				exit(1)
+			}
+
+			done := map[int]bool{}
+			if !restart {
+				var err error
+				if done, err = resumeDump(path); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+				if len(done) > 0 {
+					fmt.Fprintf(app.Err, "Resuming: %d ticket(s) already in %s\n", len(done), path)
+				}
+			}
+			flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+			if restart {
+				flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+			}
+			f, err := os.OpenFile(path, flags, 0600)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			defer f.Close()
+
+			ctx := cmd.Context()
+			client := app.client(ctx)
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				if from != "" {
+					return client.GetTicketsByDateRange(ctx, from, to, p)
+				}
+				return client.GetTicketsByStatus(ctx, status, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			var tickets []map[string]interface{}
+			for _, t := range data.Tickets {
+				if !done[mapInt(t, "ticket_id")] {
+					tickets = append(tickets, t)
+				}
+			}
+			app.addTicketNames(ctx, client, tickets, true)
+			priorities := map[int]string{}
+			if choices, err := idChoices(ctx, client, "priority"); err == nil {
+				for _, c := range choices {
+					priorities[c.ID] = c.Name
+				}
+			}
+
+			failures := newFailures(cmd, "ticket", len(tickets))
+			users := map[int]*dumpUser{}
+			var written []string
+			for i, t := range tickets {
+				id := mapInt(t, "ticket_id")
+				if name := priorities[mapInt(t, "priority_id")]; name != "" {
+					t["priority_name"] = name
+				}
+				record, err := dumpTicket(ctx, client, t, users)
+				if err == nil {
+					err = writeDumpRecord(f, record)
+				}
+				if err != nil {
+					if failures.Add(strconv.Itoa(id), "export", err) {
+						failures.Skip(len(tickets) - i - 1)
+						break
+					}
+					continue
+				}
+				written = append(written, strconv.Itoa(id))
+				app.logger().Debug(fmt.Sprintf("exported ticket %d (%d/%d)", id, i+1, len(tickets)))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"file": path, "exported": len(written), "previously_exported": len(done)},
+				IDs:   written,
+				Table: func(w io.Writer) {
+					fmt.Fprintf(w, "%s %d ticket(s) written to %s\n", green("✓"), len(written), path)
+				},
+			})
+			app.reportFailures(output.Table, failures)
+		},
+	}
+	cmd.Flags().Int("status", 0, "Filter by status (0=all, 1=open, 2=resolved, 3=closed)")
+	cmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	cmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	cmd.Flags().String("out", "", "File to write the dump to (required)")
+	cmd.Flags().Bool("restart", false, "Start a new dump instead of resuming the one in --out")
+	addFailFastFlag(cmd)
+	cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+// dumpTicket loads a ticket's thread and user. users caches the users
+// already loaded, as customers often have several tickets.
+func dumpTicket(ctx context.Context, client osticket.OSTicketAPI, t map[string]interface{}, users map[int]*dumpUser) (*dumpRecord, error) {
+	id := mapInt(t, "ticket_id")
+	thread, err := client.GetTicketThread(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	record := &dumpRecord{Ticket: t, Thread: thread.Entries}
+
+	userID := mapInt(t, "user_id")
+	if userID == 0 {
+		return record, nil
+	}
+	if user, ok := users[userID]; ok {
+		record.User = user
+		return record, nil
+	}
+	data, err := client.GetUserByID(ctx, strconv.Itoa(userID))
+	if err != nil {
+		return nil, fmt.Errorf("could not load user %d: %w", userID, err)
+	}
+	if len(data.Users) > 0 {
+		u := data.Users[0]
+		users[userID] = &dumpUser{ID: userID, Name: u.Name, Email: u.Email}
+	}
+	record.User = users[userID]
+	return record, nil
+}
+
+// writeDumpRecord appends a record as one line, synced so that a crash
+// loses at most the line being written
+func writeDumpRecord(f *os.File, record *dumpRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write %s: %w", f.Name(), err)
+	}
+	return f.Sync()
+}
+
+// resumeDump returns the IDs of the tickets in an existing dump, cutting
+// off a last line left incomplete by an interrupted export. A missing file
+// is an empty dump.
+func resumeDump(path string) (map[int]bool, error) {
+	ids := map[int]bool{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	complete := 0
+	for complete < len(data) {
+		end := bytes.IndexByte(data[complete:], '\n')
+		if end < 0 {
+			break
+		}
+		var record dumpRecord
+		if err := json.Unmarshal(data[complete:complete+end], &record); err != nil {
+			return nil, fmt.Errorf("%s is not a ticket dump: %w (use --restart to overwrite it)", path, err)
+		}
+		ids[mapInt(record.Ticket, "ticket_id")] = true
+		complete += end + 1
+	}
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+func (app *App) importCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import data from another osTicket instance",
+	}
+
+	// import tickets
+	ticketsCmd := &cobra.Command{
+		Use:   "tickets <dump.jsonl>",
+		Short: "Create tickets from a dump made with 'export tickets'",
+		Long: `Create the tickets of a dump in this instance. Users are matched by email
+and created when missing; departments, help topics, statuses and
+priorities are matched by name, falling back to the server's defaults.
+
+The first message opens the ticket. Later messages, responses and notes
+are added as internal notes headed with their poster and date, so that
+nobody is emailed about old conversations.
+
+Progress goes to a mapping file of old to new ticket and user IDs
+(default: <dump>.map.json). An interrupted import is resumed by running
+it again; the mapping file tells which tickets are already done.
+
+  osticket import tickets dump.jsonl --staff-id 1`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := args[0]
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			mapPath, _ := cmd.Flags().GetString("map")
+			if mapPath == "" {
+				mapPath = strings.TrimSuffix(path, ".jsonl") + ".map.json"
+			}
+
+			records, err := readDump(path)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			ids, err := loadImportMap(mapPath)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "cannot import in offline mode")
+				exit(1)
+			}
+			ctx := cmd.Context()
+			client := app.client(ctx)
+			// An import needs the IDs the server assigns, so it fails rather
+			// than queue
+			if c, ok := client.(*osticket.Client); ok {
+				c.QueueOnFailure = false
+			}
+			imp := &ticketImport{app: app, client: client, ids: ids, path: mapPath, staffID: staffID, names: map[string]map[string]int{}}
+			var todo []dumpRecord
+			for _, r := range records {
+				if t := ids.Tickets[mapInt(r.Ticket, "ticket_id")]; t == nil || !t.Done {
+					todo = append(todo, r)
+				}
+			}
+			if skipped := len(records) - len(todo); skipped > 0 {
+				fmt.Fprintf(app.Err, "Resuming: %d ticket(s) already imported\n", skipped)
+			}
+
+			failures := newFailures(cmd, "ticket", len(todo))
+			var created []string
+			for i, r := range todo {
+				oldID := mapInt(r.Ticket, "ticket_id")
+				newID, err := imp.ticket(ctx, r)
+				if err != nil {
+					if failures.Add(strconv.Itoa(oldID), "import", err) {
+						failures.Skip(len(todo) - i - 1)
+						break
+					}
+					continue
+				}
+				created = append(created, strconv.Itoa(newID))
+				app.logger().Debug(fmt.Sprintf("imported ticket %d as %d (%d/%d)", oldID, newID, i+1, len(todo)))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"map": mapPath, "imported": len(created), "previously_imported": len(records) - len(todo)},
+				IDs:   created,
+				Table: func(w io.Writer) {
+					fmt.Fprintf(w, "%s %d ticket(s) imported; ID mapping in %s\n", green("✓"), len(created), mapPath)
+				},
+			})
+			app.reportFailures(output.Table, failures)
+		},
+	}
+	ticketsCmd.Flags().Int("staff-id", 0, "Staff ID adding the thread entries as notes (required)")
+	ticketsCmd.Flags().String("map", "", "Mapping file of old to new IDs, which also records progress (default: <dump>.map.json)")
+	addFailFastFlag(ticketsCmd)
+	ticketsCmd.MarkFlagRequired("staff-id")
+	cmd.AddCommand(ticketsCmd)
+
+	return cmd
+}
+
+// readDump reads the records of a ticket dump
+func readDump(path string) ([]dumpRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []dumpRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r dumpRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid record: %w", path, line, err)
+		}
+		if mapInt(r.Ticket, "ticket_id") == 0 {
+			return nil, fmt.Errorf("%s:%d: record has no ticket_id", path, line)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// loadImportMap reads a mapping file; a missing file is a new import
+func loadImportMap(path string) (*importMap, error) {
+	ids := &importMap{Tickets: map[int]*importedTicket{}, Users: map[int]int{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read mapping file: %w", err)
+	}
+	if err := json.Unmarshal(data, ids); err != nil {
+		return nil, fmt.Errorf("invalid mapping file %s: %w", path, err)
+	}
+	if ids.Tickets == nil {
+		ids.Tickets = map[int]*importedTicket{}
+	}
+	if ids.Users == nil {
+		ids.Users = map[int]int{}
+	}
+	return ids, nil
+}
+
+// ticketImport creates the tickets of a dump, saving the mapping file
+// after every change
+type ticketImport struct {
+	app     *App
+	client  osticket.OSTicketAPI
+	ids     *importMap
+	path    string
+	staffID int
+	// names caches the IDs of department, topic, status and priority
+	// names on this instance
+	names map[string]map[string]int
+}
+
+// ticket imports one record, carrying on from what the mapping file says
+// was done, and returns the new ticket ID
+func (imp *ticketImport) ticket(ctx context.Context, r dumpRecord) (int, error) {
+	oldID := mapInt(r.Ticket, "ticket_id")
+	progress := imp.ids.Tickets[oldID]
+	if progress == nil {
+		userID, err := imp.user(ctx, r.User)
+		if err != nil {
+			return 0, err
+		}
+		title := mapString(r.Ticket, "subject")
+		body := title
+		entries := 0
+		if len(r.Thread) > 0 && r.Thread[0].Type == osticket.ThreadMessage {
+			body = r.Thread[0].Body
+			entries = 1
+		}
+		id, err := imp.client.CreateTicket(ctx, osticket.CreateTicketParams{
+			Title:      title,
+			Subject:    body,
+			UserID:     userID,
+			PriorityID: imp.id(ctx, r.Ticket, "priority"),
+			DeptID:     imp.id(ctx, r.Ticket, "dept"),
+			TopicID:    imp.id(ctx, r.Ticket, "topic"),
+		})
+		if err != nil {
+			return 0, err
+		}
+		progress = &importedTicket{ID: id, Entries: entries}
+		imp.ids.Tickets[oldID] = progress
+		if err := imp.save(); err != nil {
+			return 0, err
+		}
+	}
+
+	for progress.Entries < len(r.Thread) {
+		e := r.Thread[progress.Entries]
+		if err := imp.client.AddInternalNote(ctx, progress.ID, entryHeading(e), entryNote(e), imp.staffID); err != nil {
+			return 0, err
+		}
+		progress.Entries++
+		if err := imp.save(); err != nil {
+			return 0, err
+		}
+	}
+
+	if status := imp.id(ctx, r.Ticket, "status"); status != 0 {
+		err := imp.client.UpdateTicketStatus(ctx, osticket.UpdateTicketStatusParams{TicketID: progress.ID, StatusID: status, StaffID: imp.staffID})
+		if err != nil {
+			return 0, err
+		}
+	}
+	progress.Done = true
+	return progress.ID, imp.save()
+}
+
+// user returns the ID of a dump's user on this instance: mapped before,
+// found by email, or created
+func (imp *ticketImport) user(ctx context.Context, u *dumpUser) (int, error) {
+	if u == nil || u.Email == "" {
+		return 0, fmt.Errorf("the ticket has no user email to import it with")
+	}
+	if id, ok := imp.ids.Users[u.ID]; ok {
+		return id, nil
+	}
+
+	var id int
+	data, err := imp.client.GetUserByEmail(ctx, u.Email)
+	var apiErr *osticket.Error
+	switch {
+	case err == nil && len(data.Users) > 0:
+		id = data.Users[0].UserID
+	case err == nil, errors.As(err, &apiErr) && apiErr.Kind == osticket.ErrNotFound:
+		name := u.Name
+		if name == "" {
+			name = u.Email
+		}
+		if id, err = imp.client.CreateUser(ctx, osticket.CreateUserParams{Name: name, Email: u.Email}); err != nil {
+			return 0, fmt.Errorf("could not create user %s: %w", u.Email, err)
+		}
+	default:
+		return 0, err
+	}
+	imp.ids.Users[u.ID] = id
+	return id, imp.save()
+}
+
+// id returns the ID on this instance of a ticket's department, help topic,
+// status or priority, matched by the name in the dump. A dump without the
+// name keeps the ID; a name this instance lacks gets the server's default.
+func (imp *ticketImport) id(ctx context.Context, t map[string]interface{}, kind string) int {
+	name := mapString(t, kind+"_name")
+	if name == "" {
+		return mapInt(t, kind+"_id")
+	}
+	if imp.names[kind] == nil {
+		imp.names[kind] = map[string]int{}
+		choices, err := idChoices(ctx, imp.client, kind)
+		if err != nil {
+			imp.app.logger().Warn(fmt.Sprintf("could not load %s names: %v", kind, err))
+		}
+		for _, c := range choices {
+			imp.names[kind][strings.ToLower(c.Name)] = c.ID
+		}
+	}
+	id, ok := imp.names[kind][strings.ToLower(name)]
+	if !ok {
+		imp.app.logger().Warn(fmt.Sprintf("ticket %d: no %s named %q here, using the default", mapInt(t, "ticket_id"), kind, name))
+	}
+	return id
+}
+
+// save writes the mapping file
+func (imp *ticketImport) save() error {
+	if err := writeJSONFile(imp.path, imp.ids); err != nil {
+		return fmt.Errorf("could not save mapping file: %w", err)
+	}
+	return nil
+}
+
+// entryHeading is the title of the note an imported thread entry becomes
+func entryHeading(e osticket.ThreadEntry) string {
+	poster := e.Poster
+	if poster == "" {
+		poster = "(unknown)"
+	}
+	return fmt.Sprintf("%s from %s, %s", e.TypeName(), poster, e.Created)
+}
+
+// entryNote is the body of the note an imported thread entry becomes,
+// with a note's own title kept as its first line
+func entryNote(e osticket.ThreadEntry) string {
+	body := e.Body
+	if strings.TrimSpace(body) == "" {
+		body = "(no text)"
+	}
+	if e.Type != osticket.ThreadNote || e.Title == "" {
+		return body
+	}
+	if e.Format == markup.HTML || markup.IsHTML(body) {
+		return "<p><strong>" + html.EscapeString(e.Title) + "</strong></p>" + body
+	}
+	return e.Title + "\n\n" + body
+}
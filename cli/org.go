@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== ORGANIZATION COMMANDS ====================
+
+func (app *App) orgCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "org",
+		Short: "Manage organizations",
+	}
+
+	// org get
+	getCmd := &cobra.Command{
+		Use:     "get <id>",
+		Short:   "Get an organization",
+		Example: `  osticket org get 3`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid organization ID"))
+				exit(1)
+			}
+
+			data, err := client.GetOrganization(cmd.Context(), id)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.renderOrganizations(data, "No organization found")
+		},
+	}
+	cmd.AddCommand(getCmd)
+
+	// org list
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List all organizations",
+		Example: `  osticket org list -o csv`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetOrganizations(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.renderOrganizations(data, "No organizations found")
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	// org create
+	createCmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create an organization",
+		Example: `  osticket org create --name "Example Inc" --domain example.com,example.org`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			name, _ := cmd.Flags().GetString("name")
+			domain, _ := cmd.Flags().GetString("domain")
+			notes, _ := cmd.Flags().GetString("notes")
+
+			orgID, err := client.CreateOrganization(cmd.Context(), osticket.CreateOrganizationParams{
+				Name:   name,
+				Domain: domain,
+				Notes:  notes,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]int{"org_id": orgID},
+				IDs:   []string{strconv.Itoa(orgID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Organization created successfully!"))
+					fmt.Fprintf(w, "  Organization ID: %d\n", orgID)
+				},
+			})
+		},
+	}
+	createCmd.Flags().String("name", "", "Organization name")
+	createCmd.Flags().String("domain", "", "Email domain(s) whose users join automatically, comma-separated")
+	createCmd.Flags().String("notes", "", "Internal notes")
+	createCmd.MarkFlagRequired("name")
+	cmd.AddCommand(createCmd)
+
+	// org add-user
+	addUserCmd := &cobra.Command{
+		Use:     "add-user <orgId>",
+		Short:   "Add a user to an organization",
+		Example: `  osticket org add-user 3 --email jane@example.com`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			orgID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid organization ID"))
+				exit(1)
+			}
+
+			userID, _ := cmd.Flags().GetInt("user-id")
+			if email, _ := cmd.Flags().GetString("email"); email != "" {
+				data, err := client.GetUserByEmail(cmd.Context(), mustValidate(validate.Email("email", email)))
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error getting user:"), err)
+					exit(1)
+				}
+				if len(data.Users) == 0 {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("no user with email %s", email))
+					exit(1)
+				}
+				userID = data.Users[0].UserID
+			}
+
+			err = client.AddUserToOrganization(cmd.Context(), orgID, userID)
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"status": "success", "org_id": orgID, "user_id": userID},
+				IDs:   []string{strconv.Itoa(userID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green(fmt.Sprintf("\n✓ User %d added to organization %d", userID, orgID)))
+				},
+			})
+		},
+	}
+	addUserCmd.Flags().Int("user-id", 0, "User ID")
+	addUserCmd.Flags().String("email", "", "User email (looked up to find the user ID)")
+	addUserCmd.MarkFlagsOneRequired("user-id", "email")
+	addUserCmd.MarkFlagsMutuallyExclusive("user-id", "email")
+	cmd.AddCommand(addUserCmd)
+
+	return cmd
+}
+
+// orgIDs returns the IDs of organizations, for --quiet
+func orgIDs(orgs []osticket.Organization) []string {
+	ids := make([]string, len(orgs))
+	for i, o := range orgs {
+		ids[i] = strconv.Itoa(o.ID)
+	}
+	return ids
+}
+
+// renderOrganizations renders organizations as a table or the chosen format
+func (app *App) renderOrganizations(data *osticket.OrganizationData, empty string) {
+	app.render(output.Table, &output.Result{
+		Value: data,
+		Rows:  data.Organizations,
+		IDs:   orgIDs(data.Organizations),
+		Table: func(w io.Writer) {
+			if len(data.Organizations) == 0 {
+				fmt.Fprintln(w, yellow(empty))
+				return
+			}
+
+			table := tablewriter.NewWriter(w)
+			table.SetHeader([]string{"ID", "Name", "Domain", "Created"})
+			table.SetHeaderColor(
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+			)
+
+			for _, org := range data.Organizations {
+				table.Append([]string{strconv.Itoa(org.ID), org.Name, org.Domain, org.Created})
+			}
+
+			table.Render()
+		},
+	})
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/multierror"
+	"github.com/osticket-cli-go/internal/offline"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== OUTBOX COMMANDS ====================
+
+func (app *App) outboxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "outbox",
+		Aliases: []string{"queue"},
+		Short:   "Manage changes queued while offline",
+		Long: `Changes are queued in the outbox in offline mode (--offline), and with
+--queue-on-failure when the server cannot be reached. Queued changes are
+sent in order by 'outbox flush' (or 'queue flush').`,
+	}
+
+	// outbox flush
+	flushCmd := &cobra.Command{
+		Use:     "flush",
+		Short:   "Send queued changes to the server",
+		Example: `  osticket outbox flush`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "cannot flush the outbox in offline mode")
+				exit(1)
+			}
+
+			// Replaying queued requests as they were needs the HTTP client
+			client, ok := app.client(cmd.Context()).(*osticket.Client)
+			if !ok {
+				fmt.Fprintln(app.Err, red("Error:"), "flushing the outbox needs the osTicket HTTP client")
+				exit(1)
+			}
+			store := newStore()
+
+			entries, err := store.List()
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			if len(entries) == 0 {
+				fmt.Fprintln(app.Out, yellow("Outbox is empty"))
+				return
+			}
+
+			// Entries are replayed in order and flushing stops at the first
+			// failure so later changes never overtake earlier ones. The
+			// outbox itself is the checkpoint: sent entries are removed.
+			b := newBudget(cmd)
+			for i, entry := range entries {
+				if i > 0 && b.exceeded() {
+					fmt.Fprintf(app.Err, "%s stopped after %s: sent %d, %d of %d change(s) remain queued\n",
+						yellow("⚠ Time budget reached:"), b.limit, i, len(entries)-i, len(entries))
+					exit(exitIncomplete)
+				}
+				if _, err := client.Replay(cmd.Context(), entry); err != nil {
+					failures := multierror.New("change", true)
+					failures.Total = len(entries)
+					failures.Add(entry.ID, describeEntry(entry), err)
+					failures.Skip(len(entries) - i - 1)
+					fmt.Fprintf(app.Err, "%s %d of %d change(s) remain queued\n", red("✗ Failed:"), len(entries)-i, len(entries))
+					app.reportFailures(output.Table, failures)
+				}
+				if err := store.Remove(entry.ID); err != nil {
+					fmt.Fprintln(app.Err, red("Error removing outbox entry:"), err)
+					exit(1)
+				}
+				fmt.Fprintf(app.Out, "%s %s (%s)\n", green("✓ Sent"), entry.ID, describeEntry(entry))
+			}
+
+			fmt.Fprintln(app.Out, green(fmt.Sprintf("\n✓ Flushed %d change(s)", len(entries))))
+		},
+	}
+	addBudgetFlags(flushCmd, false)
+	cmd.AddCommand(flushCmd)
+
+	// outbox list
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List queued changes",
+		Example: `  osticket outbox list -o json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			store := newStore()
+
+			entries, err := store.List()
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			if entries == nil {
+				entries = []*offline.Entry{}
+			}
+			app.render(output.Table, &output.Result{
+				Value: entries,
+				Table: func(w io.Writer) {
+					if len(entries) == 0 {
+						fmt.Fprintln(w, yellow("Outbox is empty"))
+						return
+					}
+
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"ID", "Queued", "Action", "Summary"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+					table.SetColWidth(40)
+
+					for _, entry := range entries {
+						table.Append([]string{
+							entry.ID,
+							entry.Created.Format("2006-01-02 15:04:05"),
+							describeEntry(entry),
+							summarizeEntry(entry),
+						})
+					}
+
+					table.Render()
+					fmt.Fprintf(w, "\nTotal: %d queued change(s)\n", len(entries))
+				},
+			})
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	// outbox edit
+	editCmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Amend a queued change",
+		Long: `Amend a queued change before it is flushed.
+
+Without --set the request is opened in $VISUAL or $EDITOR. With --set,
+individual request parameters are replaced (e.g. --set body="New text").`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sets, _ := cmd.Flags().GetStringArray("set")
+			store := newStore()
+
+			entry, err := findEntry(store, args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			var req osticket.Request
+			if err := json.Unmarshal(entry.Request, &req); err != nil {
+				fmt.Fprintln(app.Err, red("Error parsing queued request:"), err)
+				exit(1)
+			}
+
+			if len(sets) > 0 {
+				if req.Parameters == nil {
+					req.Parameters = map[string]interface{}{}
+				}
+				if err := setParams(req.Parameters, "--set", sets); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+			} else {
+				edited, err := editRequest(req)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				req = *edited
+			}
+
+			if req.Query == "" || req.Condition == "" {
+				fmt.Fprintln(app.Err, red("Error:"), "edited request must keep query and condition")
+				exit(1)
+			}
+
+			body, err := json.Marshal(req)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			entry.Request = body
+
+			if err := store.Save(entry); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving outbox entry:"), err)
+				exit(1)
+			}
+
+			fmt.Fprintln(app.Out, green("✓ Outbox entry updated"))
+		},
+	}
+	editCmd.Flags().StringArray("set", nil, "Replace a request parameter (key=value, repeatable)")
+	cmd.AddCommand(editCmd)
+
+	// outbox drop
+	dropCmd := &cobra.Command{
+		Use:   "drop [id...]",
+		Short: "Discard queued changes",
+		Example: `  osticket outbox drop 1718000000000000000
+  osticket outbox drop --all`,
+		Run: func(cmd *cobra.Command, args []string) {
+			all, _ := cmd.Flags().GetBool("all")
+			store := newStore()
+
+			ids := args
+			if all {
+				entries, err := store.List()
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				ids = nil
+				for _, entry := range entries {
+					ids = append(ids, entry.ID)
+				}
+			} else if len(ids) == 0 {
+				fmt.Fprintln(app.Err, red("Please provide an outbox ID or --all"))
+				exit(1)
+			}
+
+			for _, id := range ids {
+				if err := store.Remove(id); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				fmt.Fprintf(app.Out, "%s %s\n", green("✓ Dropped"), id)
+			}
+		},
+	}
+	dropCmd.Flags().Bool("all", false, "Discard every queued change")
+	cmd.AddCommand(dropCmd)
+
+	return cmd
+}
+
+// findEntry returns the outbox entry with the given ID
+func findEntry(store *offline.Store, id string) (*offline.Entry, error) {
+	entries, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("outbox entry %s not found", id)
+}
+
+// summarizeEntry returns the most useful parameters of a queued request
+func summarizeEntry(entry *offline.Entry) string {
+	var req osticket.Request
+	if err := json.Unmarshal(entry.Request, &req); err != nil {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range []string{"ticket_id", "title", "name", "email", "body"} {
+		if v, ok := req.Parameters[key]; ok && fmt.Sprint(v) != "" {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, v))
+		}
+	}
+	return truncate(strings.Join(parts, " "), 40)
+}
+
+// editRequest opens the request in the user's editor and returns the result
+func editRequest(req osticket.Request) (*osticket.Request, error) {
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	edited, err := editText(string(data), ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	var result osticket.Request
+	if err := json.Unmarshal([]byte(edited), &result); err != nil {
+		return nil, fmt.Errorf("edited request is not valid JSON: %w", err)
+	}
+	return &result, nil
+}
+
+// describeEntry returns a short "query condition" label for an outbox entry
+func describeEntry(entry *offline.Entry) string {
+	var req osticket.Request
+	if err := json.Unmarshal(entry.Request, &req); err != nil {
+		return "unknown"
+	}
+	return req.Query + " " + req.Condition
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== OUTPUT ====================
+
+// addOutputFlags registers the global --output, --fields, --jq and
+// --quiet flags, plus the --json and --raw flags they replace
+func (app *App) addOutputFlags(root *cobra.Command) {
+	flags := root.PersistentFlags()
+	flags.StringVarP(&app.output, "output", "o", "", "Output format: "+strings.Join(output.Names(), ", ")+" (default depends on the command)")
+	flags.StringSliceVar(&app.fields, "fields", nil, "Fields to include in the output, in order (comma-separated)")
+	flags.StringVar(&app.jq, "jq", "", "Filter the JSON output with a jq expression, e.g. '.tickets[].number'")
+	flags.BoolVarP(&app.quiet, "quiet", "q", false, "Print only the IDs of the result, e.g. the new ticket's ID, one per line")
+	flags.BoolVar(&app.jsonOutput, "json", false, "Output as JSON")
+	flags.BoolVar(&app.rawOutput, "raw", false, "Output the raw API response")
+	flags.MarkDeprecated("json", "use --output json")
+	flags.MarkDeprecated("raw", "use --output raw")
+}
+
+// checkOutputFlag rejects an unknown --output format before any request
+func (app *App) checkOutputFlag() error {
+	if app.quiet && app.jq != "" {
+		return fmt.Errorf("--quiet cannot be combined with --jq")
+	}
+	if app.quiet && app.outputFormat("") != "" {
+		return fmt.Errorf("--quiet cannot be combined with --output")
+	}
+	if app.output != "" {
+		if _, err := output.Lookup(app.output); err != nil {
+			return err
+		}
+	}
+	if app.jq == "" {
+		return nil
+	}
+	if format := app.outputFormat(output.JSON); format != output.JSON && format != output.NDJSON {
+		return fmt.Errorf("--jq filters JSON output: use it with --output json or ndjson")
+	}
+	var err error
+	app.query, err = output.ParseQuery(app.jq)
+	return err
+}
+
+// outputFormat returns the format chosen with --output, or def when none
+// was given
+func (app *App) outputFormat(def string) string {
+	switch {
+	case app.output != "":
+		return strings.ToLower(app.output)
+	case app.jsonOutput, app.jq != "":
+		return output.JSON
+	case app.rawOutput:
+		return output.Raw
+	}
+	return def
+}
+
+// render writes a command's result in the chosen format, or def when none
+// was given, exiting on error. With --quiet only the result's IDs are
+// written, and with --jq the values the query picks out of the JSON. Raw
+// output without a Raw response shows the responses of every API call the
+// command made; an error response sets the exit status as any other
+// failure would.
+func (app *App) render(def string, r *output.Result) {
+	if app.quiet {
+		for _, id := range r.IDs {
+			fmt.Fprintln(app.stdout, id)
+		}
+		return
+	}
+	r.Fields = app.fields
+	format := app.outputFormat(def)
+	if format == output.Raw && r.Raw == nil && app.recorder != nil {
+		r.Raw = app.recorder.JSON()
+	}
+	var err error
+	if app.query != nil {
+		err = app.query.Write(app.Out, r, format == output.NDJSON)
+	} else {
+		err = output.Write(app.Out, format, r)
+	}
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	// Raw output shows an error response as is, but still fails
+	if format == output.Raw {
+		if err := osticket.ResponseError(r.Raw); err != nil {
+			exit(exitCode(err))
+		}
+	}
+}
+
+// promptOut is where interactive prompts are written: stderr with --quiet,
+// so they stay visible while stdout carries only the result's IDs
+func (app *App) promptOut() io.Writer {
+	if app.quiet {
+		return app.Err
+	}
+	return app.Out
+}
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/multierror"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET OVERDUE ====================
+
+// overdueAction is what ticket overdue did, or would do, to a ticket
+type overdueAction struct {
+	TicketID int    `json:"ticket_id"`
+	Number   string `json:"number"`
+	// Action is "escalate" or "notify"
+	Action string `json:"action"`
+	// Priority is the priority an escalation raises the ticket to
+	Priority string `json:"priority,omitempty"`
+	// Result is "done", "queued", "dry-run", "skipped" or "failed"
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// overdueReport is the result of ticket overdue
+type overdueReport struct {
+	Tickets []map[string]interface{} `json:"tickets"`
+	Actions []overdueAction          `json:"actions,omitempty"`
+}
+
+// escalatedPriority is the next shipped priority up from a ticket's; ok is
+// false for emergencies and priorities osTicket does not ship with
+func escalatedPriority(id int) (next int, ok bool) {
+	switch id {
+	case osticket.PriorityLow, osticket.PriorityNormal, osticket.PriorityHigh:
+		return id + 1, true
+	}
+	return 0, false
+}
+
+func (app *App) overdueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "overdue",
+		Short: "List overdue tickets and escalate them",
+		Long: `List the open tickets osTicket flags as overdue, oldest first.
+
+--escalate raises each ticket's priority one step (Low, Normal, High,
+Emergency); emergencies and custom priorities are left alone. --notify
+posts an internal note with the given text. Both need --staff-id, unless
+--dry-run only shows what would be done. A ticket that fails does not
+stop the others unless --fail-fast is given; failures are reported at
+the end and the command exits with status 1.`,
+		Example: `  osticket ticket overdue
+  osticket ticket overdue --dept Support --dept Billing -o json
+  osticket ticket overdue --escalate --notify "Overdue: escalated by the daily check" --staff-id 1`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			deptValues, _ := cmd.Flags().GetStringSlice("dept")
+			escalate, _ := cmd.Flags().GetBool("escalate")
+			note, _ := cmd.Flags().GetString("notify")
+			title, _ := cmd.Flags().GetString("title")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+
+			acting := escalate || note != ""
+			if acting && !dryRun && staffID == 0 {
+				fmt.Fprintln(app.Err, red("Error:"), "--staff-id is required with --escalate or --notify")
+				exit(exitValidation)
+			}
+
+			ctx := cmd.Context()
+			client := app.client(ctx)
+			depts := app.deptFlagIDs(cmd, client, deptValues)
+			closed, err := closedStatuses(ctx, client)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error loading statuses:"), err)
+				exit(exitCode(err))
+			}
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				return client.GetTicketsByStatus(ctx, 0, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			now := time.Now()
+			tickets := overdueTickets(data.Tickets, closed, depts, now)
+			app.addTicketNames(ctx, client, tickets, false)
+			report := &overdueReport{Tickets: tickets}
+
+			failures := newFailures(cmd, "ticket", len(tickets))
+			if acting {
+				for i, t := range tickets {
+					actions, stop := actOnOverdue(cmd, client, t, escalate, title, note, staffID, dryRun, failures)
+					report.Actions = append(report.Actions, actions...)
+					if stop {
+						failures.Skip(len(tickets) - i - 1)
+						break
+					}
+				}
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: report,
+				Rows:  tickets,
+				IDs:   ticketIDs(tickets),
+				Table: func(w io.Writer) {
+					if len(tickets) == 0 {
+						fmt.Fprintln(w, green("✓ No overdue tickets"))
+						return
+					}
+					app.displayTicketRows(w, tickets)
+					if len(report.Actions) > 0 {
+						fmt.Fprintln(w)
+						displayOverdueActions(w, report.Actions)
+					}
+				},
+			})
+			app.reportFailures(output.Table, failures)
+		},
+	}
+	cmd.Flags().StringSlice("dept", nil, "Only list tickets of these departments, by name or ID (repeatable)")
+	cmd.Flags().Bool("escalate", false, "Raise each ticket's priority one step")
+	cmd.Flags().String("notify", "", "Post an internal note with this text to each ticket")
+	cmd.Flags().String("title", "Overdue", "Title of the --notify note")
+	cmd.Flags().Int("staff-id", 0, "Staff ID making the changes (required with --escalate or --notify)")
+	cmd.Flags().Bool("dry-run", false, "Show what --escalate and --notify would do without doing it")
+	cmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	addFailFastFlag(cmd)
+	return cmd
+}
+
+// deptFlagIDs resolves --dept values to department IDs, exiting on
+// unknown names
+func (app *App) deptFlagIDs(cmd *cobra.Command, client osticket.OSTicketAPI, values []string) []int {
+	var ids []int
+	var choices []validate.Choice
+	for _, v := range values {
+		if id, err := strconv.Atoi(v); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		if choices == nil {
+			var err error
+			if choices, err = idChoices(cmd.Context(), client, "dept"); err != nil {
+				fmt.Fprintln(app.Err, red("Error loading server metadata:"), err)
+				exit(1)
+			}
+		}
+		ids = append(ids, mustValidate(validate.Resolve("dept", "department", v, choices)))
+	}
+	return ids
+}
+
+// overdueTickets returns the open tickets flagged overdue in depts (any
+// when empty), oldest first. Tickets without a creation time come last.
+func overdueTickets(tickets []map[string]interface{}, closed map[int]bool, depts []int, now time.Time) []map[string]interface{} {
+	overdue := []map[string]interface{}{}
+	for _, t := range tickets {
+		if mapInt(t, "isoverdue") != 1 || closed[mapInt(t, "status_id")] {
+			continue
+		}
+		if len(depts) > 0 && !containsInt(depts, mapInt(t, "dept_id")) {
+			continue
+		}
+		overdue = append(overdue, t)
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		a, okA := thresholds.Age(mapString(overdue[i], "created"), now)
+		b, okB := thresholds.Age(mapString(overdue[j], "created"), now)
+		if okA != okB {
+			return okA
+		}
+		return a > b
+	})
+	return overdue
+}
+
+// actOnOverdue escalates and notes one overdue ticket as asked, recording
+// failures. stop is true when --fail-fast asks the job to stop.
+func actOnOverdue(cmd *cobra.Command, client osticket.OSTicketAPI, t map[string]interface{}, escalate bool, title, note string, staffID int, dryRun bool, failures *multierror.Errors) (actions []overdueAction, stop bool) {
+	ctx := cmd.Context()
+	id := mapInt(t, "ticket_id")
+	number := mapString(t, "number")
+	if number == "" {
+		number = strconv.Itoa(id)
+	}
+
+	// run records an action's outcome; the note is not posted after a
+	// failed escalation, so a ticket fails once
+	run := func(a overdueAction, do func() error) bool {
+		if dryRun {
+			a.Result = "dry-run"
+			actions = append(actions, a)
+			return true
+		}
+		switch err := do(); {
+		case err == nil:
+			a.Result = "done"
+		case errors.As(err, new(*osticket.QueuedError)):
+			a.Result = "queued"
+		default:
+			a.Result, a.Reason = "failed", err.Error()
+			actions = append(actions, a)
+			stop = failures.Add(number, a.Action, err)
+			return false
+		}
+		actions = append(actions, a)
+		return true
+	}
+	if escalate {
+		a := overdueAction{TicketID: id, Number: number, Action: "escalate"}
+		current := mapInt(t, "priority_id")
+		if next, ok := escalatedPriority(current); ok {
+			a.Priority = osticket.PriorityNames[next]
+			escalated := run(a, func() error {
+				return client.UpdateTicketPriority(ctx, osticket.UpdateTicketPriorityParams{
+					TicketID:   id,
+					PriorityID: next,
+					StaffID:    staffID,
+					Comment:    "Escalated: ticket is overdue",
+				})
+			})
+			if !escalated {
+				return actions, stop
+			}
+		} else {
+			a.Result = "skipped"
+			a.Reason = "already Emergency"
+			if current != osticket.PriorityEmergency {
+				a.Reason = "not a shipped priority"
+			}
+			actions = append(actions, a)
+		}
+	}
+
+	if note != "" {
+		run(overdueAction{TicketID: id, Number: number, Action: "notify"}, func() error {
+			return client.AddInternalNote(ctx, id, title, note, staffID)
+		})
+	}
+	return actions, stop
+}
+
+// displayOverdueActions prints what was done to each ticket
+func displayOverdueActions(w io.Writer, actions []overdueAction) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Number", "Action", "Priority", "Result"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+	for _, a := range actions {
+		result := a.Result
+		if a.Reason != "" {
+			result += ": " + a.Reason
+		}
+		var color tablewriter.Colors
+		switch a.Result {
+		case "failed":
+			color = tablewriter.Colors{tablewriter.FgRedColor}
+		case "skipped", "dry-run", "queued":
+			color = tablewriter.Colors{tablewriter.FgYellowColor}
+		default:
+			color = tablewriter.Colors{tablewriter.FgGreenColor}
+		}
+		table.Rich([]string{a.Number, a.Action, a.Priority, truncate(result, 60)}, []tablewriter.Colors{{}, {}, {}, color})
+	}
+	table.Render()
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ==================== PROMPT HELPERS ====================
+
+// promptOption is a selectable value in a prompt list
+type promptOption struct {
+	ID    int
+	Label string
+}
+
+// interactiveInput reports whether input comes from a terminal, where
+// prompts can be answered
+func (app *App) interactiveInput() bool {
+	f, ok := app.In.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
+// promptString asks for a line of input, returning def when left empty
+func (app *App) promptString(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(app.promptOut(), "%s [%s]: ", cyan(label), def)
+	} else {
+		fmt.Fprintf(app.promptOut(), "%s: ", cyan(label))
+	}
+
+	line, err := app.stdin().ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// promptRequired asks for a line of input until a non-empty value is given
+func (app *App) promptRequired(label string) (string, error) {
+	for {
+		value, err := app.promptString(label, "")
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+		fmt.Fprintln(app.promptOut(), yellow("  A value is required"))
+	}
+}
+
+// promptSelect lists options and returns the chosen option's ID
+func (app *App) promptSelect(label string, options []promptOption, def int) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("no %s available", strings.ToLower(label))
+	}
+
+	fmt.Fprintln(app.promptOut(), cyan(label+":"))
+	defChoice := ""
+	for i, opt := range options {
+		fmt.Fprintf(app.promptOut(), "  %2d) %s\n", i+1, opt.Label)
+		if opt.ID == def {
+			defChoice = strconv.Itoa(i + 1)
+		}
+	}
+
+	for {
+		choice, err := app.promptString("Select", defChoice)
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(choice)
+		if err == nil && n >= 1 && n <= len(options) {
+			return options[n-1].ID, nil
+		}
+		fmt.Fprintln(app.promptOut(), yellow(fmt.Sprintf("  Enter a number between 1 and %d", len(options))))
+	}
+}
+
+// promptConfirm asks a yes/no question
+func (app *App) promptConfirm(label string, def bool) (bool, error) {
+	defStr := "y/N"
+	if def {
+		defStr = "Y/n"
+	}
+
+	answer, err := app.promptString(label+" ("+defStr+")", "")
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// editText opens initial text in $VISUAL or $EDITOR and returns the result.
+// suffix sets the temp file extension so editors pick a sensible mode.
+func editText(initial, suffix string) (string, error) {
+	tmp, err := os.CreateTemp("", "osticket-*"+suffix)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(initial); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmp.Close()
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	editorArgs := strings.Fields(editor)
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmp.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return string(edited), nil
+}
@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/hold"
+	"github.com/osticket-cli-go/internal/multierror"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/retention"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== RETENTION ====================
+
+func (app *App) retentionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Enforce records-retention policies",
+	}
+
+	// retention apply
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Archive or delete tickets past their retention age",
+		Long: `Archive or delete tickets that have had no activity for longer than a
+policy allows. Rules are checked in order; the first that matches a
+ticket applies.
+
+  rules:
+    - dept: Billing          # name or ID, optional
+      status: closed         # name or ID, optional
+      older_than: 7y         # e.g. 90d, 2y or 720h
+      action: archive        # archive or delete
+    - status: closed
+      older_than: 10y
+      action: delete
+
+A dry run is required first: it lists the tickets the policy selects and
+saves them as a plan. The real run must use the same policy and server
+within 24 hours, and only touches tickets that are in the plan and still
+match. Tickets under legal hold ('osticket hold') are never changed. It
+writes an evidence report (JSON) of every ticket it changed, skipped or
+failed on; --fail-fast stops at the first failure, recording the rest as
+skipped.
+
+  osticket retention apply --policy retention.yaml --dry-run
+  osticket retention apply --policy retention.yaml --report evidence.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			policyPath, _ := cmd.Flags().GetString("policy")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			reportPath, _ := cmd.Flags().GetString("report")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "retention apply cannot run in offline mode")
+				exit(1)
+			}
+			if app.queueOnFailure {
+				fmt.Fprintln(app.Err, red("Error:"), "retention apply cannot run with --queue-on-failure")
+				exit(1)
+			}
+			policy, err := retention.Load(policyPath)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			client := app.client(cmd.Context())
+			app.resolveRetentionRules(cmd, client, policy)
+
+			planPath := filepath.Join(config.GetRetentionDir(), "plan.json")
+			var plan *retention.Plan
+			if !dryRun {
+				plan, err = loadRetentionPlan(planPath)
+				if err == nil {
+					err = plan.Check(policy, baseURL(client), time.Now())
+				}
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					fmt.Fprintf(app.Err, "Run first: osticket retention apply --policy %s --dry-run\n", policyPath)
+					exit(1)
+				}
+			}
+
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				return client.GetTicketsByStatus(cmd.Context(), 0, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			items := retentionItems(policy, data.Tickets, app.activeHolds(cmd.Context(), client), time.Now())
+
+			if dryRun {
+				absPolicy, _ := filepath.Abs(policyPath)
+				plan := &retention.Plan{
+					Policy:  absPolicy,
+					Digest:  policy.Digest,
+					Server:  baseURL(client),
+					Created: time.Now(),
+					Items:   items,
+				}
+				if err := writeJSONFile(planPath, plan); err != nil {
+					fmt.Fprintln(app.Err, red("Error saving plan:"), err)
+					exit(1)
+				}
+				app.render(output.Table, &output.Result{
+					Value: plan,
+					Rows:  plan.Items,
+					Table: func(w io.Writer) {
+						held := 0
+						for _, item := range plan.Items {
+							if item.Hold != "" {
+								held++
+							}
+						}
+						displayRetentionItems(w, policy, plan.Items)
+						fmt.Fprintf(w, "\n%d ticket(s) would be changed", len(plan.Items)-held)
+						if held > 0 {
+							fmt.Fprintf(w, ", %d held", held)
+						}
+						fmt.Fprintf(w, ". Plan saved; apply it within %.0f hours by running without --dry-run.\n", retention.PlanMaxAge.Hours())
+					},
+				})
+				return
+			}
+
+			failures := newFailures(cmd, "ticket", len(plan.Items))
+			report := applyRetentionPlan(cmd, client, policy, plan, items, staffID, failures)
+			if reportPath == "" {
+				reportPath = filepath.Join(config.GetRetentionDir(), fmt.Sprintf("report-%s.json", report.Started.UTC().Format("20060102T150405Z")))
+			}
+			if err := writeJSONFile(reportPath, report); err != nil {
+				fmt.Fprintln(app.Err, red("Error writing evidence report:"), err)
+				exit(1)
+			}
+			// A plan is applied once; the next run needs a new dry run
+			os.Remove(planPath)
+
+			app.render(output.Table, &output.Result{
+				Value: report,
+				Rows:  report.Items,
+				Table: func(w io.Writer) {
+					displayRetentionItems(w, policy, report.Items)
+					fmt.Fprintf(w, "\n%s %d applied, %d skipped, %d failed\n", green("✓"), report.Applied, report.Skipped, report.Failed)
+					fmt.Fprintf(w, "Evidence report: %s\n", reportPath)
+				},
+			})
+			app.reportFailures(output.Table, failures)
+		},
+	}
+	applyCmd.Flags().String("policy", "", "Retention policy file (YAML)")
+	applyCmd.Flags().Bool("dry-run", false, "List the tickets the policy selects and save them as the plan to apply")
+	applyCmd.Flags().String("report", "", "Evidence report path (default in the state directory)")
+	applyCmd.Flags().Int("staff-id", 0, "Staff ID recorded with the status changes")
+	applyCmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	addFailFastFlag(applyCmd)
+	applyCmd.MarkFlagRequired("policy")
+	cmd.AddCommand(applyCmd)
+
+	return cmd
+}
+
+// resolveRetentionRules resolves the department and status names of a
+// policy's rules, exiting on unknown names
+func (app *App) resolveRetentionRules(cmd *cobra.Command, client osticket.OSTicketAPI, policy *retention.Policy) {
+	var depts []validate.Choice
+	for i := range policy.Rules {
+		r := &policy.Rules[i]
+		if r.Dept != "" {
+			if depts == nil {
+				var err error
+				if depts, err = idChoices(cmd.Context(), client, "dept"); err != nil {
+					fmt.Fprintln(app.Err, red("Error loading server metadata:"), err)
+					exit(1)
+				}
+			}
+			id, err := validate.Resolve("dept", "department", r.Dept, depts)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("rule %d: %v", i+1, err))
+				exit(1)
+			}
+			r.DeptID = id
+		}
+		if r.Status != "" {
+			id, err := validate.Named("status", r.Status, statusChoices)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("rule %d: %v", i+1, err))
+				exit(1)
+			}
+			r.StatusID = id
+		}
+	}
+}
+
+// retentionItems returns the tickets a policy selects, marking those under
+// legal hold
+func retentionItems(policy *retention.Policy, tickets []map[string]interface{}, holds *hold.List, now time.Time) []retention.Item {
+	var items []retention.Item
+	for _, t := range tickets {
+		last := ticketChanged(t)
+		idle, ok := thresholds.Age(last, now)
+		if !ok {
+			continue
+		}
+		rule, ok := policy.Match(retention.Ticket{
+			DeptID:   mapInt(t, "dept_id"),
+			StatusID: mapInt(t, "status_id"),
+			Idle:     idle,
+		})
+		if !ok {
+			continue
+		}
+		item := retention.Item{
+			TicketID:     mapInt(t, "ticket_id"),
+			Number:       mapString(t, "number"),
+			DeptID:       mapInt(t, "dept_id"),
+			StatusID:     mapInt(t, "status_id"),
+			LastActivity: last,
+			Rule:         rule + 1,
+			Action:       policy.Rules[rule].Action,
+			Email:        mapString(t, "email"),
+		}
+		if h, ok := holds.Match(hold.Target{TicketID: item.TicketID, Number: item.Number, UserID: mapInt(t, "user_id")}); ok {
+			item.Hold = h.String()
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// applyRetentionPlan changes the status of tickets that are both in the
+// plan and still selected by the policy, recording every outcome and
+// adding failures to failures. Tickets held now or at the dry run are
+// skipped, as are the rest once failures asks to stop.
+func applyRetentionPlan(cmd *cobra.Command, client osticket.OSTicketAPI, policy *retention.Policy, plan *retention.Plan, items []retention.Item, staffID int, failures *multierror.Errors) *retention.Report {
+	report := &retention.Report{
+		Policy:   plan.Policy,
+		Digest:   policy.Digest,
+		Rules:    policy.Rules,
+		Server:   baseURL(client),
+		Operator: operatorName(),
+		DryRun:   plan.Created,
+		Started:  time.Now(),
+	}
+
+	current := map[int]retention.Item{}
+	for _, item := range items {
+		current[item.TicketID] = item
+	}
+
+	stopped := false
+	for _, planned := range plan.Items {
+		item, ok := current[planned.TicketID]
+		switch {
+		case stopped:
+			planned.Result = "skipped: not attempted after a failure (--fail-fast)"
+			report.Skipped++
+			failures.Skip(1)
+			report.Items = append(report.Items, planned)
+			continue
+		case planned.Hold != "" || item.Hold != "":
+			if planned.Hold == "" {
+				planned.Hold = item.Hold
+			}
+			planned.Result = "skipped: legal hold on " + planned.Hold
+			report.Skipped++
+			report.Items = append(report.Items, planned)
+			continue
+		case !ok:
+			planned.Result = "skipped: no longer selected by the policy"
+			report.Skipped++
+			report.Items = append(report.Items, planned)
+			continue
+		case item.Action != planned.Action:
+			item.Result = "skipped: action changed since the dry run"
+			report.Skipped++
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		rule := policy.Rules[item.Rule-1]
+		err := client.UpdateTicketStatus(cmd.Context(), osticket.UpdateTicketStatusParams{
+			TicketID: item.TicketID,
+			StatusID: rule.TargetStatus(),
+			StaffID:  staffID,
+			Comment:  "Retention policy: " + rule.String(),
+		})
+		if err != nil {
+			item.Result = "failed: " + err.Error()
+			report.Failed++
+			name := item.Number
+			if name == "" {
+				name = strconv.Itoa(item.TicketID)
+			}
+			stopped = failures.Add(name, item.Action, err)
+		} else {
+			item.Result = item.Action + "d"
+			report.Applied++
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	report.Finished = time.Now()
+	return report
+}
+
+// operatorName returns the local user running the command, for audit
+// records
+func operatorName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// loadRetentionPlan reads the plan saved by a dry run
+func loadRetentionPlan(path string) (*retention.Plan, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no dry run found for this profile")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read retention plan: %w", err)
+	}
+	var plan retention.Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("invalid retention plan in %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// writeJSONFile writes v as indented JSON, atomically
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func displayRetentionItems(w io.Writer, policy *retention.Policy, items []retention.Item) {
+	if len(items) == 0 {
+		fmt.Fprintln(w, yellow("No tickets selected by the policy"))
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	header := []string{"Number", "Dept", "Status", "Last Activity", "Rule", "Action", "Result"}
+	table.SetHeader(header)
+	colors := make([]tablewriter.Colors, len(header))
+	for i := range colors {
+		colors[i] = tablewriter.Colors{tablewriter.FgCyanColor}
+	}
+	table.SetHeaderColor(colors...)
+	for _, item := range items {
+		result := item.Result
+		switch {
+		case result == "" && item.Hold != "":
+			result = yellow("held: " + item.Hold)
+		case result == "":
+		case result == item.Action+"d":
+			result = green(result)
+		default:
+			result = yellow(result)
+		}
+		table.Append([]string{
+			item.Number,
+			fmt.Sprint(item.DeptID),
+			statusName(item.StatusID),
+			item.LastActivity,
+			fmt.Sprintf("%d: %s", item.Rule, policy.Rules[item.Rule-1]),
+			item.Action,
+			result,
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/filter"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET SEARCH SORT AND FILTER ====================
+
+// ticketSortKeys are the --sort keys of ticket search
+var ticketSortKeys = []string{"created", "updated", "due", "priority"}
+
+// ticketOrder is how ticket search sorts and filters the tickets it got
+type ticketOrder struct {
+	sort    string
+	desc    bool
+	filters []filter.Expr
+}
+
+// addTicketOrderFlags adds --sort, --desc and --filter to cmd
+func addTicketOrderFlags(cmd *cobra.Command) {
+	cmd.Flags().String("sort", "", "Sort tickets by "+strings.Join(ticketSortKeys, ", ")+" (oldest or least urgent first)")
+	cmd.Flags().Bool("desc", false, "Reverse the --sort order")
+	cmd.Flags().StringArray("filter", nil, "Only show tickets matching field=value, field!=value, field>value, field<value or field~text (repeatable)")
+}
+
+// ticketOrderFlags reads the flags of addTicketOrderFlags
+func ticketOrderFlags(cmd *cobra.Command) (ticketOrder, error) {
+	var o ticketOrder
+	o.sort, _ = cmd.Flags().GetString("sort")
+	o.desc, _ = cmd.Flags().GetBool("desc")
+	exprs, _ := cmd.Flags().GetStringArray("filter")
+
+	if o.sort != "" && !containsString(ticketSortKeys, o.sort) {
+		return o, fmt.Errorf("invalid --sort %q: use %s", o.sort, strings.Join(ticketSortKeys, ", "))
+	}
+	if o.desc && o.sort == "" {
+		return o, fmt.Errorf("--desc requires --sort")
+	}
+	var err error
+	o.filters, err = filter.ParseAll(exprs)
+	return o, err
+}
+
+// apply filters and sorts tickets
+func (o ticketOrder) apply(tickets []map[string]interface{}) []map[string]interface{} {
+	tickets = filter.Apply(o.filters, tickets)
+	if o.sort != "" {
+		sortTickets(tickets, o.sort, o.desc)
+	}
+	return tickets
+}
+
+// sortTickets orders tickets by one of ticketSortKeys, keeping the
+// server's order for ties. Tickets without a value, such as no due date,
+// come last either way.
+func sortTickets(tickets []map[string]interface{}, key string, desc bool) {
+	value := func(t map[string]interface{}) (string, bool) {
+		var v string
+		switch key {
+		case "created":
+			v = apiTime(mapString(t, "created"))
+		case "updated":
+			v = apiTime(ticketChanged(t))
+		case "due":
+			if v = apiTime(mapString(t, "duedate")); v == "" {
+				v = apiTime(mapString(t, "est_duedate"))
+			}
+		case "priority":
+			if rank := priorityRank(t); rank > 0 {
+				v = fmt.Sprint(rank)
+			}
+		}
+		return v, v != ""
+	}
+	// API timestamps ("2006-01-02 15:04:05") and single-digit ranks sort
+	// correctly as strings
+	sort.SliceStable(tickets, func(i, j int) bool {
+		a, okA := value(tickets[i])
+		b, okB := value(tickets[j])
+		if okA != okB {
+			return okA
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/gateway"
+	"github.com/spf13/cobra"
+)
+
+// ==================== REST GATEWAY ====================
+
+func (app *App) serveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a REST API in front of the osTicket API",
+		Long: `Serve a small REST API that translates plain resource URLs into the
+osTicket API plugin's query/condition requests, using the profile's
+connection and API key:
+
+  GET  /tickets/{id}            a ticket, by number or ID
+  POST /tickets                 create a ticket: {"title", "subject",
+                                "user_id" or "email", "priority_id",
+                                "dept_id", "topic_id", "sla_id",
+                                "status_id", "fields"}
+  POST /tickets/{id}/reply      reply to a ticket: {"body", "staff_id"}
+  GET  /users?email=...         look up a user by email, or by ?id=
+
+Responses are JSON; errors are {"error": "..."} with status 400 for bad
+requests and osTicket validation errors, 404 for unknown tickets and
+users, and 502 or 504 when the osTicket API fails.
+
+Callers authenticate with a gateway key, sent as "Authorization: Bearer
+<key>" or in an X-API-Key header. Keys are read from --key-file, one per
+line, and from ` + config.EnvGatewayKey + `; the osTicket API key itself is never
+accepted. The gateway speaks plain HTTP: put it behind a TLS proxy when it
+listens beyond localhost. Requests are logged at the info level. Runs
+until interrupted.`,
+		Example: `  OSTICKET_GATEWAY_KEY=s3cret osticket serve --listen :8080
+  osticket serve --listen 127.0.0.1:8080 --key-file /etc/osticket/gateway-keys
+  curl -H "Authorization: Bearer s3cret" http://localhost:8080/tickets/100042`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "serve cannot run in offline mode")
+				exit(1)
+			}
+			if app.queueOnFailure {
+				fmt.Fprintln(app.Err, red("Error:"), "serve cannot run with --queue-on-failure")
+				exit(1)
+			}
+			addr, _ := cmd.Flags().GetString("listen")
+			keyFile, _ := cmd.Flags().GetString("key-file")
+
+			keys, err := gatewayKeys(keyFile)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			if len(keys) == 0 {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("no gateway keys: set %s or use --key-file", config.EnvGatewayKey))
+				exit(exitValidation)
+			}
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			srv := &http.Server{
+				Handler:           &gateway.Server{Client: app.client(cmd.Context()), Keys: keys, Logger: app.logger()},
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-cmd.Context().Done()
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				srv.Shutdown(ctx)
+			}()
+
+			fmt.Fprintf(app.Err, "Serving the REST gateway on http://%s/. Press Ctrl+C to stop.\n", ln.Addr())
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+		},
+	}
+	cmd.Flags().String("listen", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String("key-file", "", "File of accepted gateway keys, one per line (# starts a comment)")
+	return cmd
+}
+
+// gatewayKeys reads the keys accepted by serve from a key file and the
+// environment
+func gatewayKeys(path string) ([]string, error) {
+	var keys []string
+	if key := strings.TrimSpace(os.Getenv(config.EnvGatewayKey)); key != "" {
+		keys = append(keys, key)
+	}
+	if path == "" {
+		return keys, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key file: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys, scanner.Err()
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== STAFF COMMANDS ====================
+
+func (app *App) staffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "staff",
+		Short: "Look up agents",
+	}
+
+	// staff get
+	getCmd := &cobra.Command{
+		Use:   "get <id|username>",
+		Short: "Get an agent by ID or username",
+		Long: `Get an agent by numeric ID or username, e.g. to resolve a --staff-id in
+scripts that run against more than one instance:
+
+  osticket staff get jdoe -o json | jq '.staff[0].staff_id'`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			var data *osticket.StaffData
+			var err error
+			if id, convErr := strconv.Atoi(args[0]); convErr == nil {
+				data, err = client.GetStaff(cmd.Context(), id)
+			} else {
+				data, err = client.GetStaffByUsername(cmd.Context(), args[0])
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.renderStaff(data, "No agent found")
+		},
+	}
+	cmd.AddCommand(getCmd)
+
+	// staff list
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List all agents",
+		Example: `  osticket staff list -o csv`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetStaffList(cmd.Context())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.renderStaff(data, "No agents found")
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// staffIDs returns the IDs of agents, for --quiet
+func staffIDs(staff []osticket.Staff) []string {
+	ids := make([]string, len(staff))
+	for i, s := range staff {
+		ids[i] = strconv.Itoa(s.StaffID)
+	}
+	return ids
+}
+
+// renderStaff renders agents as a table or the chosen format
+func (app *App) renderStaff(data *osticket.StaffData, empty string) {
+	app.render(output.Table, &output.Result{
+		Value: data,
+		Rows:  data.Staff,
+		IDs:   staffIDs(data.Staff),
+		Table: func(w io.Writer) {
+			if len(data.Staff) == 0 {
+				fmt.Fprintln(w, yellow(empty))
+				return
+			}
+
+			table := tablewriter.NewWriter(w)
+			table.SetHeader([]string{"ID", "Username", "Name", "Email", "Dept", "Active"})
+			table.SetHeaderColor(
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+				tablewriter.Colors{tablewriter.FgCyanColor},
+			)
+
+			for _, s := range data.Staff {
+				active := "yes"
+				if !s.IsActive {
+					active = "no"
+				}
+				table.Append([]string{
+					strconv.Itoa(s.StaffID),
+					s.Username,
+					s.Name(),
+					s.Email,
+					strconv.Itoa(s.DeptID),
+					active,
+				})
+			}
+
+			table.Render()
+		},
+	})
+}
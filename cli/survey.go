@@ -0,0 +1,479 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/mail"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/retention"
+	"github.com/osticket-cli-go/internal/survey"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== SURVEYS ====================
+
+// defaultSurveySubject is the subject of survey emails unless --subject
+// is given
+const defaultSurveySubject = "How did we do? Ticket #{{.Number}}"
+
+// surveyMessage is what the survey email template and subject can use
+type surveyMessage struct {
+	TicketID int
+	Number   string
+	Subject  string
+	Name     string
+	Email    string
+	Link     string
+}
+
+// surveyResult is the outcome of a survey send for one ticket
+type surveyResult struct {
+	TicketID int    `json:"ticket_id"`
+	Number   string `json:"number"`
+	Email    string `json:"email"`
+	Result   string `json:"result"`
+}
+
+func (app *App) surveyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "survey",
+		Short: "Email satisfaction surveys for closed tickets and track responses",
+	}
+
+	// survey send
+	sendCmd := &cobra.Command{
+		Use:   "send",
+		Short: "Email a satisfaction survey for recently closed tickets",
+		Long: `Email a satisfaction survey link to the customer of every ticket closed
+within --closed-since. Each ticket gets one survey: sends are recorded in the
+state directory and tickets already sent one are skipped, so the command is
+safe to run from cron. Tickets muted by 'osticket ignore' are skipped too.
+
+The survey link is --survey-url with token and ticket query parameters
+added. Post answers back to 'osticket survey receive' to track them.
+
+The --template file is an HTML template; the subject is a text template.
+Both can use {{.Number}}, {{.Subject}}, {{.Name}}, {{.Email}}, {{.TicketID}}
+and {{.Link}}.`,
+		Example: `  osticket survey send --closed-since 24h --template survey.html --smtp-profile corp --survey-url https://survey.example.com/
+  osticket survey send --closed-since 7d --template survey.html --smtp-profile corp --survey-url https://survey.example.com/ --dry-run`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceValue, _ := cmd.Flags().GetString("closed-since")
+			templatePath, _ := cmd.Flags().GetString("template")
+			subjectText, _ := cmd.Flags().GetString("subject")
+			smtpName, _ := cmd.Flags().GetString("smtp-profile")
+			surveyURL, _ := cmd.Flags().GetString("survey-url")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "survey send cannot run in offline mode")
+				exit(1)
+			}
+			if app.queueOnFailure {
+				fmt.Fprintln(app.Err, red("Error:"), "survey send cannot run with --queue-on-failure")
+				exit(1)
+			}
+			since, err := retention.ParseAge(sinceValue)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), "--closed-since: "+err.Error())
+				exit(exitValidation)
+			}
+			if _, err := survey.Link(surveyURL, "", ""); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitValidation)
+			}
+			body, err := htmltemplate.New(filepath.Base(templatePath)).Option("missingkey=error").ParseFiles(templatePath)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error reading survey template:"), err)
+				exit(1)
+			}
+			subject, err := template.New("subject").Option("missingkey=error").Parse(subjectText)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --subject: %v", err))
+				exit(exitValidation)
+			}
+			var server *mail.Server
+			if !dryRun {
+				if server, err = config.GetSMTPServer(smtpName); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+			}
+
+			sendsPath := filepath.Join(config.GetSurveyDir(), "sends.json")
+			sends, err := survey.LoadSends(sendsPath)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+
+			client := app.client(cmd.Context())
+			closed, err := closedStatuses(cmd.Context(), client)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error loading statuses:"), err)
+				exit(exitCode(err))
+			}
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				return client.GetTicketsByStatus(cmd.Context(), 0, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			muted := app.ignoreMatcher()
+			now := time.Now()
+			var results []surveyResult
+			var sentIDs []string
+			failures := newFailures(cmd, "ticket", 0)
+			for _, t := range data.Tickets {
+				if !closed[mapInt(t, "status_id")] {
+					continue
+				}
+				if age, ok := thresholds.Age(mapString(t, "closed"), now); !ok || age > since {
+					continue
+				}
+
+				result := surveyResult{
+					TicketID: mapInt(t, "ticket_id"),
+					Number:   mapString(t, "number"),
+					Email:    mapString(t, "email"),
+				}
+				if result.Number == "" {
+					result.Number = strconv.Itoa(result.TicketID)
+				}
+				if _, skip := muted.Match(ignore.Ticket{
+					Number:  result.Number,
+					Subject: mapString(t, "subject"),
+					Sender:  result.Email,
+					DeptID:  mapInt(t, "dept_id"),
+				}); skip {
+					continue
+				}
+				switch {
+				case sends.Sent(result.TicketID):
+					result.Result = "skipped: already sent"
+				case result.Email == "":
+					result.Result = "skipped: no email address"
+				case dryRun:
+					result.Result = "would send"
+				default:
+					send, err := sendSurvey(cmd.Context(), server, body, subject, surveyURL, t, result)
+					if err != nil {
+						result.Result = "failed: " + err.Error()
+						failures.Add(result.Number, "", err)
+						break
+					}
+					// Saved after every send so a crash never leads to a
+					// second survey for the same ticket
+					sends.Sends = append(sends.Sends, send)
+					if err := sends.Save(sendsPath); err != nil {
+						fmt.Fprintln(app.Err, red("Error saving survey sends:"), err)
+						exit(1)
+					}
+					result.Result = "sent"
+					sentIDs = append(sentIDs, result.Number)
+				}
+				results = append(results, result)
+				if failures.FailFast && failures.Len() > 0 {
+					break
+				}
+			}
+			failures.Total = len(sentIDs) + failures.Len()
+
+			app.render(output.Table, &output.Result{
+				Value: results,
+				Rows:  results,
+				IDs:   sentIDs,
+				Table: func(w io.Writer) {
+					if len(results) == 0 {
+						fmt.Fprintf(w, "%s\n", yellow("No tickets closed within "+sinceValue))
+						return
+					}
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"Number", "Email", "Result"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+					for _, r := range results {
+						table.Append([]string{r.Number, r.Email, r.Result})
+					}
+					table.Render()
+					if dryRun {
+						fmt.Fprintln(w, yellow("\nDry run: no surveys were sent"))
+						return
+					}
+					fmt.Fprintf(w, "\n%s %d sent, %d failed\n", green("✓"), len(sentIDs), failures.Len())
+				},
+			})
+			app.reportFailures(output.Table, failures)
+		},
+	}
+	sendCmd.Flags().String("closed-since", "24h", "Survey tickets closed within this period, e.g. 24h or 7d")
+	sendCmd.Flags().String("template", "", "HTML template of the survey email")
+	sendCmd.Flags().String("subject", defaultSurveySubject, "Subject of the survey email (a template)")
+	sendCmd.Flags().String("smtp-profile", "", "SMTP profile to send with (see 'osticket config smtp')")
+	sendCmd.Flags().String("survey-url", "", "URL of the survey page")
+	sendCmd.Flags().Bool("dry-run", false, "List the tickets that would be sent a survey")
+	sendCmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	addFailFastFlag(sendCmd)
+	sendCmd.MarkFlagRequired("template")
+	sendCmd.MarkFlagRequired("smtp-profile")
+	sendCmd.MarkFlagRequired("survey-url")
+	cmd.AddCommand(sendCmd)
+
+	// survey receive
+	receiveCmd := &cobra.Command{
+		Use:   "receive",
+		Short: "Record survey responses posted back by the survey page",
+		Long: `Listen for survey responses and record them for survey report. The
+survey page posts the token from its link with a rating from 1 to 5 and an
+optional comment, as JSON or a form:
+
+  POST /   {"token": "...", "rating": 5, "comment": "Quick fix, thanks"}
+
+Responses with an unknown token are refused with HTTP 404. A later response
+with the same token replaces the earlier one. Runs until interrupted.`,
+		Example: `  osticket survey receive --listen 127.0.0.1:8089`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			addr, _ := cmd.Flags().GetString("listen")
+			dir := config.GetSurveyDir()
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			srv := &http.Server{
+				Handler:           app.surveyHandler(filepath.Join(dir, "sends.json"), filepath.Join(dir, "responses.jsonl")),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-cmd.Context().Done()
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				srv.Shutdown(ctx)
+			}()
+
+			fmt.Fprintf(app.Err, "Receiving survey responses on http://%s/. Press Ctrl+C to stop.\n", ln.Addr())
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+		},
+	}
+	receiveCmd.Flags().String("listen", "127.0.0.1:8089", "Address to listen on")
+	cmd.AddCommand(receiveCmd)
+
+	// survey report
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show the response rate and ratings of sent surveys",
+		Example: `  osticket survey report
+  osticket survey report --since 30d -o json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceValue, _ := cmd.Flags().GetString("since")
+			var since time.Time
+			if sinceValue != "" {
+				age, err := retention.ParseAge(sinceValue)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), "--since: "+err.Error())
+					exit(exitValidation)
+				}
+				since = time.Now().Add(-age)
+			}
+
+			dir := config.GetSurveyDir()
+			sends, err := survey.LoadSends(filepath.Join(dir, "sends.json"))
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			responses, err := survey.LoadResponses(filepath.Join(dir, "responses.jsonl"))
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			sum := survey.Summarize(sends, responses, since)
+
+			app.render(output.Table, &output.Result{
+				Value: sum,
+				Table: func(w io.Writer) {
+					if sum.Sent == 0 {
+						fmt.Fprintln(w, yellow("No surveys sent"))
+						return
+					}
+					fmt.Fprintf(w, "Surveys sent:   %d\n", sum.Sent)
+					fmt.Fprintf(w, "Responses:      %d (%.0f%%)\n", sum.Responded, sum.Rate*100)
+					if sum.Responded == 0 {
+						return
+					}
+					fmt.Fprintf(w, "Average rating: %.1f of %d\n\n", sum.Average, survey.MaxRating)
+
+					table := tablewriter.NewWriter(w)
+					table.SetHeader([]string{"Rating", "Responses"})
+					table.SetHeaderColor(
+						tablewriter.Colors{tablewriter.FgCyanColor},
+						tablewriter.Colors{tablewriter.FgCyanColor},
+					)
+					for rating := survey.MaxRating; rating >= survey.MinRating; rating-- {
+						table.Append([]string{strings.Repeat("★", rating), strconv.Itoa(sum.Ratings[rating])})
+					}
+					table.Render()
+				},
+			})
+		},
+	}
+	reportCmd.Flags().String("since", "", "Only count surveys sent within this period, e.g. 30d")
+	cmd.AddCommand(reportCmd)
+
+	return cmd
+}
+
+// closedStatuses returns the IDs of the statuses that close a ticket,
+// assuming the shipped Resolved and Closed on plugins without the status
+// query
+func closedStatuses(ctx context.Context, client osticket.OSTicketAPI) (map[int]bool, error) {
+	closed := map[int]bool{}
+	data, err := client.GetStatuses(ctx)
+	var apiErr *osticket.Error
+	if errors.As(err, &apiErr) {
+		closed[osticket.StatusResolved] = true
+		closed[osticket.StatusClosed] = true
+		return closed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range data.Statuses {
+		if s.State == "closed" {
+			closed[s.ID] = true
+		}
+	}
+	return closed, nil
+}
+
+// sendSurvey emails the survey for a ticket and returns the send to record
+func sendSurvey(ctx context.Context, server *mail.Server, body *htmltemplate.Template, subject *template.Template, surveyURL string, t map[string]interface{}, result surveyResult) (survey.Send, error) {
+	send := survey.Send{
+		TicketID: result.TicketID,
+		Number:   result.Number,
+		Email:    result.Email,
+		DeptID:   mapInt(t, "dept_id"),
+		StaffID:  mapInt(t, "staff_id"),
+	}
+	token, err := survey.NewToken()
+	if err != nil {
+		return send, err
+	}
+	link, err := survey.Link(surveyURL, token, result.Number)
+	if err != nil {
+		return send, err
+	}
+	msg := surveyMessage{
+		TicketID: result.TicketID,
+		Number:   result.Number,
+		Subject:  mapString(t, "subject"),
+		Name:     mapString(t, "name"),
+		Email:    result.Email,
+		Link:     link,
+	}
+
+	var html, subj bytes.Buffer
+	if err := body.Execute(&html, msg); err != nil {
+		return send, fmt.Errorf("template: %w", err)
+	}
+	if err := subject.Execute(&subj, msg); err != nil {
+		return send, fmt.Errorf("subject: %w", err)
+	}
+	if err := server.Send(ctx, mail.Message{To: result.Email, Subject: subj.String(), HTML: html.String()}); err != nil {
+		return send, err
+	}
+	send.Token = token
+	send.Sent = time.Now()
+	return send, nil
+}
+
+// surveyHandler records survey responses posted as JSON or a form
+func (app *App) surveyHandler(sendsPath, responsesPath string) http.Handler {
+	reply := func(w http.ResponseWriter, status int, message string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"status": http.StatusText(status), "message": message})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			reply(w, http.StatusMethodNotAllowed, "post survey responses")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+
+		var resp survey.Response
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+				reply(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+				return
+			}
+		} else {
+			if err := r.ParseForm(); err != nil {
+				reply(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			resp.Token = r.PostForm.Get("token")
+			resp.Rating, _ = strconv.Atoi(r.PostForm.Get("rating"))
+			resp.Comment = r.PostForm.Get("comment")
+		}
+		if err := resp.Validate(); err != nil {
+			reply(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// survey send may have added tokens since the last response
+		sends, err := survey.LoadSends(sendsPath)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			reply(w, http.StatusInternalServerError, "could not read survey sends")
+			return
+		}
+		send, ok := sends.ByToken(resp.Token)
+		if !ok {
+			reply(w, http.StatusNotFound, "unknown survey token")
+			return
+		}
+		resp.TicketID = send.TicketID
+		resp.Received = time.Now()
+		if err := survey.AppendResponse(responsesPath, resp); err != nil {
+			fmt.Fprintln(app.Err, red("Error saving survey response:"), err)
+			reply(w, http.StatusInternalServerError, "could not save the response")
+			return
+		}
+		fmt.Fprintf(app.Err, "%s ticket #%s rated %d\n", green("✓"), send.Number, resp.Rating)
+		reply(w, http.StatusOK, "response recorded")
+	})
+}
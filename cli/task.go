@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TASKS ====================
+
+func (app *App) taskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: "Manage the tasks attached to tickets",
+		Long: `Tasks track internal work on a ticket, such as ordering a part, each with
+its own department, assignee and due date. Needs an API plugin that
+answers the task query.`,
+	}
+
+	// task list
+	listCmd := &cobra.Command{
+		Use:   "list <ticketId>",
+		Short: "List a ticket's tasks",
+		Example: `  osticket task list 12345
+  osticket task list 12345 --all -o json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			ticketID := app.idArg(args[0], "ticket")
+			all, _ := cmd.Flags().GetBool("all")
+
+			data, err := client.GetTicketTasks(cmd.Context(), ticketID)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			tasks := []osticket.Task{}
+			for _, t := range data.Tasks {
+				if t.IsOpen || all {
+					tasks = append(tasks, t)
+				}
+			}
+			data = &osticket.TaskData{Total: len(tasks), Tasks: tasks}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  tasks,
+				Table: func(w io.Writer) {
+					if len(tasks) == 0 {
+						fmt.Fprintln(w, yellow("No tasks found"))
+						return
+					}
+					app.displayTasks(cmd.Context(), client, w, tasks)
+				},
+			})
+		},
+	}
+	listCmd.Flags().Bool("all", false, "Include closed tasks")
+	cmd.AddCommand(listCmd)
+
+	// task create
+	createCmd := &cobra.Command{
+		Use:   "create <ticketId>",
+		Short: "Add a task to a ticket",
+		Example: `  osticket task create 12345 --title "Order replacement fuser"
+  osticket task create 12345 --title "Swap drive" --description "Bay 3" --staff-id 7 --due 2026-11-02`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			ticketID := app.idArg(args[0], "ticket")
+
+			title, _ := cmd.Flags().GetString("title")
+			description, _ := cmd.Flags().GetString("description")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			due, _ := cmd.Flags().GetString("due")
+			dept := app.namedIDFlag(cmd, client, "dept")
+			team := app.namedIDFlag(cmd, client, "team")
+			if due != "" {
+				due = mustValidate(taskDueDate(due))
+			}
+			if description != "" {
+				description = app.convertBody(cmd, description)
+			}
+
+			taskID, err := client.CreateTask(cmd.Context(), osticket.CreateTaskParams{
+				TicketID:    ticketID,
+				Title:       title,
+				Description: description,
+				DeptID:      dept,
+				StaffID:     staffID,
+				TeamID:      team,
+				DueDate:     due,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"task_id": taskID, "ticket_id": ticketID},
+				IDs:   []string{strconv.Itoa(taskID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Task created successfully!"))
+					fmt.Fprintf(w, "  Task ID: %d\n", taskID)
+				},
+			})
+		},
+	}
+	createCmd.Flags().String("title", "", "Task title (required)")
+	createCmd.Flags().String("description", "", "Task description")
+	createCmd.Flags().String("dept", "", "Department ID or name (default: the ticket's)")
+	createCmd.Flags().Int("staff-id", 0, "Assign to this staff member")
+	createCmd.Flags().String("team", "", "Assign to this team (ID or name, see 'osticket info teams')")
+	createCmd.Flags().String("due", "", "Due date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	addBodyFormatFlag(createCmd)
+	createCmd.MarkFlagRequired("title")
+	createCmd.MarkFlagsMutuallyExclusive("staff-id", "team")
+	cmd.AddCommand(createCmd)
+
+	// task close
+	closeCmd := &cobra.Command{
+		Use:     "close <taskId>",
+		Short:   "Close a task",
+		Example: `  osticket task close 42 --staff-id 1 --comment "Part installed"`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			taskID := app.idArg(args[0], "task")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			comment, _ := cmd.Flags().GetString("comment")
+
+			err := client.CloseTask(cmd.Context(), taskID, staffID, comment)
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(taskID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Task closed successfully!"))
+				},
+			})
+		},
+	}
+	closeCmd.Flags().Int("staff-id", 0, "Staff ID closing the task")
+	closeCmd.Flags().String("comment", "", "Closing comment")
+	cmd.AddCommand(closeCmd)
+
+	// task assign
+	assignCmd := &cobra.Command{
+		Use:   "assign <taskId>",
+		Short: "Assign a task to an agent or team",
+		Example: `  osticket task assign 42 --staff-id 7
+  osticket task assign 42 --team "Level 2" --comment "Needs on-site visit"`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			taskID := app.idArg(args[0], "task")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			team := app.namedIDFlag(cmd, client, "team")
+			comment, _ := cmd.Flags().GetString("comment")
+
+			err := client.AssignTask(cmd.Context(), osticket.AssignTaskParams{
+				TaskID:  taskID,
+				StaffID: staffID,
+				TeamID:  team,
+				Comment: comment,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(taskID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Task assigned successfully!"))
+				},
+			})
+		},
+	}
+	assignCmd.Flags().Int("staff-id", 0, "Assign to this staff member")
+	assignCmd.Flags().String("team", "", "Assign to this team (ID or name, see 'osticket info teams')")
+	assignCmd.Flags().String("comment", "", "Assignment comment")
+	assignCmd.MarkFlagsOneRequired("staff-id", "team")
+	assignCmd.MarkFlagsMutuallyExclusive("staff-id", "team")
+	cmd.AddCommand(assignCmd)
+
+	return cmd
+}
+
+// idArg parses a ticket or task ID argument, exiting when it is not
+// a number
+func (app *App) idArg(arg, what string) int {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		fmt.Fprintln(app.Err, red(fmt.Sprintf("Invalid %s ID", what)))
+		exit(1)
+	}
+	return id
+}
+
+// taskDueDate checks a --due date and returns it as the API takes it
+func taskDueDate(value string) (string, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if due, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return due.Format("2006-01-02 15:04:05"), nil
+		}
+	}
+	return "", &validate.FieldError{Flag: "due", Value: value, Reason: "use YYYY-MM-DD or YYYY-MM-DD HH:MM:SS"}
+}
+
+// displayTasks prints tasks with their assignee by name
+func (app *App) displayTasks(ctx context.Context, client osticket.OSTicketAPI, w io.Writer, tasks []osticket.Task) {
+	staffNames := map[int]string{}
+	if staff, err := client.GetStaffList(ctx); err == nil {
+		for _, s := range staff.Staff {
+			staffNames[s.StaffID] = s.Name()
+		}
+	}
+	teamNames := map[int]string{}
+	if teams, err := client.GetTeams(ctx); err == nil {
+		for _, t := range teams.Teams {
+			teamNames[t.ID] = t.Name
+		}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Title", "Status", "Assignee", "Due", "Created"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+	for _, t := range tasks {
+		status := "Open"
+		if !t.IsOpen {
+			status = "Closed"
+		}
+		assignee := "-"
+		switch {
+		case t.StaffID != 0:
+			if assignee = staffNames[t.StaffID]; assignee == "" {
+				assignee = "Staff " + strconv.Itoa(t.StaffID)
+			}
+		case t.TeamID != 0:
+			if assignee = teamNames[t.TeamID]; assignee == "" {
+				assignee = "Team " + strconv.Itoa(t.TeamID)
+			}
+		}
+		due := apiTime(t.DueDate)
+		if due == "" {
+			due = "-"
+		}
+		table.Append([]string{strconv.Itoa(t.ID), truncate(t.Title, 40), status, assignee, due, t.Created})
+	}
+	table.Render()
+}
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/templates"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ==================== TICKET TEMPLATES ====================
+
+func (app *App) templateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage reusable ticket templates",
+		Long: `Templates hold the title, body, department, help topic, priority and
+custom fields of a kind of ticket that is filed again and again. The title,
+body and field values can use Go template placeholders such as {{.host}},
+filled in with --var when a ticket is created. Templates are stored as YAML
+in the templates directory next to the config file and shared by every
+profile.
+
+  osticket template save outage --title "Outage: {{.host}}" \
+    --body "{{.host}} stopped answering at {{.since}}." --dept NOC --priority high
+  osticket ticket create --template outage --var host=web01 --var since=09:14 --user-id 12`,
+	}
+
+	// template save
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save a ticket template",
+		Example: `  osticket template save outage --title "Outage: {{.host}}" --body "{{.host}} is down" --priority high
+  osticket template save outage --file outage.yaml`,
+		Long: `Save a template from flags, or from a YAML file with --file ('-' reads
+stdin); flags override values in the file. Saving under an existing name
+replaces that template.
+
+  description: Host stopped answering
+  title: "Outage: {{.host}}"
+  body: "{{.host}} stopped answering at {{.since}}."
+  dept: NOC
+  priority: high
+  fields:
+    hostname: "{{.host}}"`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			t := &templates.Template{Name: args[0]}
+			if path, _ := cmd.Flags().GetString("file"); path != "" {
+				var err error
+				if t, err = app.loadTemplateFile(args[0], path); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+			}
+
+			strs := map[string]*string{
+				"description": &t.Description,
+				"title":       &t.Title,
+				"body":        &t.Body,
+				"dept":        &t.Dept,
+				"topic":       &t.Topic,
+				"priority":    &t.Priority,
+			}
+			for flag, v := range strs {
+				if cmd.Flags().Changed(flag) {
+					*v, _ = cmd.Flags().GetString(flag)
+				}
+			}
+			fieldPairs, _ := cmd.Flags().GetStringArray("field")
+			for _, pair := range fieldPairs {
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok || key == "" {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --field %q, expected key=value", pair))
+					exit(1)
+				}
+				if t.Fields == nil {
+					t.Fields = map[string]string{}
+				}
+				t.Fields[key] = value
+			}
+			if t.Priority != "" {
+				if _, err := thresholds.PriorityID(t.Priority); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+			}
+
+			if err := templates.Save(config.GetTemplateDir(), t); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving template:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Template "+t.Name+" saved"))
+		},
+	}
+	saveCmd.Flags().String("description", "", "What the template is for")
+	saveCmd.Flags().String("title", "", "Ticket title")
+	saveCmd.Flags().String("body", "", "Ticket body")
+	saveCmd.Flags().String("dept", "", "Department ID or name")
+	saveCmd.Flags().String("topic", "", "Topic ID or name")
+	saveCmd.Flags().String("priority", "", "Priority ID or name (low, normal, high, emergency)")
+	saveCmd.Flags().StringArray("field", nil, "Custom form field as key=value (repeatable)")
+	saveCmd.Flags().StringP("file", "f", "", "Read the template from a YAML file ('-' for stdin)")
+	cmd.AddCommand(saveCmd)
+
+	// template list
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List ticket templates",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			list, err := templates.List(config.GetTemplateDir())
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			app.render(output.Table, &output.Result{
+				Value: list,
+				Rows:  list,
+				Table: func(w io.Writer) { displayTemplates(w, list) },
+			})
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	// template show
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a ticket template",
+		Long: `Show a template and the variables it needs. With --var the template is
+shown filled in, as a ticket created from it would be.`,
+		Example: `  osticket template show outage
+  osticket template show outage --var host=web01 --var since=09:14`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplateArg,
+		Run: func(cmd *cobra.Command, args []string) {
+			t := app.loadTemplate(args[0])
+			vars, _ := t.Vars()
+			if cmd.Flags().Changed("var") {
+				var err error
+				if t, err = t.Apply(app.templateVars(cmd)); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: t,
+				Table: func(w io.Writer) {
+					data, _ := yaml.Marshal(t)
+					fmt.Fprintln(w, cyan("Template: ")+t.Name)
+					if len(vars) > 0 {
+						fmt.Fprintln(w, cyan("Variables: ")+strings.Join(vars, ", "))
+					}
+					fmt.Fprintln(w)
+					fmt.Fprint(w, string(data))
+				},
+			})
+		},
+	}
+	showCmd.Flags().StringArray("var", nil, "Template variable as key=value (repeatable)")
+	cmd.AddCommand(showCmd)
+
+	// template apply
+	applyCmd := &cobra.Command{
+		Use:   "apply <name>",
+		Short: "Create a ticket from a template",
+		Long: `Create a ticket from a template, filling in its placeholders with --var.
+This is 'ticket create --template <name>' and takes the same flags, which
+override what the template sets.`,
+		Example:           `  osticket template apply outage --var host=web01 --var since=09:14 --user-id 12`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplateArg,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Flags().Set("template", args[0])
+			app.createTicket(cmd)
+		},
+	}
+	addCreateFlags(applyCmd)
+	applyCmd.Flags().MarkHidden("template")
+	cmd.AddCommand(applyCmd)
+
+	// template delete
+	deleteCmd := &cobra.Command{
+		Use:               "delete <name>",
+		Short:             "Delete a ticket template",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplateArg,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := templates.Delete(config.GetTemplateDir(), args[0]); err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			fmt.Fprintln(app.Out, green("✓ Template "+args[0]+" deleted"))
+		},
+	}
+	cmd.AddCommand(deleteCmd)
+
+	return cmd
+}
+
+// loadTemplate reads a saved template, exiting when it cannot be read
+func (app *App) loadTemplate(name string) *templates.Template {
+	t, err := templates.Load(config.GetTemplateDir(), name)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(1)
+	}
+	return t
+}
+
+// loadTemplateFile reads a template definition from a file or, for "-",
+// stdin
+func (app *App) loadTemplateFile(name, path string) (*templates.Template, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(app.In)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read template file: %w", err)
+	}
+	return templates.Parse(name, data)
+}
+
+// templateVars reads the --var key=value pairs, exiting on a malformed one
+func (app *App) templateVars(cmd *cobra.Command) map[string]string {
+	vars := map[string]string{}
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --var %q, expected key=value", pair))
+			exit(1)
+		}
+		vars[key] = value
+	}
+	return vars
+}
+
+// applyTemplate fills in the named template with the --var values and sets
+// the create command's flags from it. Flags given on the command line keep
+// their values; a --file applied afterwards overrides the template too.
+func (app *App) applyTemplate(cmd *cobra.Command, name string) *templates.Template {
+	t, err := app.loadTemplate(name).Apply(app.templateVars(cmd))
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(1)
+	}
+
+	values := map[string]string{
+		"title":   t.Title,
+		"subject": t.Body,
+		"dept":    t.Dept,
+		"topic":   t.Topic,
+	}
+	if t.Priority != "" {
+		id, err := thresholds.PriorityID(t.Priority)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("template %s: %v", t.Name, err))
+			exit(1)
+		}
+		values["priority"] = strconv.Itoa(id)
+	}
+	for flag, v := range values {
+		if v == "" || cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, v); err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid %s in template %s: %v", flag, t.Name, err))
+			exit(1)
+		}
+	}
+	return t
+}
+
+// completeTemplate completes --template with the saved template names
+func (app *App) completeTemplate(cmd *cobra.Command, flag *pflag.Flag) []string {
+	return templateNames()
+}
+
+// templateNames returns the saved template names, described by their
+// description
+func templateNames() []string {
+	list, _ := templates.List(config.GetTemplateDir())
+	names := make([]string, 0, len(list))
+	for _, t := range list {
+		names = append(names, t.Name+"\t"+t.Description)
+	}
+	return names
+}
+
+// completeTemplateArg completes the template name argument of template
+// show, apply and delete
+func completeTemplateArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return templateNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func displayTemplates(w io.Writer, list []*templates.Template) {
+	if len(list) == 0 {
+		fmt.Fprintln(w, yellow("No templates; save one with 'osticket template save'"))
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	header := []string{"Name", "Description", "Title", "Priority", "Variables"}
+	table.SetHeader(header)
+	colors := make([]tablewriter.Colors, len(header))
+	for i := range colors {
+		colors[i] = tablewriter.Colors{tablewriter.FgCyanColor}
+	}
+	table.SetHeaderColor(colors...)
+	for _, t := range list {
+		vars, _ := t.Vars()
+		table.Append([]string{t.Name, t.Description, t.Title, t.Priority, strings.Join(vars, ", ")})
+	}
+	table.Render()
+}
@@ -0,0 +1,1039 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/checkpoint"
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/entitlement"
+	"github.com/osticket-cli-go/internal/markup"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET COMMANDS ====================
+
+func (app *App) ticketCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ticket",
+		Short: "Manage tickets",
+	}
+
+	// ticket get
+	getCmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get a ticket by ID or ticket number",
+		Example: `  # By ticket ID or number
+  osticket ticket get 12345
+  osticket ticket get API123 -o json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			// Raw output - return exact API response
+			if app.outputFormat(output.JSON) == output.Raw {
+				raw, err := client.GetTicketRaw(cmd.Context(), args[0])
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				app.render(output.JSON, &output.Result{Raw: raw})
+				return
+			}
+
+			data, err := client.GetTicket(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			app.enrichTickets(cmd, client, data.Tickets)
+
+			app.render(output.JSON, &output.Result{
+				Value: data,
+				Rows:  data.Tickets,
+				IDs:   ticketIDs(data.Tickets),
+				Table: func(w io.Writer) { app.displayTicketRows(w, data.Tickets) },
+			})
+		},
+	}
+	addEnrichFlag(getCmd)
+	cmd.AddCommand(getCmd)
+
+	// ticket get-batch
+	getBatchCmd := &cobra.Command{
+		Use:   "get-batch [id...]",
+		Short: "Fetch many tickets concurrently",
+		Long: `Fetch tickets by ID or number, several at a time, and print them as one
+list. IDs come from the arguments and/or --file (one per line, blank lines
+and # comments ignored; - reads stdin). Tickets that cannot be fetched are
+listed on stderr after the rest are printed (as JSON with -o json or
+yaml) and the command exits with status 1. --fail-fast stops requesting
+tickets after the first failure.`,
+		Example: `  osticket ticket get-batch 12345 12346 12347
+  osticket ticket get-batch --file ids.txt --concurrency 16 -o csv
+  cut -d, -f1 escalations.csv | osticket ticket get-batch --file - -o json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			path, _ := cmd.Flags().GetString("file")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			if concurrency < 1 {
+				fmt.Fprintln(app.Err, red("Error:"), "--concurrency must be at least 1")
+				exit(1)
+			}
+			ids := args
+			if path != "" {
+				fileIDs, err := app.readIDs(path)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				ids = append(ids, fileIDs...)
+			}
+			ids = dedupe(ids)
+			if len(ids) == 0 {
+				fmt.Fprintln(app.Err, red("Error:"), "no ticket IDs given: pass them as arguments or with --file")
+				exit(1)
+			}
+
+			// With --fail-fast tickets are fetched --concurrency at a time,
+			// so no more are requested once one has failed
+			tickets := []map[string]interface{}{}
+			failures := newFailures(cmd, "ticket", len(ids))
+			chunk := len(ids)
+			if failures.FailFast {
+				chunk = concurrency
+			}
+			for start := 0; start < len(ids); start += chunk {
+				end := min(start+chunk, len(ids))
+				stop := false
+				for _, r := range client.GetTicketsBatch(cmd.Context(), ids[start:end], concurrency) {
+					if r.Err != nil {
+						stop = failures.Add(r.ID, "", r.Err)
+						continue
+					}
+					tickets = append(tickets, r.Ticket)
+				}
+				if stop {
+					failures.Skip(len(ids) - end)
+					break
+				}
+			}
+			app.enrichTickets(cmd, client, tickets)
+
+			app.render(output.JSON, &output.Result{
+				Value: tickets,
+				Rows:  tickets,
+				IDs:   ticketIDs(tickets),
+				Table: func(w io.Writer) { app.displayTicketRows(w, tickets) },
+			})
+			app.reportFailures(output.JSON, failures)
+		},
+	}
+	getBatchCmd.Flags().String("file", "", "Read ticket IDs or numbers from a file, one per line (- for stdin)")
+	getBatchCmd.Flags().Int("concurrency", osticket.DefaultBatchConcurrency, "Tickets fetched at once")
+	addFailFastFlag(getBatchCmd)
+	addEnrichFlag(getBatchCmd)
+	cmd.AddCommand(getBatchCmd)
+
+	// ticket thread
+	threadCmd := &cobra.Command{
+		Use:   "thread <id>",
+		Short: "Show the conversation thread of a ticket",
+		Example: `  osticket ticket thread 12345
+  osticket ticket thread 12345 -o json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			data, err := client.GetTicketThread(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Entries,
+				Table: func(w io.Writer) {
+					if len(data.Entries) == 0 {
+						fmt.Fprintln(w, yellow("No thread entries found"))
+						return
+					}
+
+					displayThread(w, data.Entries)
+				},
+			})
+		},
+	}
+	cmd.AddCommand(threadCmd)
+
+	// ticket search
+	searchCmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search tickets",
+		Example: `  # Open tickets, one page at a time
+  osticket ticket search --status 1 --limit 50 --page 2
+
+  # Everything from one user, as CSV
+  osticket ticket search --email user@example.com -o csv
+
+  # Every ticket as one JSON object per line, written as pages arrive
+  osticket ticket search --status 0 --all -o ndjson | jq -r .number
+
+  # Open tickets, most urgent first
+  osticket ticket search --status 1 --priority -o table
+
+  # Open high-priority tickets from this year, newest first
+  osticket ticket search --status 1 --all --filter 'priority_id>=3' --filter 'created>=2026-01-01' --sort created --desc
+
+  # Full-text search needs a date range
+  osticket ticket search --term "password reset" --from 2024-01-01 --to 2024-06-30`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			rawOut := app.outputFormat(output.JSON) == output.Raw
+			number, _ := cmd.Flags().GetString("number")
+			email, _ := cmd.Flags().GetString("email")
+			phone, _ := cmd.Flags().GetString("phone")
+			status, _ := cmd.Flags().GetInt("status")
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			term, _ := cmd.Flags().GetString("term")
+			byPriority, _ := cmd.Flags().GetBool("priority")
+			order, err := ticketOrderFlags(cmd)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitValidation)
+			}
+			if byPriority && order.sort != "" {
+				fmt.Fprintln(app.Err, red("Error:"), "--priority cannot be combined with --sort")
+				exit(exitValidation)
+			}
+			page, all := app.searchPage(cmd)
+			if all && rawOut {
+				fmt.Fprintln(app.Err, red("Error:"), "--all cannot be combined with --raw")
+				exit(1)
+			}
+			if !all && (cmd.Flags().Changed("max-duration") || cmd.Flags().Changed("resume")) {
+				fmt.Fprintln(app.Err, red("Error:"), "--max-duration and --resume require --all")
+				exit(1)
+			}
+			// renderTickets shows a search's tickets and, for a search by
+			// email, the user. Filters see the enriched names.
+			renderTickets := func(data *osticket.SimpleTicketResponse, user *osticket.User) {
+				app.enrichTickets(cmd, client, data.Tickets)
+				data.Tickets = order.apply(data.Tickets)
+				if len(order.filters) > 0 {
+					data.Total = len(data.Tickets)
+				}
+				if byPriority {
+					sortByPriority(data.Tickets)
+				}
+				tickets := data.Tickets
+				var value interface{} = data
+				if user != nil {
+					value = map[string]interface{}{
+						"total":   data.Total,
+						"tickets": tickets,
+						"user": map[string]interface{}{
+							"user_id": user.UserID,
+							"name":    user.Name,
+							"created": user.Created,
+						},
+					}
+				}
+				app.render(output.JSON, &output.Result{
+					Value: value,
+					Rows:  tickets,
+					IDs:   ticketIDs(tickets),
+					Table: func(w io.Writer) { app.displayTicketRows(w, tickets) },
+				})
+			}
+			job := checkpoint.Key("ticket search", term, from, to, strconv.Itoa(status), strconv.Itoa(page.Limit))
+			listTickets := func(fetch func(osticket.Page) (*osticket.SimpleTicketResponse, error)) {
+				var stopped bool
+				var err error
+				if all && !byPriority && order.sort == "" && app.outputFormat(output.JSON) == output.NDJSON {
+					// Each page is written as it arrives; sorting needs
+					// every ticket first
+					stopped, err = app.streamTickets(cmd, job, page.Limit, fetch, func(tickets []map[string]interface{}) error {
+						app.enrichTickets(cmd, client, tickets)
+						tickets = order.apply(tickets)
+						app.render(output.NDJSON, &output.Result{Rows: tickets, IDs: ticketIDs(tickets)})
+						return nil
+					})
+				} else {
+					var data *osticket.SimpleTicketResponse
+					data, stopped, err = app.fetchTickets(cmd, job, page, all, fetch)
+					if err == nil {
+						renderTickets(data, nil)
+					}
+				}
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				if stopped {
+					exit(exitIncomplete)
+				}
+			}
+
+			// Handle search by term (requires date range)
+			if term != "" {
+				if from == "" || to == "" {
+					fmt.Fprintln(app.Err, red("Error:"), "--from and --to are required when using --term")
+					exit(1)
+				}
+				if rawOut {
+					raw, err := client.SearchTicketsByTermRaw(cmd.Context(), term, from, to, status, page)
+					if err != nil {
+						fmt.Fprintln(app.Err, red("Error:"), err)
+						exit(exitCode(err))
+					}
+					app.render(output.JSON, &output.Result{Raw: raw})
+					return
+				}
+				listTickets(func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+					return client.SearchTicketsByTerm(cmd.Context(), term, from, to, status, p)
+				})
+				return
+			}
+
+			// Handle search by number
+			if number != "" {
+				if rawOut {
+					raw, err := client.GetTicketRaw(cmd.Context(), number)
+					if err != nil {
+						fmt.Fprintln(app.Err, red("Error:"), err)
+						exit(exitCode(err))
+					}
+					app.render(output.JSON, &output.Result{Raw: raw})
+					return
+				}
+				data, err := client.GetTicket(cmd.Context(), number)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				renderTickets(data, nil)
+				return
+			}
+
+			// Handle search by email
+			if email != "" {
+				email = mustValidate(validate.Email("email", email))
+				data, user, err := client.SearchTicketsByEmail(cmd.Context(), email, status)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				renderTickets(data, user)
+				return
+			}
+
+			if phone != "" {
+				fmt.Fprintln(app.Out, yellow("Phone search requires user lookup. Please search by email or ticket number instead."))
+				return
+			}
+
+			// Handle search by status or date range
+			if rawOut {
+				var raw []byte
+				var err error
+				if from != "" && to != "" {
+					raw, err = client.GetTicketsByDateRangeRaw(cmd.Context(), from, to, page)
+				} else {
+					raw, err = client.GetTicketsByStatusRaw(cmd.Context(), status, page)
+				}
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				app.render(output.JSON, &output.Result{Raw: raw})
+				return
+			}
+
+			listTickets(func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				if from != "" && to != "" {
+					return client.GetTicketsByDateRange(cmd.Context(), from, to, p)
+				}
+				return client.GetTicketsByStatus(cmd.Context(), status, p)
+			})
+		},
+	}
+	searchCmd.Flags().String("number", "", "Search by ticket number")
+	searchCmd.Flags().String("email", "", "Search by user email")
+	searchCmd.Flags().String("phone", "", "Search by user phone number")
+	searchCmd.Flags().String("term", "", "Search by term in subject/body (requires --from and --to)")
+	searchCmd.Flags().Int("status", 0, "Filter by status (0=all, 1=open, 2=resolved, 3=closed)")
+	searchCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	searchCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	searchCmd.Flags().Int("limit", 0, "Maximum number of tickets to return (0 = no limit)")
+	searchCmd.Flags().Int("offset", 0, "Number of tickets to skip")
+	searchCmd.Flags().Int("page", 0, "Page number to return, starting at 1 (requires --limit)")
+	searchCmd.Flags().Bool("priority", false, "Sort tickets by priority, most urgent first")
+	searchCmd.Flags().Bool("all", false, "Fetch every page automatically (page size from --limit, default 100)")
+	addTicketOrderFlags(searchCmd)
+	addEnrichFlag(searchCmd)
+	addBudgetFlags(searchCmd, true)
+	cmd.AddCommand(searchCmd)
+
+	// ticket create
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a new ticket",
+		Example: `  osticket ticket create --title "Printer offline" --subject "3rd floor printer is offline" --user-id 42 --dept Support
+  osticket ticket create --file ticket.yaml --attach photo.jpg
+  osticket ticket create --template outage --var host=web01 --user-id 12`,
+		Long: `Create a ticket from flags, or from a YAML or JSON definition with --file
+('-' reads stdin). Values in the file win; flags supply anything the file
+leaves out. --template fills in a saved template (see 'osticket template')
+with --var values; flags and the file override what it sets. Run without
+flags from a terminal, it prompts for each field instead, as 'ticket new
+--interactive' does.
+
+  title: Disk almost full on web01
+  subject: /var is at 95%
+  user_id: 12
+  priority_id: 3
+  attachments: [df.txt]
+  fields:
+    hostname: web01`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !anyFlagChanged(cmd) && app.interactiveInput() {
+				app.createWithWizard(cmd)
+				return
+			}
+			app.createTicket(cmd)
+		},
+	}
+	addCreateFlags(createCmd)
+	cmd.AddCommand(createCmd)
+
+	// ticket new
+	newCmd := &cobra.Command{
+		Use:     "new",
+		Short:   "Create a new ticket with guided prompts",
+		Example: `  osticket ticket new --interactive`,
+		Run: func(cmd *cobra.Command, args []string) {
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			if !interactive {
+				fmt.Fprintln(app.Err, red("Error:"), "ticket new requires --interactive; use 'ticket create' to pass values as flags")
+				exit(1)
+			}
+			app.createWithWizard(cmd)
+		},
+	}
+	newCmd.Flags().BoolP("interactive", "i", false, "Prompt for each ticket field")
+	cmd.AddCommand(newCmd)
+
+	// ticket reply
+	replyCmd := &cobra.Command{
+		Use:   "reply <ticketId>",
+		Short: "Reply to a ticket",
+		Example: `  osticket ticket reply 12345 --body "Fixed, please confirm." --staff-id 1
+  osticket ticket reply 12345 --body "Log attached." --staff-id 1 --attach debug.log
+  osticket ticket reply 12345 --edit --staff-id 1
+  osticket ticket reply 12345 --canned "Password reset" --var eta="an hour" --staff-id 1
+  osticket ticket reply 12345 --body-format markdown --body "**Fixed** in [v2.1](https://example.com/changelog)" --staff-id 1`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			ticketID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid ticket ID"))
+				exit(1)
+			}
+
+			if canned, _ := cmd.Flags().GetString("canned"); canned != "" {
+				app.useCanned(cmd, client, ticketID, canned)
+			}
+			body := app.messageBody(cmd, client, ticketID, "reply")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			attach, _ := cmd.Flags().GetStringArray("attach")
+
+			err = client.ReplyToTicket(cmd.Context(), ticketID, body, staffID, app.loadAttachments(attach)...)
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(ticketID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Reply sent successfully!"))
+				},
+			})
+		},
+	}
+	replyCmd.Flags().String("body", "", "Reply body (required unless --edit or --canned)")
+	replyCmd.Flags().Int("staff-id", 0, "Staff ID")
+	replyCmd.Flags().StringArray("attach", nil, "Attach a file (repeatable)")
+	addEditFlags(replyCmd)
+	addBodyFormatFlag(replyCmd)
+	replyCmd.Flags().String("canned", "", "Send a canned response, by ID or title (see 'osticket canned list')")
+	replyCmd.Flags().StringArray("var", nil, "Canned response variable as key=value, e.g. ticket.name=Ann (repeatable)")
+	replyCmd.MarkFlagRequired("staff-id")
+	cmd.AddCommand(replyCmd)
+
+	// ticket note
+	noteCmd := &cobra.Command{
+		Use:   "note <ticketId>",
+		Short: "Add an internal note to a ticket",
+		Example: `  osticket ticket note 12345 --title "Escalated" --body "Waiting on vendor." --staff-id 1
+  osticket ticket note 12345 --title "Findings" --edit --no-quote --staff-id 1`,
+		Long: `Add an internal note visible only to staff. Unlike 'ticket reply', the user
+is not emailed, so automation can attach diagnostic information safely.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			ticketID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid ticket ID"))
+				exit(1)
+			}
+
+			title, _ := cmd.Flags().GetString("title")
+			body := app.messageBody(cmd, client, ticketID, "note")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			attach, _ := cmd.Flags().GetStringArray("attach")
+
+			err = client.AddInternalNote(cmd.Context(), ticketID, title, body, staffID, app.loadAttachments(attach)...)
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(ticketID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Internal note added successfully!"))
+				},
+			})
+		},
+	}
+	noteCmd.Flags().String("title", "", "Note title")
+	noteCmd.Flags().String("body", "", "Note body (required unless --edit)")
+	noteCmd.Flags().Int("staff-id", 0, "Staff ID")
+	noteCmd.Flags().StringArray("attach", nil, "Attach a file (repeatable)")
+	addEditFlags(noteCmd)
+	addBodyFormatFlag(noteCmd)
+	noteCmd.MarkFlagRequired("staff-id")
+	cmd.AddCommand(noteCmd)
+
+	// ticket close
+	closeCmd := &cobra.Command{
+		Use:   "close <ticketId>",
+		Short: "Close a ticket",
+		Example: `  osticket ticket close 12345 --body "Resolved by replacing the toner." --staff-id 1
+  osticket ticket close 12345 --edit --staff-id 1 --username jdoe`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			ticketID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid ticket ID"))
+				exit(1)
+			}
+
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			username, _ := cmd.Flags().GetString("username")
+			status, _ := cmd.Flags().GetInt("status")
+			team := app.namedIDFlag(cmd, client, "team")
+			dept := app.namedIDFlag(cmd, client, "dept")
+			topic := app.namedIDFlag(cmd, client, "topic")
+
+			app.validateIDFlags(cmd, client)
+			body := app.messageBody(cmd, client, ticketID, "closing message")
+
+			err = client.CloseTicket(cmd.Context(), osticket.CloseTicketParams{
+				TicketID: ticketID,
+				Body:     body,
+				StaffID:  staffID,
+				StatusID: status,
+				TeamID:   team,
+				DeptID:   dept,
+				TopicID:  topic,
+				Username: username,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(ticketID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Ticket closed successfully!"))
+				},
+			})
+		},
+	}
+	closeCmd.Flags().String("body", "", "Closing message (required unless --edit)")
+	closeCmd.Flags().Int("staff-id", 0, "Staff ID")
+	closeCmd.Flags().String("username", "", "Username")
+	closeCmd.Flags().Int("status", 3, "Status ID (default: 3 for closed)")
+	closeCmd.Flags().String("team", "1", "Team ID or name (see 'osticket info teams')")
+	closeCmd.Flags().String("dept", "1", "Department ID or name")
+	closeCmd.Flags().String("topic", "1", "Topic ID or name")
+	addValidateFlag(closeCmd)
+	addEditFlags(closeCmd)
+	addBodyFormatFlag(closeCmd)
+	closeCmd.MarkFlagRequired("staff-id")
+	closeCmd.MarkFlagRequired("username")
+	cmd.AddCommand(closeCmd)
+
+	// ticket assign
+	assignCmd := &cobra.Command{
+		Use:   "assign <ticketId>",
+		Short: "Assign a ticket to an agent or team",
+		Example: `  osticket ticket assign 12345 --staff-id 7 --comment "Printer expert"
+  osticket ticket assign 12345 --team "Level 2"`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			ticketID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid ticket ID"))
+				exit(1)
+			}
+
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			team := app.namedIDFlag(cmd, client, "team")
+			comment, _ := cmd.Flags().GetString("comment")
+
+			err = client.AssignTicket(cmd.Context(), osticket.AssignTicketParams{
+				TicketID: ticketID,
+				StaffID:  staffID,
+				TeamID:   team,
+				Comment:  comment,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]string{"status": "success"},
+				IDs:   []string{strconv.Itoa(ticketID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ Ticket assigned successfully!"))
+				},
+			})
+		},
+	}
+	assignCmd.Flags().Int("staff-id", 0, "Assign to this staff member")
+	assignCmd.Flags().String("team", "", "Assign to this team (ID or name, see 'osticket info teams')")
+	assignCmd.Flags().String("comment", "", "Assignment comment")
+	assignCmd.MarkFlagsOneRequired("staff-id", "team")
+	assignCmd.MarkFlagsMutuallyExclusive("staff-id", "team")
+	cmd.AddCommand(assignCmd)
+
+	// ticket set-status
+	setStatusCmd := &cobra.Command{
+		Use:     "set-status <ticketId>",
+		Short:   "Change a ticket's status (reopen, resolve, close)",
+		Example: `  osticket ticket set-status 12345 --status resolved --staff-id 1 --comment "Confirmed by user"`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			ticketID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid ticket ID"))
+				exit(1)
+			}
+
+			statusValue, _ := cmd.Flags().GetString("status")
+			statusID, err := validate.Named("status", statusValue, statusChoices)
+			if err != nil {
+				// Custom statuses such as "On Hold" are only known to the server
+				statusID = app.namedIDFlag(cmd, client, "status")
+			}
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			comment, _ := cmd.Flags().GetString("comment")
+			if statusID == osticket.StatusArchived || statusID == osticket.StatusDeleted {
+				app.checkNotHeld(cmd.Context(), client, args[0])
+			}
+
+			err = client.UpdateTicketStatus(cmd.Context(), osticket.UpdateTicketStatusParams{
+				TicketID: ticketID,
+				StatusID: statusID,
+				StaffID:  staffID,
+				Comment:  comment,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"status": "success", "status_id": statusID},
+				IDs:   []string{strconv.Itoa(ticketID)},
+				Table: func(w io.Writer) {
+					label := statusName(statusID)
+					if _, err := strconv.Atoi(statusValue); err != nil {
+						label = statusValue
+					}
+					fmt.Fprintln(w, green(fmt.Sprintf("\n✓ Ticket status set to %s", label)))
+				},
+			})
+		},
+	}
+	setStatusCmd.Flags().String("status", "", "New status: open, resolved, closed, archived, deleted, a custom status name or a status ID (see 'osticket info statuses')")
+	setStatusCmd.Flags().Int("staff-id", 0, "Staff ID making the change")
+	setStatusCmd.Flags().String("comment", "", "Comment recorded with the change")
+	setStatusCmd.MarkFlagRequired("status")
+	cmd.AddCommand(setStatusCmd)
+
+	// ticket archive
+	archiveCmd := &cobra.Command{
+		Use:   "archive <id>",
+		Short: "Archive a ticket",
+		Long: `Move a ticket, by ID or number, to the Archived status. The ticket is shown
+and confirmation asked first unless --yes is given. Tickets under legal
+hold ('osticket hold') are refused.`,
+		Example: `  osticket ticket archive 12345
+  osticket ticket archive 100042 --yes --staff-id 1 --comment "Spam"`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			app.removeTicket(cmd, args[0], "archive")
+		},
+	}
+	addRemoveFlags(archiveCmd)
+	cmd.AddCommand(archiveCmd)
+
+	// ticket delete
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a ticket",
+		Long: `Delete a ticket, by ID or number, by moving it to the Deleted status. The
+ticket is shown and confirmation asked first unless --yes is given.
+Tickets under legal hold ('osticket hold') are refused.`,
+		Example: `  osticket ticket delete 12345
+  osticket ticket delete 100042 --yes --staff-id 1 --comment "Spam"`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			app.removeTicket(cmd, args[0], "delete")
+		},
+	}
+	addRemoveFlags(deleteCmd)
+	cmd.AddCommand(deleteCmd)
+
+	// ticket watch
+	cmd.AddCommand(app.watchCmd())
+	cmd.AddCommand(app.exportCmd())
+	cmd.AddCommand(app.workspaceCmd())
+	cmd.AddCommand(app.overdueCmd())
+
+	return cmd
+}
+
+func displayThread(w io.Writer, entries []osticket.ThreadEntry) {
+	for _, e := range entries {
+		label := e.TypeName()
+		switch e.Type {
+		case osticket.ThreadMessage:
+			label = cyan(label)
+		case osticket.ThreadResponse:
+			label = green(label)
+		case osticket.ThreadNote:
+			label = yellow(label)
+		}
+
+		poster := e.Poster
+		if poster == "" {
+			poster = "(unknown)"
+		}
+
+		fmt.Fprintf(w, "\n[%s] %s — %s\n", label, poster, e.Created)
+		if e.Title != "" {
+			fmt.Fprintf(w, "%s\n", e.Title)
+		}
+		fmt.Fprintln(w, entryText(e))
+	}
+	fmt.Fprintf(w, "\nTotal: %d thread entries\n", len(entries))
+}
+
+// entryText returns a thread entry's body as readable text, converting
+// HTML bodies, including those whose format the server does not say
+func entryText(e osticket.ThreadEntry) string {
+	body := strings.TrimSpace(e.Body)
+	if e.Format == "html" || e.Format == "" && markup.IsHTML(body) {
+		return markup.ToText(body)
+	}
+	return body
+}
+
+// addRemoveFlags registers the flags of ticket archive and delete
+func addRemoveFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().Int("staff-id", 0, "Staff ID making the change")
+	cmd.Flags().String("comment", "", "Comment recorded with the change")
+}
+
+// removeTicket archives or deletes a ticket after checking legal holds
+// and asking for confirmation
+func (app *App) removeTicket(cmd *cobra.Command, id, action string) {
+	client := app.client(cmd.Context())
+	yes, _ := cmd.Flags().GetBool("yes")
+	staffID, _ := cmd.Flags().GetInt("staff-id")
+	comment, _ := cmd.Flags().GetString("comment")
+
+	t := app.checkNotHeld(cmd.Context(), client, id)
+	ticketID := mapInt(t, "ticket_id")
+	number := mapString(t, "number")
+
+	if !yes {
+		fmt.Fprintf(app.promptOut(), "Ticket #%s: %s (%s)\n", number, mapString(t, "subject"), statusName(mapInt(t, "status_id")))
+		ok, err := app.promptConfirm(strings.ToUpper(action[:1])+action[1:]+" this ticket?", false)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		if !ok {
+			fmt.Fprintln(app.Out, yellow("Ticket not "+action+"d"))
+			return
+		}
+	}
+
+	var err error
+	if action == "delete" {
+		err = client.DeleteTicket(cmd.Context(), ticketID, staffID, comment)
+	} else {
+		err = client.ArchiveTicket(cmd.Context(), ticketID, staffID, comment)
+	}
+	if app.queued(err) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+
+	app.render(output.Table, &output.Result{
+		Value: map[string]interface{}{"status": "success", "ticket_id": ticketID, "action": action},
+		IDs:   []string{strconv.Itoa(ticketID)},
+		Table: func(w io.Writer) {
+			fmt.Fprintln(w, green(fmt.Sprintf("✓ Ticket #%s %sd", number, action)))
+		},
+	})
+}
+
+// addCreateFlags registers the flags of ticket create and template apply
+func addCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("title", "", "Ticket title")
+	cmd.Flags().String("subject", "", "Ticket subject/body")
+	cmd.Flags().Int("user-id", 0, "User ID")
+	cmd.Flags().String("email", "", "Requester email, instead of --user-id")
+	cmd.Flags().Int("priority", 2, "Priority ID (see 'osticket info priorities'; 1=low, 2=normal, 3=high, 4=emergency by default)")
+	cmd.Flags().Int("status", 1, "Status ID (see 'osticket info statuses'; 1=open)")
+	cmd.Flags().String("dept", "1", "Department ID or name")
+	cmd.Flags().String("sla", "1", "SLA ID or name")
+	cmd.Flags().String("topic", "1", "Topic ID or name")
+	cmd.Flags().StringArray("attach", nil, "Attach a file (repeatable)")
+	addBodyFormatFlag(cmd)
+	cmd.Flags().StringArray("field", nil, "Custom form field as key=value (repeatable)")
+	cmd.Flags().StringP("file", "f", "", "Read the ticket from a YAML or JSON file ('-' for stdin)")
+	cmd.Flags().String("template", "", "Start from a saved ticket template")
+	cmd.Flags().StringArray("var", nil, "Template variable as key=value (repeatable)")
+	cmd.Flags().Bool("no-entitlement", false, "Skip the entitlement hook (see 'osticket config entitlement')")
+	addValidateFlag(cmd)
+}
+
+// createTicket creates a ticket from the flags of ticket create, a
+// template and a ticket file
+func (app *App) createTicket(cmd *cobra.Command) {
+	client := app.client(cmd.Context())
+
+	fields := map[string]interface{}{}
+	if name, _ := cmd.Flags().GetString("template"); name != "" {
+		for k, v := range app.applyTemplate(cmd, name).Fields {
+			fields[k] = v
+		}
+	}
+	fieldPairs, _ := cmd.Flags().GetStringArray("field")
+	if err := setParams(fields, "--field", fieldPairs); err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if path, _ := cmd.Flags().GetString("file"); path != "" {
+		f, err := app.loadTicketFile(path)
+		if err == nil {
+			err = f.applyTo(cmd)
+		}
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+		for k, v := range f.Fields {
+			fields[k] = v
+		}
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	subject, _ := cmd.Flags().GetString("subject")
+	userID, _ := cmd.Flags().GetInt("user-id")
+	priority, _ := cmd.Flags().GetInt("priority")
+	status, _ := cmd.Flags().GetInt("status")
+	attach, _ := cmd.Flags().GetStringArray("attach")
+	email, _ := cmd.Flags().GetString("email")
+	noEntitlement, _ := cmd.Flags().GetBool("no-entitlement")
+	if userID == 0 && email != "" {
+		userID = app.userIDByEmail(cmd.Context(), client, email)
+	}
+	if title == "" || subject == "" || userID == 0 {
+		fmt.Fprintln(app.Err, red("Error:"), "a title, subject and user (--user-id or --email) are required (flags, --file or --template)")
+		exit(1)
+	}
+	subject = app.convertBody(cmd, subject)
+
+	var ent *entitlement.Entitlement
+	if !noEntitlement {
+		var sla string
+		ent, sla = app.checkEntitlement(cmd.Context(), client, userID, email, fields)
+		// An SLA given with --sla, the file or the template wins
+		if sla != "" && !cmd.Flags().Changed("sla") {
+			cmd.Flags().Set("sla", sla)
+		}
+	}
+	dept := app.namedIDFlag(cmd, client, "dept")
+	sla := app.namedIDFlag(cmd, client, "sla")
+	topic := app.namedIDFlag(cmd, client, "topic")
+
+	app.validateIDFlags(cmd, client)
+
+	ticketID, err := client.CreateTicket(cmd.Context(), osticket.CreateTicketParams{
+		Title:       title,
+		Subject:     subject,
+		UserID:      userID,
+		PriorityID:  priority,
+		StatusID:    status,
+		DeptID:      dept,
+		SLAID:       sla,
+		TopicID:     topic,
+		Fields:      fields,
+		Attachments: app.loadAttachments(attach),
+	})
+
+	if app.queued(err) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+
+	value := map[string]interface{}{"ticket_id": ticketID}
+	if ent != nil {
+		value["entitlement"] = ent
+	}
+	app.render(output.Table, &output.Result{
+		Value: value,
+		IDs:   []string{strconv.Itoa(ticketID)},
+		Table: func(w io.Writer) {
+			fmt.Fprintln(w, green("\n✓ Ticket created successfully!"))
+			fmt.Fprintf(w, "  Ticket ID: %d\n", ticketID)
+			if ent != nil {
+				fmt.Fprintf(w, "  Entitlement: %s\n", describeEntitlement(ent))
+			}
+		},
+	})
+}
+
+// userIDByEmail returns the ID of the user with an email, exiting when
+// there is none
+func (app *App) userIDByEmail(ctx context.Context, client osticket.OSTicketAPI, email string) int {
+	data, err := client.GetUserByEmail(ctx, email)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if len(data.Users) == 0 {
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("no user with email %s", email))
+		exit(exitNotFound)
+	}
+	return data.Users[0].UserID
+}
+
+// checkEntitlement asks the profile's entitlement hook about the
+// requester, stamps the contract into fields and returns it with the SLA
+// its tier maps to. Without a hook, an email or an answer the ticket is
+// created as it is; failures are warnings.
+func (app *App) checkEntitlement(ctx context.Context, client osticket.OSTicketAPI, userID int, email string, fields map[string]interface{}) (*entitlement.Entitlement, string) {
+	spec := config.GetEntitlementSpec()
+	if spec.Kind == "" {
+		return nil, ""
+	}
+	if email == "" {
+		if data, err := client.GetUserByID(ctx, strconv.Itoa(userID)); err == nil && len(data.Users) > 0 {
+			email = data.Users[0].Email
+		}
+	}
+	if email == "" {
+		app.logger().Warn(fmt.Sprintf("no email known for user %d; skipping the entitlement check (use --email)", userID))
+		return nil, ""
+	}
+
+	ent, err := entitlement.Check(ctx, spec, email)
+	if err != nil {
+		app.logger().Warn(fmt.Sprintf("%v; creating the ticket without entitlement data", err))
+		return nil, ""
+	}
+	if ent == nil {
+		app.logger().Info("no entitlement", "email", email)
+		return nil, ""
+	}
+	ent.Stamp(spec, fields)
+	sla := ent.SLA(spec, time.Now())
+	app.logger().Info("entitlement", "email", email, "tier", ent.Tier, "expires", ent.Expires, "sla", sla)
+	return ent, sla
+}
+
+// describeEntitlement summarizes a contract, e.g. "gold, expires 2027-03-31"
+func describeEntitlement(ent *entitlement.Entitlement) string {
+	desc := ent.Tier
+	if desc == "" {
+		desc = "no tier"
+	}
+	if ent.Expires != "" {
+		if ent.Expired(time.Now()) {
+			desc += ", " + yellow("expired "+ent.Expires)
+		} else {
+			desc += ", expires " + ent.Expires
+		}
+	}
+	return desc
+}
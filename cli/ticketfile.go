@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ==================== TICKET DEFINITION FILES ====================
+
+// ticketFile is a ticket definition read by ticket create --file. It is
+// YAML or JSON; unset fields fall back to the command's flags.
+type ticketFile struct {
+	Title       *string                `yaml:"title"`
+	Subject     *string                `yaml:"subject"`
+	UserID      *int                   `yaml:"user_id"`
+	PriorityID  *int                   `yaml:"priority_id"`
+	StatusID    *int                   `yaml:"status_id"`
+	DeptID      *int                   `yaml:"dept_id"`
+	SLAID       *int                   `yaml:"sla_id"`
+	TopicID     *int                   `yaml:"topic_id"`
+	Attachments []string               `yaml:"attachments"`
+	Fields      map[string]interface{} `yaml:"fields"`
+}
+
+// loadTicketFile reads a ticket definition from a file or, for "-", stdin.
+// Relative attachment paths are resolved against the file's directory.
+func (app *App) loadTicketFile(path string) (*ticketFile, error) {
+	var data []byte
+	var err error
+	dir := "."
+	if path == "-" {
+		data, err = io.ReadAll(app.In)
+	} else {
+		data, err = os.ReadFile(path)
+		dir = filepath.Dir(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read ticket file: %w", err)
+	}
+
+	var f ticketFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid ticket file %s: %w", path, err)
+	}
+
+	for i, a := range f.Attachments {
+		if !filepath.IsAbs(a) {
+			f.Attachments[i] = filepath.Join(dir, a)
+		}
+	}
+	return &f, nil
+}
+
+// applyTo sets the create command's flags from the file, so flags only
+// supply values the file leaves out
+func (f *ticketFile) applyTo(cmd *cobra.Command) error {
+	set := func(flag string, value string) error {
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return fmt.Errorf("invalid %s in ticket file: %w", flag, err)
+		}
+		return nil
+	}
+
+	strs := map[string]*string{"title": f.Title, "subject": f.Subject}
+	for flag, v := range strs {
+		if v != nil {
+			if err := set(flag, *v); err != nil {
+				return err
+			}
+		}
+	}
+	ints := map[string]*int{
+		"user-id":  f.UserID,
+		"priority": f.PriorityID,
+		"status":   f.StatusID,
+		"dept":     f.DeptID,
+		"sla":      f.SLAID,
+		"topic":    f.TopicID,
+	}
+	for flag, v := range ints {
+		if v != nil {
+			if err := set(flag, strconv.Itoa(*v)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, a := range f.Attachments {
+		if err := set("attach", a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== USER COMMANDS ====================
+
+func (app *App) userCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	// user get
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get a user",
+		Example: `  osticket user get --email user@example.com
+  osticket user get --id 42 -o yaml`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			id, _ := cmd.Flags().GetString("id")
+			email, _ := cmd.Flags().GetString("email")
+
+			var data *osticket.UserData
+			var err error
+
+			if id != "" {
+				data, err = client.GetUserByID(cmd.Context(), id)
+			} else if email != "" {
+				data, err = client.GetUserByEmail(cmd.Context(), mustValidate(validate.Email("email", email)))
+			} else {
+				fmt.Fprintln(app.Err, red("Please provide --id or --email"))
+				exit(1)
+			}
+
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: data,
+				Rows:  data.Users,
+				IDs:   userIDs(data.Users),
+				Table: func(w io.Writer) {
+					if len(data.Users) == 0 {
+						fmt.Fprintln(w, yellow("No user found"))
+						return
+					}
+
+					displayUsers(w, data.Users)
+				},
+			})
+		},
+	}
+	getCmd.Flags().String("id", "", "User ID")
+	getCmd.Flags().String("email", "", "User email")
+	cmd.AddCommand(getCmd)
+
+	// user create
+	createCmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create a new user",
+		Example: `  osticket user create --name "Jane Doe" --email jane@example.com --phone "555 0100" --org-id 3`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			name, _ := cmd.Flags().GetString("name")
+			email, _ := cmd.Flags().GetString("email")
+			password, _ := cmd.Flags().GetString("password")
+			phone, _ := cmd.Flags().GetString("phone")
+			timezone, _ := cmd.Flags().GetString("timezone")
+			orgID, _ := cmd.Flags().GetInt("org-id")
+			sendWelcome, _ := cmd.Flags().GetBool("send-welcome-email")
+			phoneCountry, _ := cmd.Flags().GetString("phone-country")
+
+			email = mustValidate(validate.Email("email", email))
+			timezone = mustValidate(validate.Timezone("timezone", timezone))
+			if phone != "" {
+				phone = mustValidate(validate.Phone("phone", phone, phoneCountry))
+			}
+
+			userID, err := client.CreateUser(cmd.Context(), osticket.CreateUserParams{
+				Name:             name,
+				Email:            email,
+				Password:         password,
+				Phone:            phone,
+				Timezone:         timezone,
+				OrgID:            orgID,
+				Status:           osticket.UserStatusActive,
+				SendWelcomeEmail: sendWelcome,
+			})
+
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]int{"user_id": userID},
+				IDs:   []string{strconv.Itoa(userID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("\n✓ User created successfully!"))
+					fmt.Fprintf(w, "  User ID: %d\n", userID)
+				},
+			})
+		},
+	}
+	createCmd.Flags().String("name", "", "User name")
+	createCmd.Flags().String("email", "", "User email")
+	createCmd.Flags().String("password", "", "User password (omit to create a guest requester)")
+	createCmd.Flags().String("phone", "", "User phone number (optional, normalized to E.164)")
+	createCmd.Flags().String("phone-country", "1", "Country calling code for phone numbers without one")
+	createCmd.Flags().String("timezone", "America/New_York", "Timezone")
+	createCmd.Flags().Int("org-id", 0, "Organization ID")
+	createCmd.Flags().Bool("send-welcome-email", false, "Send the new user a welcome email")
+	createCmd.MarkFlagRequired("name")
+	createCmd.MarkFlagRequired("email")
+	cmd.AddCommand(createCmd)
+
+	// user update
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Change a user's name, email, phone or status",
+		Example: `  osticket user update --id 42 --email jane.doe@example.com
+  osticket user update --id 42 --name "Jane Doe" --phone "555 0100"
+  osticket user update --id 42 --status active`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+
+			id, _ := cmd.Flags().GetInt("id")
+			name, _ := cmd.Flags().GetString("name")
+			email, _ := cmd.Flags().GetString("email")
+			phone, _ := cmd.Flags().GetString("phone")
+			phoneCountry, _ := cmd.Flags().GetString("phone-country")
+			statusValue, _ := cmd.Flags().GetString("status")
+
+			params := osticket.UpdateUserParams{UserID: id, Name: name}
+			if email != "" {
+				params.Email = mustValidate(validate.Email("email", email))
+			}
+			if phone != "" {
+				params.Phone = mustValidate(validate.Phone("phone", phone, phoneCountry))
+			}
+			if statusValue != "" {
+				params.Status = mustValidate(validate.Named("status", statusValue, userStatusChoices))
+			}
+
+			err := client.UpdateUser(cmd.Context(), params)
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"status": "success", "user_id": id},
+				IDs:   []string{strconv.Itoa(id)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green(fmt.Sprintf("\n✓ User %d updated", id)))
+				},
+			})
+		},
+	}
+	updateCmd.Flags().Int("id", 0, "User ID")
+	updateCmd.Flags().String("name", "", "New name")
+	updateCmd.Flags().String("email", "", "New email")
+	updateCmd.Flags().String("phone", "", "New phone number (normalized to E.164)")
+	updateCmd.Flags().String("phone-country", "1", "Country calling code for phone numbers without one")
+	updateCmd.Flags().String("status", "", "New status: active, disabled or a status ID")
+	updateCmd.MarkFlagRequired("id")
+	updateCmd.MarkFlagsOneRequired("name", "email", "phone", "status")
+	cmd.AddCommand(updateCmd)
+
+	// user disable
+	disableCmd := &cobra.Command{
+		Use:   "disable <id>",
+		Short: "Disable a user's account",
+		Long: `Lock a user's account so they can no longer sign in, e.g. when they leave
+the company. Their tickets are kept. The user is shown and confirmation
+asked first unless --yes is given. Re-enable the account with
+'osticket user update --id <id> --status active'.`,
+		Example: `  osticket user disable 42
+  osticket user disable 42 --yes`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := app.client(cmd.Context())
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			userID, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Invalid user ID"))
+				exit(1)
+			}
+
+			if !yes {
+				data, err := client.GetUserByID(cmd.Context(), args[0])
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				if len(data.Users) == 0 {
+					fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("user %d not found", userID))
+					exit(exitNotFound)
+				}
+				fmt.Fprintf(app.promptOut(), "User %d: %s\n", userID, data.Users[0].Name)
+				ok, err := app.promptConfirm("Disable this user?", false)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				if !ok {
+					fmt.Fprintln(app.Out, yellow("User not disabled"))
+					return
+				}
+			}
+
+			err = client.DisableUser(cmd.Context(), userID)
+			if app.queued(err) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: map[string]interface{}{"status": "success", "user_id": userID, "action": "disable"},
+				IDs:   []string{strconv.Itoa(userID)},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green(fmt.Sprintf("✓ User %d disabled", userID)))
+				},
+			})
+		},
+	}
+	disableCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	cmd.AddCommand(disableCmd)
+
+	return cmd
+}
+
+// userIDs returns the IDs of users, for --quiet
+func userIDs(users []osticket.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = strconv.Itoa(u.UserID)
+	}
+	return ids
+}
+
+func displayUsers(w io.Writer, users []osticket.User) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Name", "Created"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+
+	for _, user := range users {
+		table.Append([]string{
+			strconv.Itoa(user.UserID),
+			user.Name,
+			user.Created,
+		})
+	}
+
+	table.Render()
+}
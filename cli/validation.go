@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== SERVER-SIDE FLAG VALIDATION ====================
+
+// Validation modes for --validate
+const (
+	validateNone   = "none"
+	validateServer = "server"
+)
+
+// statusChoices are the ticket statuses osTicket ships with, used where
+// the server's own list is not available
+var statusChoices = []validate.Choice{
+	{ID: 1, Name: "Open"},
+	{ID: 2, Name: "Resolved"},
+	{ID: 3, Name: "Closed"},
+	{ID: 4, Name: "Archived"},
+	{ID: 5, Name: "Deleted"},
+}
+
+// userStatusChoices are the user account statuses 'user update' can set
+var userStatusChoices = []validate.Choice{
+	{ID: osticket.UserStatusActive, Name: "Active"},
+	{ID: osticket.UserStatusLocked, Name: "Disabled"},
+}
+
+// statusName returns the name of a shipped status, or its ID otherwise
+func statusName(id int) string {
+	for _, c := range statusChoices {
+		if c.ID == id {
+			return c.Name
+		}
+	}
+	return fmt.Sprintf("status %d", id)
+}
+
+// addValidateFlag registers --validate on a command
+func addValidateFlag(cmd *cobra.Command) {
+	cmd.Flags().String("validate", validateNone, "Check IDs before sending: none or server (against server metadata)")
+}
+
+// idFlags lists the ID flags of a command to check with --validate=server.
+// Flags that were not registered on the command are skipped.
+var idFlags = []string{"dept", "topic", "sla", "team", "status", "priority"}
+
+// validateIDFlags checks ID flags against server metadata when the command
+// was run with --validate=server, exiting with a suggestion on failure
+func (app *App) validateIDFlags(cmd *cobra.Command, client osticket.OSTicketAPI) {
+	mode, _ := cmd.Flags().GetString("validate")
+	switch mode {
+	case validateNone, "":
+		return
+	case validateServer:
+	default:
+		fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("invalid --validate %q: use none or server", mode))
+		exit(1)
+	}
+
+	for _, flag := range idFlags {
+		if cmd.Flags().Lookup(flag) == nil {
+			continue
+		}
+		value, err := cmd.Flags().GetInt(flag)
+		if err != nil {
+			// Flags taking a name are strings; names are checked when resolved
+			s, _ := cmd.Flags().GetString(flag)
+			if value, err = strconv.Atoi(s); err != nil {
+				continue
+			}
+		}
+
+		choices, err := idChoices(cmd.Context(), client, flag)
+		if err != nil {
+			fmt.Fprintln(app.Err, red("Error loading server metadata:"), err)
+			exit(1)
+		}
+		if err := validate.ID(flag, value, choices); err != nil {
+			fmt.Fprintln(app.Err, red("Error:"), err)
+			exit(exitCode(err))
+		}
+	}
+}
+
+// idKinds names what each name-capable ID flag refers to
+var idKinds = map[string]string{
+	"dept":   "department",
+	"topic":  "help topic",
+	"sla":    "SLA plan",
+	"team":   "team",
+	"status": "status",
+}
+
+// namedIDFlag returns the ID given to a flag such as --dept, which takes
+// either an ID or a name. Names are looked up on the server (through the
+// list cache); unknown or ambiguous names exit with suggestions. An unset
+// flag without a default is 0.
+func (app *App) namedIDFlag(cmd *cobra.Command, client osticket.OSTicketAPI, flag string) int {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		return 0
+	}
+	if id, err := strconv.Atoi(value); err == nil {
+		return id
+	}
+
+	choices, err := idChoices(cmd.Context(), client, flag)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error loading server metadata:"), err)
+		exit(1)
+	}
+	return mustValidate(validate.Resolve(flag, idKinds[flag], value, choices))
+}
+
+// idChoices returns the allowed values for an ID flag
+func idChoices(ctx context.Context, client osticket.OSTicketAPI, flag string) ([]validate.Choice, error) {
+	var choices []validate.Choice
+
+	switch flag {
+	case "dept":
+		data, err := client.GetDepartments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data.Departments {
+			choices = append(choices, validate.Choice{ID: d.ID, Name: d.Name})
+		}
+	case "topic":
+		data, err := client.GetTopics(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range data.Topics {
+			choices = append(choices, validate.Choice{ID: t.TopicID, Name: t.Topic})
+		}
+	case "sla":
+		data, err := client.GetSLAs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range data.SLA {
+			choices = append(choices, validate.Choice{ID: s.ID, Name: s.Name})
+		}
+	case "team":
+		data, err := client.GetTeams(ctx)
+		var apiErr *osticket.Error
+		if errors.As(err, &apiErr) {
+			// Older API plugins do not answer the team query; no choices
+			// means IDs are not checked
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range data.Teams {
+			choices = append(choices, validate.Choice{ID: t.ID, Name: t.Name})
+		}
+	case "status":
+		data, err := client.GetStatuses(ctx)
+		var apiErr *osticket.Error
+		if errors.As(err, &apiErr) {
+			// Older API plugins do not answer the status query
+			return statusChoices, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range data.Statuses {
+			choices = append(choices, validate.Choice{ID: s.ID, Name: s.Name})
+		}
+	case "priority":
+		data, err := client.GetPriorities(ctx)
+		var apiErr *osticket.Error
+		if errors.As(err, &apiErr) {
+			// Older API plugins do not answer the priority query; fall
+			// back to the priorities osTicket ships with
+			for id := osticket.PriorityLow; id <= osticket.PriorityEmergency; id++ {
+				choices = append(choices, validate.Choice{ID: id, Name: osticket.PriorityNames[id]})
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range data.Priorities {
+			choices = append(choices, validate.Choice{ID: p.ID, Name: p.Name})
+		}
+	}
+
+	return choices, nil
+}
@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/internal/config"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/internal/warehouse"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== WAREHOUSE EXPORT ====================
+
+func (app *App) exportGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export data to external systems",
+	}
+
+	// export warehouse
+	warehouseCmd := &cobra.Command{
+		Use:   "warehouse",
+		Short: "Upsert tickets into a Postgres or BigQuery analytics database",
+		Long: `Load tickets straight into an analytics database. Tables are created, and
+new columns added, as needed; rows are upserted by their key so the load
+can be repeated safely.
+
+  osticket export warehouse --dsn postgres://etl@db.example.com/analytics
+  osticket export warehouse --dsn bigquery://my-project/support --threads
+
+Three tables are written, named after --table:
+  <table>           one row per ticket, keyed by ticket_id
+  <table>_threads   thread entries, keyed by id (with --threads)
+  <table>_metrics   daily counts per status and department
+
+Postgres is loaded with psql and BigQuery with the bq CLI, so their usual
+credentials (~/.pgpass, gcloud auth) apply. With --since-last-run only
+tickets changed since the previous load are upserted; metrics always cover
+every ticket.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dsn, _ := cmd.Flags().GetString("dsn")
+			tableName, _ := cmd.Flags().GetString("table")
+			status, _ := cmd.Flags().GetInt("status")
+			pageSize, _ := cmd.Flags().GetInt("limit")
+			threads, _ := cmd.Flags().GetBool("threads")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			sinceLastRun, _ := cmd.Flags().GetBool("since-last-run")
+			statePath, _ := cmd.Flags().GetString("state")
+			if statePath == "" {
+				statePath = config.GetExportStatePath("warehouse")
+			}
+
+			loader, err := warehouse.Open(dsn)
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			tables := warehouseTables(tableName)
+			for _, t := range tables {
+				if err := warehouse.CheckTable(t); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+			}
+
+			var state exportState
+			if sinceLastRun {
+				state, err = loadExportState(statePath)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+			}
+
+			client := app.client(cmd.Context())
+			data, err := osticket.CollectPages(pageSize, func(p osticket.Page) (*osticket.SimpleTicketResponse, error) {
+				return client.GetTicketsByStatus(cmd.Context(), status, p)
+			})
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+
+			tickets := data.Tickets
+			if sinceLastRun {
+				tickets = changedSince(tickets, state)
+			}
+
+			now := time.Now()
+			loads := []warehouseLoad{
+				{tables[0], ticketRows(tickets, now)},
+				{tables[2], metricRows(data.Tickets, now)},
+			}
+			if threads {
+				rows, err := threadRows(cmd, client, tickets)
+				if err != nil {
+					fmt.Fprintln(app.Err, red("Error loading threads:"), err)
+					exit(1)
+				}
+				loads = append(loads, warehouseLoad{tables[1], rows})
+			}
+
+			for _, l := range loads {
+				if dryRun {
+					fmt.Fprint(app.Out, loader.Plan(l.table, l.rows))
+					continue
+				}
+				if len(l.rows) == 0 {
+					continue
+				}
+				if err := loader.Load(cmd.Context(), l.table, l.rows); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				fmt.Fprintln(app.Out, green(fmt.Sprintf("✓ %s: %d row(s) upserted", l.table.Name, len(l.rows))))
+			}
+
+			if !sinceLastRun || dryRun {
+				return
+			}
+			if err := saveExportState(statePath, advanceCursor(state, tickets)); err != nil {
+				fmt.Fprintln(app.Err, red("Error saving export state:"), err)
+				exit(1)
+			}
+		},
+	}
+	warehouseCmd.Flags().String("dsn", "", "Database: postgres://user@host/db or bigquery://project/dataset")
+	warehouseCmd.Flags().String("table", "tickets", "Ticket table name; thread and metric tables use it as a prefix")
+	warehouseCmd.Flags().Int("status", 0, "Filter by status (0=all, 1=open, 2=resolved, 3=closed)")
+	warehouseCmd.Flags().Int("limit", osticket.DefaultPageSize, "Tickets fetched per request")
+	warehouseCmd.Flags().Bool("threads", false, "Also load thread entries (one request per ticket)")
+	warehouseCmd.Flags().Bool("dry-run", false, "Print the SQL and commands instead of running them")
+	warehouseCmd.Flags().Bool("since-last-run", false, "Only load tickets created or updated since the previous run")
+	warehouseCmd.Flags().String("state", "", "State file holding the load cursor (default in the state directory)")
+	warehouseCmd.MarkFlagRequired("dsn")
+	cmd.AddCommand(warehouseCmd)
+	cmd.AddCommand(app.exportTicketsCmd())
+
+	return cmd
+}
+
+// warehouseLoad is a batch of rows for one table
+type warehouseLoad struct {
+	table warehouse.Table
+	rows  []warehouse.Row
+}
+
+// warehouseTables returns the ticket, thread and metric tables
+func warehouseTables(name string) []warehouse.Table {
+	return []warehouse.Table{
+		{
+			Name: name,
+			Key:  []string{"ticket_id"},
+			Columns: []warehouse.Column{
+				{Name: "ticket_id", Type: warehouse.Int},
+				{Name: "number", Type: warehouse.Text},
+				{Name: "subject", Type: warehouse.Text},
+				{Name: "status_id", Type: warehouse.Int},
+				{Name: "dept_id", Type: warehouse.Int},
+				{Name: "topic_id", Type: warehouse.Int},
+				{Name: "priority_id", Type: warehouse.Int},
+				{Name: "sla_id", Type: warehouse.Int},
+				{Name: "staff_id", Type: warehouse.Int},
+				{Name: "team_id", Type: warehouse.Int},
+				{Name: "user_id", Type: warehouse.Int},
+				{Name: "source", Type: warehouse.Text},
+				{Name: "isoverdue", Type: warehouse.Int},
+				{Name: "isanswered", Type: warehouse.Int},
+				{Name: "duedate", Type: warehouse.Timestamp},
+				{Name: "closed", Type: warehouse.Timestamp},
+				{Name: "created", Type: warehouse.Timestamp},
+				{Name: "updated", Type: warehouse.Timestamp},
+				{Name: "loaded_at", Type: warehouse.Timestamp},
+			},
+		},
+		{
+			Name: name + "_threads",
+			Key:  []string{"id"},
+			Columns: []warehouse.Column{
+				{Name: "id", Type: warehouse.Int},
+				{Name: "ticket_id", Type: warehouse.Int},
+				{Name: "thread_id", Type: warehouse.Int},
+				{Name: "type", Type: warehouse.Text},
+				{Name: "poster", Type: warehouse.Text},
+				{Name: "staff_id", Type: warehouse.Int},
+				{Name: "user_id", Type: warehouse.Int},
+				{Name: "title", Type: warehouse.Text},
+				{Name: "body", Type: warehouse.Text},
+				{Name: "format", Type: warehouse.Text},
+				{Name: "created", Type: warehouse.Timestamp},
+			},
+		},
+		{
+			Name: name + "_metrics",
+			Key:  []string{"snapshot_date", "status_id", "dept_id"},
+			Columns: []warehouse.Column{
+				{Name: "snapshot_date", Type: warehouse.Date},
+				{Name: "status_id", Type: warehouse.Int},
+				{Name: "dept_id", Type: warehouse.Int},
+				{Name: "tickets", Type: warehouse.Int},
+				{Name: "overdue", Type: warehouse.Int},
+			},
+		},
+	}
+}
+
+// ticketRows converts tickets to rows of the ticket table
+func ticketRows(tickets []map[string]interface{}, now time.Time) []warehouse.Row {
+	rows := make([]warehouse.Row, 0, len(tickets))
+	for _, t := range tickets {
+		row := warehouse.Row{"loaded_at": now.Format(thresholds.TimeLayout)}
+		for _, key := range []string{"ticket_id", "status_id", "dept_id", "topic_id", "priority_id", "sla_id", "staff_id", "team_id", "user_id", "isoverdue", "isanswered"} {
+			row[key] = mapInt(t, key)
+		}
+		for _, key := range []string{"number", "subject", "source"} {
+			row[key] = mapString(t, key)
+		}
+		for _, key := range []string{"duedate", "closed", "created"} {
+			row[key] = apiTime(mapString(t, key))
+		}
+		row["updated"] = apiTime(ticketChanged(t))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// metricRows counts tickets per status and department for today
+func metricRows(tickets []map[string]interface{}, now time.Time) []warehouse.Row {
+	type group struct{ status, dept int }
+	counts := map[group][2]int{}
+	for _, t := range tickets {
+		g := group{mapInt(t, "status_id"), mapInt(t, "dept_id")}
+		c := counts[g]
+		c[0]++
+		if mapInt(t, "isoverdue") == 1 {
+			c[1]++
+		}
+		counts[g] = c
+	}
+
+	day := now.Format("2006-01-02")
+	rows := make([]warehouse.Row, 0, len(counts))
+	for g, c := range counts {
+		rows = append(rows, warehouse.Row{
+			"snapshot_date": day,
+			"status_id":     g.status,
+			"dept_id":       g.dept,
+			"tickets":       c[0],
+			"overdue":       c[1],
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i]["status_id"] != rows[j]["status_id"] {
+			return rows[i]["status_id"].(int) < rows[j]["status_id"].(int)
+		}
+		return rows[i]["dept_id"].(int) < rows[j]["dept_id"].(int)
+	})
+	return rows
+}
+
+// threadRows fetches the thread of every ticket
+func threadRows(cmd *cobra.Command, client osticket.OSTicketAPI, tickets []map[string]interface{}) ([]warehouse.Row, error) {
+	var rows []warehouse.Row
+	for _, t := range tickets {
+		ticketID := mapInt(t, "ticket_id")
+		data, err := client.GetTicketThread(cmd.Context(), strconv.Itoa(ticketID))
+		if err != nil {
+			return nil, fmt.Errorf("ticket %d: %w", ticketID, err)
+		}
+		for _, e := range data.Entries {
+			rows = append(rows, warehouse.Row{
+				"id":        e.ID,
+				"ticket_id": ticketID,
+				"thread_id": e.ThreadID,
+				"type":      e.TypeName(),
+				"poster":    e.Poster,
+				"staff_id":  e.StaffID,
+				"user_id":   e.UserID,
+				"title":     e.Title,
+				"body":      e.Body,
+				"format":    e.Format,
+				"created":   apiTime(e.Created),
+			})
+		}
+	}
+	return rows, nil
+}
+
+// apiTime returns an API timestamp, or "" for unset zero dates
+func apiTime(s string) string {
+	if strings.HasPrefix(s, "0000-00-00") {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/notify"
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET WATCH ====================
+
+// Watch event types
+const (
+	watchNew     = "new"
+	watchUpdated = "updated"
+)
+
+// watchEvent describes a new or updated ticket seen by ticket watch
+type watchEvent struct {
+	Type     string                 `json:"type"`
+	Seen     time.Time              `json:"seen"`
+	TicketID int                    `json:"ticket_id"`
+	Number   string                 `json:"number"`
+	Subject  string                 `json:"subject"`
+	Sender   string                 `json:"sender,omitempty"`
+	StatusID int                    `json:"status_id"`
+	DeptID   int                    `json:"dept_id"`
+	Updated  string                 `json:"updated"`
+	Age      string                 `json:"age,omitempty"`
+	AgeLevel string                 `json:"age_level,omitempty"`
+	Ticket   map[string]interface{} `json:"ticket"`
+
+	level thresholds.Level
+}
+
+func (app *App) watchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Poll for new and updated tickets",
+		Long: `Poll the API and report tickets that were created or updated since the
+previous poll. The first poll only records the current state.
+
+With --output json each event is printed as one JSON object per line.
+--notify shows a desktop notification and --webhook POSTs each event as
+JSON to a URL. Tickets matching the profile's ignore rules (see
+'osticket config ignore') are skipped unless --no-ignore is given.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if app.offline {
+				fmt.Fprintln(app.Err, red("Error:"), "ticket watch cannot run in offline mode")
+				exit(1)
+			}
+			format := app.outputFormat(output.Table)
+			if format == output.NDJSON {
+				// Events are JSON lines either way
+				format = output.JSON
+			}
+			if format != output.Table && format != output.JSON {
+				fmt.Fprintln(app.Err, red("Error:"), "ticket watch supports --output table, json or ndjson")
+				exit(1)
+			}
+
+			interval, _ := cmd.Flags().GetDuration("interval")
+			status, _ := cmd.Flags().GetInt("status")
+			depts, _ := cmd.Flags().GetIntSlice("dept")
+			desktop, _ := cmd.Flags().GetBool("notify")
+			webhook, _ := cmd.Flags().GetString("webhook")
+			noIgnore, _ := cmd.Flags().GetBool("no-ignore")
+			if interval < time.Second {
+				fmt.Fprintln(app.Err, red("Error:"), "--interval must be at least 1s")
+				exit(1)
+			}
+
+			client := app.client(cmd.Context())
+			ctx := cmd.Context()
+
+			ages := app.ageThresholds()
+			var muted *ignore.Matcher
+			if !noIgnore {
+				muted = app.ignoreMatcher()
+			}
+			known := map[int]string{}
+			for baseline := true; ; {
+				data, err := client.GetTicketsByStatus(ctx, status, osticket.Page{})
+				switch {
+				case ctx.Err() != nil:
+					return
+				case err != nil:
+					app.logger().Warn(fmt.Sprintf("%v (retrying in %s)", err, interval))
+				default:
+					events := diffTickets(known, data.Tickets, depts, ages)
+					if baseline {
+						fmt.Fprintf(app.Err, "Watching %d ticket(s), polling every %s. Press Ctrl+C to stop.\n", len(known), interval)
+						baseline = false
+						break
+					}
+					for _, ev := range events {
+						if muted != nil {
+							if _, skip := muted.Match(ignore.Ticket{
+								Number:  ev.Number,
+								Subject: ev.Subject,
+								Sender:  ev.Sender,
+								DeptID:  ev.DeptID,
+							}); skip {
+								continue
+							}
+						}
+						app.printWatchEvent(format, ev)
+						if desktop {
+							title := fmt.Sprintf("osTicket: %s ticket #%s", ev.Type, ev.Number)
+							if err := notify.Send(ctx, title, ev.Subject); err != nil {
+								app.logger().Warn("notification failed: " + err.Error())
+							}
+						}
+						if webhook != "" {
+							if err := postWebhook(ctx, webhook, ev); err != nil {
+								app.logger().Warn("webhook failed: " + err.Error())
+							}
+						}
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+	cmd.Flags().Duration("interval", 30*time.Second, "Time between polls")
+	cmd.Flags().Int("status", 1, "Only watch tickets with this status (0=all, 1=open, 2=resolved, 3=closed)")
+	cmd.Flags().IntSlice("dept", nil, "Only report tickets in these department IDs (repeatable)")
+	cmd.Flags().Bool("notify", false, "Show a desktop notification for each event")
+	cmd.Flags().String("webhook", "", "POST each event as JSON to this URL")
+	cmd.Flags().Bool("no-ignore", false, "Report tickets matching the ignore rules too")
+	return cmd
+}
+
+// diffTickets records the tickets in known (ticket ID to last update) and
+// returns events for tickets that are new or changed since the last call,
+// with each ticket's age classified against ages
+func diffTickets(known map[int]string, tickets []map[string]interface{}, depts []int, ages *thresholds.Thresholds) []watchEvent {
+	var events []watchEvent
+	now := time.Now()
+
+	for _, t := range tickets {
+		ev := watchEvent{
+			Seen:     now,
+			TicketID: mapInt(t, "ticket_id"),
+			Number:   mapString(t, "number"),
+			Subject:  mapString(t, "subject"),
+			Sender:   mapString(t, "email"),
+			StatusID: mapInt(t, "status_id"),
+			DeptID:   mapInt(t, "dept_id"),
+			Updated:  mapString(t, "updated"),
+			Ticket:   t,
+		}
+		if ev.Updated == "" {
+			ev.Updated = mapString(t, "lastupdate")
+		}
+		if ev.Number == "" {
+			ev.Number = strconv.Itoa(ev.TicketID)
+		}
+		if age, level, ok := ticketAge(ages, t, now); ok {
+			ev.Age = thresholds.Format(age)
+			ev.AgeLevel = level.String()
+			ev.level = level
+		}
+		if len(depts) > 0 && !containsInt(depts, ev.DeptID) {
+			continue
+		}
+
+		previous, seen := known[ev.TicketID]
+		known[ev.TicketID] = ev.Updated
+		switch {
+		case !seen:
+			ev.Type = watchNew
+		case previous != ev.Updated:
+			ev.Type = watchUpdated
+		default:
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// printWatchEvent prints one event as a line of text or JSON
+func (app *App) printWatchEvent(format string, ev watchEvent) {
+	if format == output.JSON {
+		json.NewEncoder(app.Out).Encode(ev)
+		return
+	}
+
+	label := green("NEW    ")
+	if ev.Type == watchUpdated {
+		label = yellow("UPDATED")
+	}
+	age := ""
+	if ev.Age != "" {
+		age = " " + colorAge(ev.level, "age "+ev.Age)
+	}
+	fmt.Fprintf(app.Out, "%s %s #%s %s %s%s\n",
+		ev.Seen.Format("15:04:05"), label, ev.Number, truncate(ev.Subject, 60),
+		cyan(fmt.Sprintf("(dept %d, %s)", ev.DeptID, statusName(ev.StatusID))), age)
+}
+
+// postWebhook sends an event to a webhook URL
+func postWebhook(ctx context.Context, url string, ev watchEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return postWebhookBody(ctx, url, body)
+}
+
+// postWebhookBody POSTs a JSON body to a webhook URL. Errors name only the
+// host, as webhook URLs often embed a secret token.
+func postWebhookBody(ctx context.Context, webhook string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("%s: %w", req.URL.Host, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
+
+// mapString returns a ticket field as a string
+func mapString(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// mapInt returns a ticket field as an int; the API sends IDs as numbers or
+// numeric strings
+func mapInt(m map[string]interface{}, key string) int {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/validate"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ==================== TICKET WIZARD ====================
+
+// priorityOptions are the priorities osTicket ships with, offered when the
+// server cannot list its own
+var priorityOptions = []promptOption{
+	{ID: osticket.PriorityLow, Label: "Low"},
+	{ID: osticket.PriorityNormal, Label: "Normal"},
+	{ID: osticket.PriorityHigh, Label: "High"},
+	{ID: osticket.PriorityEmergency, Label: "Emergency"},
+}
+
+// ticketWizard walks through ticket fields interactively and returns them
+// as a ticket file would give them. It returns nil when the user declines
+// the final confirmation.
+func (app *App) ticketWizard(ctx context.Context, client osticket.OSTicketAPI) (*ticketFile, error) {
+	fmt.Fprintln(app.promptOut(), cyan("\nNew ticket\n"))
+
+	// Requester
+	user, err := app.promptUser(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Department and topic from live lists
+	depts, err := client.GetDepartments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load departments: %w", err)
+	}
+	var deptOptions []promptOption
+	deptNames := map[int]string{}
+	for _, d := range depts.Departments {
+		deptOptions = append(deptOptions, promptOption{ID: d.ID, Label: d.Name})
+		deptNames[d.ID] = d.Name
+	}
+	fmt.Fprintln(app.promptOut())
+	deptID, err := app.promptSelect("Department", deptOptions, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := client.GetTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load help topics: %w", err)
+	}
+	var topicOptions []promptOption
+	topicNames := map[int]string{}
+	for _, t := range topics.Topics {
+		topicOptions = append(topicOptions, promptOption{ID: t.TopicID, Label: t.Topic})
+		topicNames[t.TopicID] = t.Topic
+	}
+	fmt.Fprintln(app.promptOut())
+	topicID, err := app.promptSelect("Help topic", topicOptions, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	options := priorityOptions
+	if priorities, err := client.GetPriorities(ctx); err == nil && len(priorities.Priorities) > 0 {
+		options = nil
+		for _, p := range priorities.Priorities {
+			options = append(options, promptOption{ID: p.ID, Label: p.Name})
+		}
+	}
+	priorityNames := map[int]string{}
+	for _, o := range options {
+		priorityNames[o.ID] = o.Label
+	}
+	fmt.Fprintln(app.promptOut())
+	priorityID, err := app.promptSelect("Priority", options, osticket.PriorityNormal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Title and body
+	fmt.Fprintln(app.promptOut())
+	title, err := app.promptRequired("Title")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := app.promptBody()
+	if err != nil {
+		return nil, err
+	}
+
+	// Summary
+	fmt.Fprintln(app.promptOut(), cyan("\nSummary:"))
+	fmt.Fprintf(app.promptOut(), "  User:       %s (ID %d)\n", user.Name, user.UserID)
+	fmt.Fprintf(app.promptOut(), "  Department: %s\n", deptNames[deptID])
+	fmt.Fprintf(app.promptOut(), "  Topic:      %s\n", topicNames[topicID])
+	fmt.Fprintf(app.promptOut(), "  Priority:   %s\n", priorityNames[priorityID])
+	fmt.Fprintf(app.promptOut(), "  Title:      %s\n", title)
+	fmt.Fprintf(app.promptOut(), "  Body:       %s\n\n", truncate(strings.ReplaceAll(body, "\n", " "), 60))
+
+	ok, err := app.promptConfirm("Create this ticket?", true)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	// The SLA and status are left to the flag defaults, so that the
+	// entitlement hook can pick the SLA
+	return &ticketFile{
+		Title:      &title,
+		Subject:    &body,
+		UserID:     &user.UserID,
+		PriorityID: &priorityID,
+		DeptID:     &deptID,
+		TopicID:    &topicID,
+	}, nil
+}
+
+// promptUser asks for an email until a matching user is found
+func (app *App) promptUser(ctx context.Context, client osticket.OSTicketAPI) (*osticket.User, error) {
+	for {
+		input, err := app.promptRequired("User email")
+		if err != nil {
+			return nil, err
+		}
+		email, err := validate.Email("email", input)
+		if err != nil {
+			fmt.Fprintln(app.promptOut(), yellow("  "+err.Error()))
+			continue
+		}
+
+		data, err := client.GetUserByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("user lookup failed: %w", err)
+		}
+		if len(data.Users) == 0 {
+			fmt.Fprintln(app.promptOut(), yellow("  No user found for "+email+", try again"))
+			continue
+		}
+
+		user := data.Users[0]
+		fmt.Fprintf(app.promptOut(), "  Found: %s (ID %d)\n", green(user.Name), user.UserID)
+		return &user, nil
+	}
+}
+
+// promptBody composes the ticket body in the editor, falling back to a
+// single line prompt if no editor can be started
+func (app *App) promptBody() (string, error) {
+	fmt.Fprintln(app.promptOut(), cyan("Body:")+" opening editor...")
+	body, err := editText("", ".txt")
+	if err != nil {
+		fmt.Fprintln(app.promptOut(), yellow("  "+err.Error()))
+		return app.promptRequired("Body")
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return app.promptRequired("Body")
+	}
+	return body, nil
+}
+
+// createWithWizard creates a ticket from the answers of ticketWizard. The
+// answers fill in the flags of ticket create, as a ticket file does, and
+// go through createTicket so entitlement checks and validation apply.
+func (app *App) createWithWizard(cmd *cobra.Command) {
+	client := app.client(cmd.Context())
+	answers, err := app.ticketWizard(cmd.Context(), client)
+	if err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	if answers == nil {
+		fmt.Fprintln(app.Out, yellow("Ticket creation cancelled"))
+		return
+	}
+
+	// ticket new has no create flags of its own
+	create := &cobra.Command{Use: "create"}
+	addCreateFlags(create)
+	create.SetContext(cmd.Context())
+	if err := answers.applyTo(create); err != nil {
+		fmt.Fprintln(app.Err, red("Error:"), err)
+		exit(exitCode(err))
+	}
+	app.createTicket(create)
+}
+
+// anyFlagChanged reports whether any of cmd's own flags was given
+func anyFlagChanged(cmd *cobra.Command) bool {
+	changed := false
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		changed = changed || f.Changed
+	})
+	return changed
+}
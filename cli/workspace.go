@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/osticket-cli-go/internal/output"
+	"github.com/osticket-cli-go/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// ==================== TICKET WORKSPACE ====================
+
+// workspacePush describes the files attached from a workspace's outbox
+type workspacePush struct {
+	TicketID int      `json:"ticket_id"`
+	As       string   `json:"as"`
+	Files    []string `json:"files"`
+	Pushed   bool     `json:"pushed"`
+}
+
+// workspaceResult describes a workspace created or refreshed
+type workspaceResult struct {
+	Dir           string `json:"dir"`
+	TicketID      int    `json:"ticket_id"`
+	Number        string `json:"number"`
+	ThreadEntries int    `json:"thread_entries"`
+	NotesCreated  bool   `json:"notes_created"`
+}
+
+func (app *App) workspaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace <id>",
+		Short: "Create a local folder with a ticket's details for offline work",
+		Long: `Create a folder named ticket-<number> holding everything needed to
+investigate a ticket offline:
+
+  ticket.json   the ticket's metadata
+  thread.md     the conversation thread as a Markdown transcript
+  notes.md      a notes file for the investigation
+  attachments/  for files belonging to the ticket
+  outbox/       files to upload with 'osticket ticket workspace push'
+
+Running the command again refreshes ticket.json and thread.md; notes.md and
+the files in attachments/ and outbox/ are never touched. The osTicket API
+plugin does not serve attachment files, so save the ones you need to
+attachments/ from the staff panel.`,
+		Example: `  osticket ticket workspace 12345
+  osticket ticket workspace 100042 --dir ~/cases`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parent, _ := cmd.Flags().GetString("dir")
+			ctx := cmd.Context()
+			client := app.client(ctx)
+
+			data, err := client.GetTicket(ctx, args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitCode(err))
+			}
+			if len(data.Tickets) == 0 {
+				fmt.Fprintln(app.Err, red("Error:"), fmt.Sprintf("ticket %s not found", args[0]))
+				exit(exitNotFound)
+			}
+			t := data.Tickets[0]
+			result := workspaceResult{TicketID: mapInt(t, "ticket_id"), Number: mapString(t, "number")}
+			if result.Number == "" {
+				result.Number = strconv.Itoa(result.TicketID)
+			}
+			subject := mapString(t, "subject")
+
+			thread, err := client.GetTicketThread(ctx, strconv.Itoa(result.TicketID))
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error loading thread:"), err)
+				exit(exitCode(err))
+			}
+			result.ThreadEntries = len(thread.Entries)
+
+			ws := workspace.New(parent, result.Number)
+			result.Dir = ws.Dir
+			err = ws.Init()
+			if err == nil {
+				err = ws.WriteTicket(t)
+			}
+			if err == nil {
+				err = ws.WriteThread(result.Number, subject, thread.Entries)
+			}
+			if err == nil {
+				result.NotesCreated, err = ws.WriteNotes(result.Number, subject)
+			}
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: result,
+				IDs:   []string{result.Dir},
+				Table: func(w io.Writer) {
+					fmt.Fprintln(w, green("✓ Workspace ready: "+result.Dir))
+					notes := "kept"
+					if result.NotesCreated {
+						notes = "created"
+					}
+					fmt.Fprintf(w, "  %-14s metadata\n", workspace.TicketFile)
+					fmt.Fprintf(w, "  %-14s %d thread entries\n", workspace.ThreadFile, result.ThreadEntries)
+					fmt.Fprintf(w, "  %-14s %s\n", workspace.NotesFile, notes)
+					fmt.Fprintf(w, "  %s%c\n", workspace.AttachmentsDir, filepath.Separator)
+				},
+			})
+		},
+	}
+	cmd.Flags().String("dir", ".", "Folder to create the workspace in")
+
+	// ticket workspace push
+	pushCmd := &cobra.Command{
+		Use:   "push <id>",
+		Short: "Attach the files in a workspace's outbox/ to the ticket",
+		Long: `Upload the files placed in the workspace's outbox/ folder as attachments
+of an internal note on the ticket, or of a reply to the user with --reply.
+Uploaded files move to attachments/, so each file is sent once. Files
+larger than the attachment size limit are refused before anything is sent.
+
+The workspace is found under --dir by ticket number or ID.`,
+		Example: `  osticket ticket workspace push 100042 --staff-id 1
+  osticket ticket workspace push 100042 --staff-id 1 --reply --body "Here is the patch we applied."
+  osticket ticket workspace push 100042 --dry-run`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parent, _ := cmd.Flags().GetString("dir")
+			reply, _ := cmd.Flags().GetBool("reply")
+			title, _ := cmd.Flags().GetString("title")
+			body, _ := cmd.Flags().GetString("body")
+			staffID, _ := cmd.Flags().GetInt("staff-id")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			ws, err := workspace.Find(parent, args[0])
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(exitNotFound)
+			}
+			ticketID, err := ws.TicketID()
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			paths, err := ws.Outbox()
+			if err != nil {
+				fmt.Fprintln(app.Err, red("Error:"), err)
+				exit(1)
+			}
+			if len(paths) == 0 {
+				fmt.Fprintln(app.Err, yellow("No files in "+filepath.Join(ws.Dir, workspace.OutboxDir)))
+				return
+			}
+			names := make([]string, len(paths))
+			for i, path := range paths {
+				names[i] = filepath.Base(path)
+			}
+			attachments := app.loadAttachments(paths)
+			if body == "" {
+				body = "Attached: " + strings.Join(names, ", ")
+			}
+			kind, described := "note", "an internal note"
+			if reply {
+				kind, described = "reply", "a reply"
+			}
+
+			result := workspacePush{TicketID: ticketID, As: kind, Files: names}
+			if !dryRun {
+				if staffID == 0 {
+					fmt.Fprintln(app.Err, red("Error:"), "--staff-id is required")
+					exit(exitValidation)
+				}
+				client := app.client(cmd.Context())
+				if reply {
+					err = client.ReplyToTicket(cmd.Context(), ticketID, body, staffID, attachments...)
+				} else {
+					err = client.AddInternalNote(cmd.Context(), ticketID, title, body, staffID, attachments...)
+				}
+				// A queued request carries the files, so they are done with
+				// either way
+				if !app.queued(err) && err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(exitCode(err))
+				}
+				if err := ws.Archive(paths); err != nil {
+					fmt.Fprintln(app.Err, red("Error:"), err)
+					exit(1)
+				}
+				result.Pushed = true
+			}
+
+			app.render(output.Table, &output.Result{
+				Value: result,
+				IDs:   names,
+				Table: func(w io.Writer) {
+					if !result.Pushed {
+						fmt.Fprintf(w, "Would attach %d file(s) to ticket %d as %s:\n", len(names), ticketID, described)
+					} else {
+						fmt.Fprintln(w, green(fmt.Sprintf("✓ %d file(s) attached to ticket %d as %s", len(names), ticketID, described)))
+					}
+					for _, name := range names {
+						fmt.Fprintln(w, "  "+name)
+					}
+				},
+			})
+		},
+	}
+	pushCmd.Flags().String("dir", ".", "Folder holding the workspace")
+	pushCmd.Flags().Bool("reply", false, "Attach the files to a reply to the user instead of an internal note")
+	pushCmd.Flags().String("title", "Workspace files", "Title of the internal note")
+	pushCmd.Flags().String("body", "", "Body of the note or reply (default lists the files)")
+	pushCmd.Flags().Int("staff-id", 0, "Staff ID (required unless --dry-run)")
+	pushCmd.Flags().Bool("dry-run", false, "List the files that would be attached")
+	cmd.AddCommand(pushCmd)
+	return cmd
+}
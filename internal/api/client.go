@@ -1,801 +0,0 @@
-package api
-
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"time"
-)
-
-// Client represents the osTicket API client
-type Client struct {
-	BaseURL    string
-	APIKey     string
-	HTTPClient *http.Client
-}
-
-// NewClient creates a new osTicket API client
-func NewClient(baseURL, apiKey string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
-}
-
-// Request represents the API request body
-type Request struct {
-	Query      string                 `json:"query"`
-	Condition  string                 `json:"condition"`
-	Sort       string                 `json:"sort,omitempty"`
-	Parameters map[string]interface{} `json:"parameters,omitempty"`
-}
-
-// Response represents the API response
-type Response struct {
-	Status  string          `json:"status"`
-	Message string          `json:"message,omitempty"`
-	Time    float64         `json:"time,omitempty"`
-	Data    json.RawMessage `json:"data,omitempty"`
-}
-
-// TicketData represents ticket response data
-type TicketData struct {
-	Total   int        `json:"total"`
-	Tickets [][]Ticket `json:"tickets"`
-}
-
-// Ticket represents a single ticket
-type Ticket struct {
-	TicketID    int    `json:"ticket_id"`
-	TicketPID   int    `json:"ticket_pid"`
-	Number      string `json:"number"`
-	UserID      int    `json:"user_id"`
-	UserEmailID int    `json:"user_email_id"`
-	StatusID    int    `json:"status_id"`
-	DeptID      int    `json:"dept_id"`
-	SLAID       int    `json:"sla_id"`
-	TopicID     int    `json:"topic_id"`
-	StaffID     int    `json:"staff_id"`
-	TeamID      int    `json:"team_id"`
-	EmailID     int    `json:"email_id"`
-	LockID      int    `json:"lock_id"`
-	Flags       int    `json:"flags"`
-	Sort        int    `json:"sort"`
-	Subject     string `json:"subject"`
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	IPAddress   string `json:"ip_address"`
-	Source      string `json:"source"`
-	SourceExtra string `json:"source_extra"`
-	IsOverdue   int    `json:"isoverdue"`
-	IsAnswered  int    `json:"isanswered"`
-	DueDate     string `json:"duedate"`
-	EstDueDate  string `json:"est_duedate"`
-	Reopened    string `json:"reopened"`
-	Closed      string `json:"closed"`
-	LastUpdate  string `json:"lastupdate"`
-	Created     string `json:"created"`
-	Updated     string `json:"updated"`
-}
-
-// UserData represents user response data
-type UserData struct {
-	Total int    `json:"total"`
-	Users []User `json:"users"`
-}
-
-// User represents a single user
-type User struct {
-	UserID  int    `json:"-"` // Parsed manually due to API returning string or int
-	Name    string `json:"name"`
-	Created string `json:"created"`
-}
-
-// UnmarshalJSON custom unmarshaler for User to handle user_id as string or int
-func (u *User) UnmarshalJSON(data []byte) error {
-	type Alias User
-	aux := &struct {
-		UserID interface{} `json:"user_id"`
-		*Alias
-	}{
-		Alias: (*Alias)(u),
-	}
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return err
-	}
-	
-	// Handle user_id as string or number
-	switch v := aux.UserID.(type) {
-	case float64:
-		u.UserID = int(v)
-	case string:
-		fmt.Sscanf(v, "%d", &u.UserID)
-	case int:
-		u.UserID = v
-	}
-	return nil
-}
-
-// DepartmentData represents department response data
-type DepartmentData struct {
-	Total       int          `json:"total"`
-	Departments []Department `json:"departments"`
-}
-
-// Department represents a single department
-type Department struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-}
-
-// TopicData represents topic response data
-type TopicData struct {
-	Total  int     `json:"total"`
-	Topics []Topic `json:"topics"`
-}
-
-// Topic represents a single topic
-type Topic struct {
-	TopicID int    `json:"topic_id"`
-	Topic   string `json:"topic"`
-}
-
-// SLAData represents SLA response data
-type SLAData struct {
-	Total int   `json:"total"`
-	SLA   []SLA `json:"sla"`
-}
-
-// SLA represents a single SLA plan
-type SLA struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	GracePeriod int    `json:"grace_period"`
-}
-
-// doRequest performs the API request (POST)
-func (c *Client) doRequest(req Request) (*Response, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest("POST", c.BaseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", c.APIKey)
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var apiResp Response
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if apiResp.Status == "Error" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Message)
-	}
-
-	return &apiResp, nil
-}
-
-// doGetRequest performs a GET API request with JSON body
-func (c *Client) doGetRequest(req Request) (*Response, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest("GET", c.BaseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", c.APIKey)
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var apiResp Response
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if apiResp.Status == "Error" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Message)
-	}
-
-	return &apiResp, nil
-}
-
-// doGetRequestRaw performs a GET API request and returns raw response bytes
-func (c *Client) doGetRequestRaw(req Request) ([]byte, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest("GET", c.BaseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", c.APIKey)
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	return io.ReadAll(resp.Body)
-}
-
-// doPostRequestRaw performs a POST API request and returns raw response bytes
-func (c *Client) doPostRequestRaw(req Request) ([]byte, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest("POST", c.BaseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", c.APIKey)
-
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	return io.ReadAll(resp.Body)
-}
-
-// SimpleTicketResponse is a flat ticket response for JSON output
-type SimpleTicketResponse struct {
-	Total   int                      `json:"total"`
-	Tickets []map[string]interface{} `json:"tickets"`
-}
-
-// GetTicket gets a specific ticket by ID or number (uses GET)
-// Returns tickets as a flat array of individual ticket objects
-func (c *Client) GetTicket(id string) (*SimpleTicketResponse, error) {
-	raw, err := c.doGetRequestRaw(Request{
-		Query:      "ticket",
-		Condition:  "specific",
-		Parameters: map[string]interface{}{"id": id},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse the raw response to extract tickets dynamically
-	var rawResp map[string]interface{}
-	if err := json.Unmarshal(raw, &rawResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Check for error status
-	if status, ok := rawResp["status"].(string); ok && status == "Error" {
-		msg := "unknown error"
-		if m, ok := rawResp["message"].(string); ok {
-			msg = m
-		}
-		return nil, fmt.Errorf("API error: %s", msg)
-	}
-
-	// Extract data field
-	data, ok := rawResp["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid data field in response")
-	}
-
-	// Get total
-	total := 0
-	if t, ok := data["total"].(float64); ok {
-		total = int(t)
-	}
-
-	// Extract tickets - handle various formats
-	var tickets []map[string]interface{}
-
-	if ticketsRaw, ok := data["tickets"]; ok {
-		switch t := ticketsRaw.(type) {
-		case []interface{}:
-			// Could be [][]ticket or []ticket
-			for _, item := range t {
-				switch v := item.(type) {
-				case []interface{}:
-					// Nested array - flatten it
-					for _, ticket := range v {
-						if ticketMap, ok := ticket.(map[string]interface{}); ok {
-							tickets = append(tickets, ticketMap)
-						}
-					}
-				case map[string]interface{}:
-					// Direct ticket object
-					tickets = append(tickets, v)
-				}
-			}
-		case map[string]interface{}:
-			// Single ticket object
-			tickets = append(tickets, t)
-		}
-	}
-
-	return &SimpleTicketResponse{
-		Total:   total,
-		Tickets: tickets,
-	}, nil
-}
-
-// parseTicketsResponse parses raw API response into SimpleTicketResponse
-func parseTicketsResponse(raw []byte) (*SimpleTicketResponse, error) {
-	var rawResp map[string]interface{}
-	if err := json.Unmarshal(raw, &rawResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Check for error status
-	if status, ok := rawResp["status"].(string); ok && status == "Error" {
-		msg := "unknown error"
-		if m, ok := rawResp["message"].(string); ok {
-			msg = m
-		}
-		return nil, fmt.Errorf("API error: %s", msg)
-	}
-
-	// Extract data field
-	data, ok := rawResp["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid data field in response")
-	}
-
-	// Get total
-	total := 0
-	if t, ok := data["total"].(float64); ok {
-		total = int(t)
-	}
-
-	// Extract tickets - handle various formats
-	var tickets []map[string]interface{}
-
-	if ticketsRaw, ok := data["tickets"]; ok {
-		switch t := ticketsRaw.(type) {
-		case []interface{}:
-			for _, item := range t {
-				switch v := item.(type) {
-				case []interface{}:
-					for _, ticket := range v {
-						if ticketMap, ok := ticket.(map[string]interface{}); ok {
-							tickets = append(tickets, ticketMap)
-						}
-					}
-				case map[string]interface{}:
-					tickets = append(tickets, v)
-				}
-			}
-		case map[string]interface{}:
-			tickets = append(tickets, t)
-		}
-	}
-
-	return &SimpleTicketResponse{
-		Total:   total,
-		Tickets: tickets,
-	}, nil
-}
-
-// GetTicketRaw gets a specific ticket and returns raw API response
-func (c *Client) GetTicketRaw(id string) ([]byte, error) {
-	return c.doGetRequestRaw(Request{
-		Query:      "ticket",
-		Condition:  "specific",
-		Parameters: map[string]interface{}{"id": id},
-	})
-}
-
-// GetTicketsByStatus gets tickets by status (uses GET)
-func (c *Client) GetTicketsByStatus(status int) (*SimpleTicketResponse, error) {
-	raw, err := c.doGetRequestRaw(Request{
-		Query:      "ticket",
-		Condition:  "all",
-		Sort:       "status",
-		Parameters: map[string]interface{}{"status": status},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return parseTicketsResponse(raw)
-}
-
-// GetTicketsByDateRange gets tickets by creation date range (uses GET)
-func (c *Client) GetTicketsByDateRange(startDate, endDate string) (*SimpleTicketResponse, error) {
-	raw, err := c.doGetRequestRaw(Request{
-		Query:     "ticket",
-		Condition: "all",
-		Sort:      "creationDate",
-		Parameters: map[string]interface{}{
-			"start_date": startDate,
-			"end_date":   endDate,
-		},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return parseTicketsResponse(raw)
-}
-
-// GetTicketsByStatusRaw gets tickets by status and returns raw response (GET)
-func (c *Client) GetTicketsByStatusRaw(status int) ([]byte, error) {
-	return c.doGetRequestRaw(Request{
-		Query:      "ticket",
-		Condition:  "all",
-		Sort:       "status",
-		Parameters: map[string]interface{}{"status": status},
-	})
-}
-
-// GetTicketsByDateRangeRaw gets tickets by date range and returns raw response
-func (c *Client) GetTicketsByDateRangeRaw(startDate, endDate string) ([]byte, error) {
-	return c.doGetRequestRaw(Request{
-		Query:     "ticket",
-		Condition: "all",
-		Sort:      "creationDate",
-		Parameters: map[string]interface{}{
-			"start_date": startDate,
-			"end_date":   endDate,
-		},
-	})
-}
-
-// GetUserByEmailRaw gets user by email and returns raw response
-func (c *Client) GetUserByEmailRaw(email string) ([]byte, error) {
-	return c.doGetRequestRaw(Request{
-		Query:      "user",
-		Condition:  "specific",
-		Sort:       "email",
-		Parameters: map[string]interface{}{"email": email},
-	})
-}
-
-// SearchTicketsByTerm searches tickets by term (subject/body) within a date range
-func (c *Client) SearchTicketsByTerm(term, startDate, endDate string, status int) (*SimpleTicketResponse, error) {
-	raw, err := c.SearchTicketsByTermRaw(term, startDate, endDate, status)
-	if err != nil {
-		return nil, err
-	}
-	return parseTicketsResponse(raw)
-}
-
-// SearchTicketsByTermRaw searches tickets by term and returns raw response
-func (c *Client) SearchTicketsByTermRaw(term, startDate, endDate string, status int) ([]byte, error) {
-	params := map[string]interface{}{
-		"term":       term,
-		"start_date": startDate,
-		"end_date":   endDate,
-	}
-	if status > 0 {
-		params["status"] = status
-	}
-	return c.doGetRequestRaw(Request{
-		Query:      "ticket",
-		Condition:  "all",
-		Sort:       "search",
-		Parameters: params,
-	})
-}
-
-// CreateTicketParams contains parameters for creating a ticket
-type CreateTicketParams struct {
-	Title      string
-	Subject    string
-	UserID     int
-	PriorityID int
-	StatusID   int
-	DeptID     int
-	SLAID      int
-	TopicID    int
-}
-
-// CreateTicket creates a new ticket
-func (c *Client) CreateTicket(params CreateTicketParams) (int, error) {
-	resp, err := c.doRequest(Request{
-		Query:     "ticket",
-		Condition: "add",
-		Parameters: map[string]interface{}{
-			"title":       params.Title,
-			"subject":     params.Subject,
-			"user_id":     params.UserID,
-			"priority_id": params.PriorityID,
-			"status_id":   params.StatusID,
-			"dept_id":     params.DeptID,
-			"sla_id":      params.SLAID,
-			"topic_id":    params.TopicID,
-		},
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	// API returns ticket ID as string or int
-	var ticketID int
-	if err := json.Unmarshal(resp.Data, &ticketID); err != nil {
-		// Try as string
-		var ticketIDStr string
-		if err2 := json.Unmarshal(resp.Data, &ticketIDStr); err2 != nil {
-			return 0, fmt.Errorf("failed to parse ticket ID: %w", err)
-		}
-		fmt.Sscanf(ticketIDStr, "%d", &ticketID)
-	}
-
-	return ticketID, nil
-}
-
-// ReplyToTicket adds a reply to a ticket
-func (c *Client) ReplyToTicket(ticketID int, body string, staffID int) error {
-	_, err := c.doRequest(Request{
-		Query:     "ticket",
-		Condition: "reply",
-		Parameters: map[string]interface{}{
-			"ticket_id": ticketID,
-			"body":      body,
-			"staff_id":  staffID,
-		},
-	})
-	return err
-}
-
-// CloseTicketParams contains parameters for closing a ticket
-type CloseTicketParams struct {
-	TicketID int
-	Body     string
-	StaffID  int
-	StatusID int
-	TeamID   int
-	DeptID   int
-	TopicID  int
-	Username string
-}
-
-// CloseTicket closes a ticket
-func (c *Client) CloseTicket(params CloseTicketParams) error {
-	_, err := c.doRequest(Request{
-		Query:     "ticket",
-		Condition: "close",
-		Parameters: map[string]interface{}{
-			"ticket_id": params.TicketID,
-			"body":      params.Body,
-			"staff_id":  params.StaffID,
-			"status_id": params.StatusID,
-			"team_id":   params.TeamID,
-			"dept_id":   params.DeptID,
-			"topic_id":  params.TopicID,
-			"username":  params.Username,
-		},
-	})
-	return err
-}
-
-// GetUserByID gets a user by ID
-func (c *Client) GetUserByID(id string) (*UserData, error) {
-	resp, err := c.doRequest(Request{
-		Query:      "user",
-		Condition:  "specific",
-		Sort:       "id",
-		Parameters: map[string]interface{}{"id": id},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var data UserData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse user data: %w", err)
-	}
-
-	return &data, nil
-}
-
-// GetUserByEmail gets a user by email (uses GET)
-func (c *Client) GetUserByEmail(email string) (*UserData, error) {
-	resp, err := c.doGetRequest(Request{
-		Query:      "user",
-		Condition:  "specific",
-		Sort:       "email",
-		Parameters: map[string]interface{}{"email": email},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var data UserData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse user data: %w", err)
-	}
-
-	return &data, nil
-}
-
-// CreateUserParams contains parameters for creating a user
-type CreateUserParams struct {
-	Name           string
-	Email          string
-	Password       string
-	Phone          string
-	Timezone       string
-	OrgID          int
-	DefaultEmailID int
-	Status         int
-}
-
-// CreateUser creates a new user
-func (c *Client) CreateUser(params CreateUserParams) (int, error) {
-	resp, err := c.doRequest(Request{
-		Query:     "user",
-		Condition: "add",
-		Parameters: map[string]interface{}{
-			"name":             params.Name,
-			"email":            params.Email,
-			"password":         params.Password,
-			"phone":            params.Phone,
-			"timezone":         params.Timezone,
-			"org_id":           params.OrgID,
-			"default_email_id": params.DefaultEmailID,
-			"status":           params.Status,
-		},
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	// API returns user ID as string or int
-	var userID int
-	if err := json.Unmarshal(resp.Data, &userID); err != nil {
-		// Try as string
-		var userIDStr string
-		if err2 := json.Unmarshal(resp.Data, &userIDStr); err2 != nil {
-			return 0, fmt.Errorf("failed to parse user ID: %w", err)
-		}
-		fmt.Sscanf(userIDStr, "%d", &userID)
-	}
-
-	return userID, nil
-}
-
-// GetDepartments gets all departments
-func (c *Client) GetDepartments() (*DepartmentData, error) {
-	resp, err := c.doRequest(Request{
-		Query:      "department",
-		Condition:  "all",
-		Sort:       "all",
-		Parameters: map[string]interface{}{},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var data DepartmentData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse department data: %w", err)
-	}
-
-	return &data, nil
-}
-
-// GetTopics gets all help topics
-func (c *Client) GetTopics() (*TopicData, error) {
-	resp, err := c.doRequest(Request{
-		Query:      "topics",
-		Condition:  "all",
-		Sort:       "all",
-		Parameters: map[string]interface{}{},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var data TopicData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse topic data: %w", err)
-	}
-
-	return &data, nil
-}
-
-// GetSLAs gets all SLA plans
-func (c *Client) GetSLAs() (*SLAData, error) {
-	resp, err := c.doRequest(Request{
-		Query:      "sla",
-		Condition:  "all",
-		Sort:       "all",
-		Parameters: map[string]interface{}{},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var data SLAData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse SLA data: %w", err)
-	}
-
-	return &data, nil
-}
-
-// SearchTicketsByEmail searches tickets by user email (uses GET)
-func (c *Client) SearchTicketsByEmail(email string) (*SimpleTicketResponse, *User, error) {
-	// First get the user
-	userData, err := c.GetUserByEmail(email)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if len(userData.Users) == 0 {
-		return &SimpleTicketResponse{Total: 0, Tickets: []map[string]interface{}{}}, nil, nil
-	}
-
-	user := userData.Users[0]
-
-	// Get all tickets using date range (wider compatibility)
-	allTickets, err := c.GetTicketsByDateRange("2000-01-01", "2099-12-31")
-	if err != nil {
-		return nil, &user, err
-	}
-
-	// Filter by user ID
-	var filtered []map[string]interface{}
-	for _, ticket := range allTickets.Tickets {
-		// Check user_id field (could be float64 or string from JSON)
-		switch uid := ticket["user_id"].(type) {
-		case float64:
-			if int(uid) == user.UserID {
-				filtered = append(filtered, ticket)
-			}
-		case string:
-			var uidInt int
-			fmt.Sscanf(uid, "%d", &uidInt)
-			if uidInt == user.UserID {
-				filtered = append(filtered, ticket)
-			}
-		}
-	}
-
-	return &SimpleTicketResponse{
-		Total:   len(filtered),
-		Tickets: filtered,
-	}, &user, nil
-}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps data on disk for a limited time. Entries are files whose
+// modification time marks when they were stored.
+type Cache struct {
+	Dir string
+	// TTL is how long entries stay fresh; zero disables the cache
+	TTL time.Duration
+	// Refresh ignores stored entries but still replaces them
+	Refresh bool
+}
+
+// New creates a cache in dir
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get returns a fresh entry. Missing, expired and unreadable entries are
+// all misses.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	if c.TTL <= 0 || c.Refresh {
+		return nil, false
+	}
+	info, err := os.Stat(c.path(key))
+	if err != nil || time.Since(info.ModTime()) > c.TTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores an entry, replacing it atomically so concurrent readers never
+// see a partial file
+func (c *Cache) Put(key string, data []byte) error {
+	if c.TTL <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(c.Dir, "."+key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Clear removes every entry
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package checkpoint records how far a bulk job got, so a job stopped by
+// its time budget can resume where it left off.
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotFound is returned by Load when no checkpoint exists for a job
+var ErrNotFound = errors.New("no checkpoint for this job")
+
+// Checkpoint is the saved progress of a bulk job
+type Checkpoint struct {
+	Job     string    `json:"job"`
+	Offset  int       `json:"offset"`
+	Total   int       `json:"total,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Store keeps checkpoints as files in a directory
+type Store struct {
+	Dir string
+}
+
+// NewStore creates a checkpoint store in dir
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Key derives a job key from the values that identify a job, e.g. the
+// command name and its filter flags
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func (s *Store) path(job string) string {
+	return filepath.Join(s.Dir, job+".json")
+}
+
+// Save records the progress of a job, replacing any earlier checkpoint
+func (s *Store) Save(cp *Checkpoint) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	cp.Created = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write then rename so a crash never leaves a truncated checkpoint
+	tmp := s.path(cp.Job) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, s.path(cp.Job))
+}
+
+// Load returns the checkpoint of a job
+func (s *Store) Load(job string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(job))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Remove deletes the checkpoint of a finished job
+func (s *Store) Remove(job string) error {
+	err := os.Remove(s.path(job))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -1,139 +1,784 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/osticket-cli-go/internal/credentials"
+	"github.com/osticket-cli-go/internal/entitlement"
+	"github.com/osticket-cli-go/internal/ignore"
+	"github.com/osticket-cli-go/internal/keyring"
+	"github.com/osticket-cli-go/internal/thresholds"
+	"github.com/osticket-cli-go/pkg/osticket"
 	"github.com/spf13/viper"
 )
 
 var cfg *viper.Viper
 
+// configDirOverride is set by SetConfigDir
+var configDirOverride string
+
+// activeProfile is the profile selected with --profile
+var activeProfile string
+
+// profileNamePattern restricts profile names to characters that are safe
+// as viper key segments
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// sizePattern matches sizes accepted by viper's GetSizeInBytes
+var sizePattern = regexp.MustCompile(`(?i)^\d+\s*(b|kb|mb|gb)?$`)
+
 // Environment variable names
 const (
-	EnvBaseURL = "OSTICKET_BASE_URL"
-	EnvAPIKey  = "OSTICKET_API_KEY"
+	EnvBaseURL   = "OSTICKET_BASE_URL"
+	EnvAPIKey    = "OSTICKET_API_KEY"
+	EnvConfigDir = "OSTICKET_CONFIG_DIR"
+	EnvProfile   = "OSTICKET_PROFILE"
+	EnvDebug     = "OSTICKET_DEBUG"
+	EnvLogLevel  = "OSTICKET_LOG_LEVEL"
+	// EnvGatewayKey is a key accepted by osticket serve
+	EnvGatewayKey = "OSTICKET_GATEWAY_KEY"
+	// EnvInjectFaults is the development-only fault spec (see --inject-faults)
+	EnvInjectFaults = "OSTICKET_INJECT_FAULTS"
 )
 
-func init() {
-	cfg = viper.New()
-	cfg.SetConfigName("config")
-	cfg.SetConfigType("yaml")
-
-	// Config directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "."
-	}
-
-	configDir := filepath.Join(homeDir, ".osticket-cli")
-	cfg.AddConfigPath(configDir)
+// api_key_store values for keys kept outside the config file: the OS
+// keyring, or the encrypted secrets file where no keyring is available
+const (
+	KeyringStore = "keyring"
+	FileStore    = "encrypted-file"
+)
 
-	// Create config directory if it doesn't exist
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: could not create config directory: %v\n", err)
-	}
+// Load reads the config file. It has no side effects beyond reading: the
+// config directory is only created when settings are saved. Load is called
+// lazily by the accessors, so calling it explicitly is only needed to see
+// read errors. Calling it again reloads the file.
+func Load() error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(GetConfigPath())
 
 	// Set defaults
-	cfg.SetDefault("base_url", "")
-	cfg.SetDefault("api_key", "")
+	v.SetDefault("base_url", "")
+	v.SetDefault("api_key", "")
+	v.SetDefault("api_key_store", "")
+	v.SetDefault("max_attachment_size", "10MB")
+	v.SetDefault("retries", 2)
+	v.SetDefault("retry_wait", "500ms")
+	v.SetDefault("timeout", "30s")
+	v.SetDefault("connect_timeout", "10s")
+	v.SetDefault("read_timeout", "0s")
+	v.SetDefault("cache_ttl", "1h")
+	v.SetDefault("rate_limit", 0)
+	v.SetDefault("warn_slow", "0s")
 
 	// Bind environment variables
-	cfg.BindEnv("base_url", EnvBaseURL)
-	cfg.BindEnv("api_key", EnvAPIKey)
+	v.BindEnv("base_url", EnvBaseURL)
+	// api_key is not bound: GetAPIKey checks the environment itself, and
+	// the config value must show only what is on disk so plaintext keys
+	// can be found and migrated
+
+	cfg = v
 
 	// Read config file if it exists
-	if err := cfg.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		// Config file not found is okay
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			fmt.Fprintf(os.Stderr, "Warning: error reading config: %v\n", err)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
 		}
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	return nil
+}
+
+// settings returns the loaded config, loading it on first use
+func settings() *viper.Viper {
+	if cfg == nil {
+		// Read errors leave the defaults in place; Load reports them
+		Load()
 	}
+	return cfg
+}
+
+// SetConfigDir overrides the config directory for this process, e.g. to
+// point tests at a temporary directory. The config is reloaded on next use.
+func SetConfigDir(dir string) {
+	configDirOverride = dir
+	cfg = nil
+	pending = nil
 }
 
 // Get returns a config value
 func Get(key string) string {
-	return cfg.GetString(key)
+	return settings().GetString(key)
 }
 
 // Set sets a config value and saves to file
 func Set(key, value string) error {
-	cfg.Set(key, value)
+	stage(key, value)
 	return Save()
 }
 
-// Save writes the config to file
-func Save() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("could not get home directory: %w", err)
+// SetProfile selects the named profile for this invocation. An empty name
+// falls back to OSTICKET_PROFILE and then to the default profile.
+func SetProfile(name string) error {
+	if name != "" && !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' or '_'", name)
 	}
+	activeProfile = name
+	return nil
+}
 
-	configPath := filepath.Join(homeDir, ".osticket-cli", "config.yaml")
-	return cfg.WriteConfigAs(configPath)
+// GetProfile returns the active profile name ("" for the default profile)
+func GetProfile() string {
+	if activeProfile != "" {
+		return activeProfile
+	}
+	if envVal := os.Getenv(EnvProfile); profileNamePattern.MatchString(envVal) {
+		return envVal
+	}
+	return ""
+}
+
+// ListProfiles returns the names of all named profiles
+func ListProfiles() []string {
+	var names []string
+	for name := range settings().GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
-// GetBaseURL returns the API base URL (env var takes precedence)
+// profileKey maps a setting to its key within the active profile
+func profileKey(key string) string {
+	return profileKeyFor(GetProfile(), key)
+}
+
+// profileKeyFor maps a setting to its key within a profile
+func profileKeyFor(profile, key string) string {
+	if profile != "" {
+		return "profiles." + profile + "." + key
+	}
+	return key
+}
+
+// keyringKey returns the keyring entry name for the active profile's API key
+func keyringKey() string {
+	return keyringKeyFor(GetProfile())
+}
+
+func keyringKeyFor(profile string) string {
+	if profile != "" {
+		return "api_key:" + profile
+	}
+	return "api_key"
+}
+
+// secretsFile is the encrypted fallback for systems without a keyring. Its
+// encryption key is kept in the state directory, apart from the config.
+func secretsFile() *keyring.File {
+	return &keyring.File{
+		Path:    filepath.Join(GetConfigDir(), "secrets.enc"),
+		KeyPath: filepath.Join(GetStateDir(), "secrets.key"),
+	}
+}
+
+// Save writes the values changed by this process to the config file.
+// Other keys on disk are left untouched, so concurrent invocations do not
+// overwrite each other's settings.
+func Save() error {
+	return writeConfig(GetConfigPath())
+}
+
+// Overrides hold per-invocation credentials that take precedence over the
+// environment and config file and are never saved
+type Overrides struct {
+	BaseURL      string
+	APIKey       string
+	APIKeySource string // e.g. "flag:--api-key", shown by config show
+}
+
+var overrides Overrides
+
+// SetOverrides sets per-invocation credentials
+func SetOverrides(o Overrides) {
+	overrides = o
+}
+
+// logger receives warnings such as an unreadable keyring
+var logger = slog.Default()
+
+// SetLogger sets where the package logs warnings
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// GetBaseURL returns the API base URL (flag override, then env var, then config)
 func GetBaseURL() string {
+	if overrides.BaseURL != "" {
+		return overrides.BaseURL
+	}
 	// Check environment variable first
 	if envVal := os.Getenv(EnvBaseURL); envVal != "" {
 		return envVal
 	}
-	return cfg.GetString("base_url")
+	return settings().GetString(profileKey("base_url"))
 }
 
-// GetAPIKey returns the API key (env var takes precedence)
+// DebugFromEnv reports whether OSTICKET_DEBUG enables debug logging
+// (1, true, ...)
+func DebugFromEnv() bool {
+	debug, _ := strconv.ParseBool(os.Getenv(EnvDebug))
+	return debug
+}
+
+// GetAPIKey returns the API key (flag override, then env var, then config)
 func GetAPIKey() string {
+	if overrides.APIKey != "" {
+		return overrides.APIKey
+	}
 	// Check environment variable first
 	if envVal := os.Getenv(EnvAPIKey); envVal != "" {
 		return envVal
 	}
-	return cfg.GetString("api_key")
+	switch settings().GetString(profileKey("api_key_store")) {
+	case KeyringStore:
+		key, err := keyring.Get(keyringKey())
+		if err != nil {
+			logger.Warn("could not read API key from keyring", "error", err)
+			return ""
+		}
+		return key
+	case FileStore:
+		key, err := secretsFile().Get(keyringKey())
+		if err != nil {
+			logger.Warn("could not read API key", "error", err)
+			return ""
+		}
+		return key
+	}
+	return settings().GetString(profileKey("api_key"))
 }
 
 // SetBaseURL sets the API base URL
 func SetBaseURL(url string) error {
-	return Set("base_url", url)
+	return Set(profileKey("base_url"), url)
+}
+
+// SetAPIKey stores the API key encrypted: in the OS keyring when one is
+// available, otherwise, unless requireKeyring is set, in the encrypted
+// secrets file. It returns the store used.
+func SetAPIKey(key string, requireKeyring bool) (string, error) {
+	store, err := storeAPIKey(GetProfile(), key, requireKeyring)
+	if err != nil {
+		return "", err
+	}
+	return store, Save()
+}
+
+// storeAPIKey saves a profile's API key outside the config file and stages
+// the config change. A keyring that fails (e.g. a locked or missing
+// collection) falls back to the encrypted file unless requireKeyring is
+// set.
+func storeAPIKey(profile, key string, requireKeyring bool) (string, error) {
+	store := KeyringStore
+	err := keyring.ErrUnsupported
+	if keyring.Supported() {
+		err = keyring.Set(keyringKeyFor(profile), key)
+	}
+	if err != nil {
+		if requireKeyring {
+			return "", fmt.Errorf("could not store API key in keyring: %w", err)
+		}
+		store = FileStore
+		if err := secretsFile().Set(keyringKeyFor(profile), key); err != nil {
+			return "", fmt.Errorf("could not store API key: %w", err)
+		}
+	}
+
+	if old := settings().GetString(profileKeyFor(profile, "api_key_store")); old != "" && old != store {
+		deleteAPIKey(old, profile)
+	}
+	unstage(profileKeyFor(profile, "api_key"))
+	stage(profileKeyFor(profile, "api_key_store"), store)
+	return store, nil
+}
+
+// deleteAPIKey removes a profile's API key from a store
+func deleteAPIKey(store, profile string) error {
+	var err error
+	switch store {
+	case KeyringStore:
+		err = keyring.Delete(keyringKeyFor(profile))
+	case FileStore:
+		err = secretsFile().Delete(keyringKeyFor(profile))
+	}
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("could not remove API key from %s: %w", store, err)
+	}
+	return nil
+}
+
+// PlaintextAPIKeys returns the profiles ("" for the default) whose API key
+// is still stored in plaintext in the config file
+func PlaintextAPIKeys() []string {
+	var profiles []string
+	for _, profile := range append([]string{""}, ListProfiles()...) {
+		if settings().GetString(profileKeyFor(profile, "api_key_store")) == "" && settings().GetString(profileKeyFor(profile, "api_key")) != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// EncryptAPIKeys moves plaintext API keys from the config file to
+// encrypted storage. It returns the keys moved as "profile -> store".
+func EncryptAPIKeys() ([]string, error) {
+	var moves []string
+	for _, profile := range PlaintextAPIKeys() {
+		store, err := storeAPIKey(profile, settings().GetString(profileKeyFor(profile, "api_key")), false)
+		if err != nil {
+			return moves, err
+		}
+		name := profile
+		if name == "" {
+			name = "(default)"
+		}
+		moves = append(moves, name+" -> "+store)
+	}
+	if len(moves) == 0 {
+		return nil, nil
+	}
+	return moves, Save()
+}
+
+// GetMaxAttachmentSize returns the attachment upload limit in bytes.
+// The config value accepts units, e.g. "10MB" or "512KB".
+func GetMaxAttachmentSize() int64 {
+	return int64(settings().GetSizeInBytes("max_attachment_size"))
+}
+
+// SetMaxAttachmentSize sets the attachment upload limit (e.g. "20MB")
+func SetMaxAttachmentSize(size string) error {
+	if !sizePattern.MatchString(size) {
+		return fmt.Errorf("invalid size %q: use a number with an optional B, KB, MB or GB unit", size)
+	}
+	return Set("max_attachment_size", size)
+}
+
+// GetConnection returns how the active profile reaches its server: proxy,
+// extra CAs, client certificate and whether TLS verification is skipped
+func GetConnection() osticket.Connection {
+	return osticket.Connection{
+		Proxy:    settings().GetString(profileKey("proxy")),
+		CAFile:   settings().GetString(profileKey("ca_cert")),
+		CertFile: settings().GetString(profileKey("client_cert")),
+		KeyFile:  settings().GetString(profileKey("client_key")),
+		Insecure: settings().GetBool(profileKey("insecure")),
+	}
+}
+
+// SetProxy sets the active profile's proxy URL; empty uses the
+// HTTP_PROXY/HTTPS_PROXY environment
+func SetProxy(proxy string) error {
+	if proxy == "" {
+		unstage(profileKey("proxy"))
+		return Save()
+	}
+	if _, err := osticket.ParseProxy(proxy); err != nil {
+		return err
+	}
+	return Set(profileKey("proxy"), proxy)
+}
+
+// SetCACert sets the CA bundle trusted by the active profile; empty
+// trusts only the system CAs
+func SetCACert(path string) error {
+	return setFilePath("ca_cert", path)
+}
+
+// SetClientCert sets the active profile's client certificate and key;
+// empty values remove them
+func SetClientCert(cert, key string) error {
+	if err := setFilePath("client_cert", cert); err != nil {
+		return err
+	}
+	return setFilePath("client_key", key)
+}
+
+// setFilePath stores the absolute path of an existing file, or removes the
+// setting when path is empty
+func setFilePath(key, path string) error {
+	if path == "" {
+		unstage(profileKey(key))
+		return Save()
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return err
+	}
+	return Set(profileKey(key), abs)
+}
+
+// SetInsecure sets whether the active profile skips TLS verification
+func SetInsecure(insecure bool) error {
+	if !insecure {
+		unstage(profileKey("insecure"))
+		return Save()
+	}
+	stage(profileKey("insecure"), true)
+	return Save()
+}
+
+// GetRetries returns how many times failed requests are retried
+func GetRetries() int {
+	return settings().GetInt("retries")
+}
+
+// GetRetryWait returns the base delay between retries
+func GetRetryWait() time.Duration {
+	return settings().GetDuration("retry_wait")
+}
+
+// GetTimeout returns how long a request may take, 0 for no limit
+func GetTimeout() time.Duration {
+	return settings().GetDuration("timeout")
+}
+
+// GetConnectTimeout returns how long connecting to the server may take,
+// 0 for the system default
+func GetConnectTimeout() time.Duration {
+	return settings().GetDuration("connect_timeout")
+}
+
+// GetReadTimeout returns how long to wait for the server to start
+// answering a request, 0 for as long as the request timeout allows
+func GetReadTimeout() time.Duration {
+	return settings().GetDuration("read_timeout")
+}
+
+// GetCacheTTL returns how long cached lists such as departments stay fresh
+func GetCacheTTL() time.Duration {
+	return settings().GetDuration("cache_ttl")
+}
+
+// SetCacheTTL sets how long cached lists stay fresh (e.g. "30m", "0" to
+// disable caching)
+func SetCacheTTL(ttl string) error {
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q: use a value like 30m or 0 to disable", ttl)
+	}
+	return Set("cache_ttl", ttl)
+}
+
+// SetRetries sets how many times failed requests are retried
+func SetRetries(n int) error {
+	if n < 0 {
+		return fmt.Errorf("retries cannot be negative")
+	}
+	stage("retries", n)
+	return Save()
+}
+
+// SetRetryWait sets the base delay between retries (e.g. "1s")
+func SetRetryWait(wait string) error {
+	d, err := time.ParseDuration(wait)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q: use a value like 500ms or 2s", wait)
+	}
+	return Set("retry_wait", wait)
+}
+
+// SetTimeout sets how long a request may take (e.g. "2m", "0" for no
+// limit)
+func SetTimeout(d string) error {
+	return setTimeout("timeout", d)
+}
+
+// SetConnectTimeout sets how long connecting to the server may take
+// (e.g. "5s", "0" for the system default)
+func SetConnectTimeout(d string) error {
+	return setTimeout("connect_timeout", d)
+}
+
+// SetReadTimeout sets how long to wait for the server to start answering
+// (e.g. "90s", "0" for as long as the request timeout allows)
+func SetReadTimeout(d string) error {
+	return setTimeout("read_timeout", d)
+}
+
+func setTimeout(key, d string) error {
+	parsed, err := time.ParseDuration(d)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("invalid duration %q: use a value like 10s or 2m, or 0 for no limit", d)
+	}
+	return Set(key, d)
+}
+
+// GetRateLimit returns the maximum requests per second, 0 for no limit
+func GetRateLimit() float64 {
+	return settings().GetFloat64("rate_limit")
+}
+
+// SetRateLimit sets the maximum requests per second (0 removes the limit)
+func SetRateLimit(rate float64) error {
+	if rate < 0 {
+		return fmt.Errorf("rate limit cannot be negative")
+	}
+	stage("rate_limit", rate)
+	return Save()
+}
+
+// GetWarnSlow returns the request duration above which a warning is
+// printed, 0 for none
+func GetWarnSlow() time.Duration {
+	return settings().GetDuration("warn_slow")
+}
+
+// SetWarnSlow sets the request duration above which a warning is printed
+// (e.g. "2s", "0" to disable the warnings)
+func SetWarnSlow(budget string) error {
+	d, err := time.ParseDuration(budget)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q: use a value like 2s or 0 to disable", budget)
+	}
+	return Set("warn_slow", budget)
 }
 
-// SetAPIKey sets the API key
-func SetAPIKey(key string) error {
-	return Set("api_key", key)
+// GetLogFile returns the file diagnostics are logged to, empty for stderr
+func GetLogFile() string {
+	return settings().GetString("log_file")
+}
+
+// SetLogFile sets the file diagnostics are logged to as JSON lines (empty
+// logs to stderr again). Relative paths are made absolute so cron jobs
+// log to the same file whatever their working directory.
+func SetLogFile(path string) error {
+	if path == "" {
+		return Set("log_file", "")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid log file %q: %w", path, err)
+	}
+	return Set("log_file", abs)
+}
+
+// GetAgeThresholds returns the per-priority ages after which tickets are
+// shown as late, with the defaults for priorities not in the config
+func GetAgeThresholds() (*thresholds.Thresholds, error) {
+	return thresholds.New(settings().GetStringMapString("age_thresholds"))
+}
+
+// SetAgeThreshold sets the late age for a priority name or ID (e.g.
+// "emergency", "30m")
+func SetAgeThreshold(priority, age string) error {
+	if _, err := thresholds.New(map[string]string{priority: age}); err != nil {
+		return err
+	}
+	return Set("age_thresholds."+strings.ToLower(priority), age)
+}
+
+// GetCredentialSpec returns the credential provider configured for the
+// active profile (Provider is empty when none is set)
+func GetCredentialSpec() credentials.Spec {
+	var spec credentials.Spec
+	settings().UnmarshalKey(profileKey("credentials"), &spec)
+	return spec
+}
+
+// SetCredentialSpec stores the credential provider for the active profile.
+// An empty Provider removes it.
+func SetCredentialSpec(spec credentials.Spec) error {
+	key := profileKey("credentials")
+	if spec.Provider == "" {
+		settings().Set(key, map[string]interface{}{})
+		unstage(key)
+		return Save()
+	}
+
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	values := map[string]interface{}{"provider": spec.Provider}
+	for k, v := range map[string]string{
+		"command":   spec.Command,
+		"addr":      spec.Addr,
+		"path":      spec.Path,
+		"field":     spec.Field,
+		"secret_id": spec.SecretID,
+		"region":    spec.Region,
+	} {
+		if v != "" {
+			values[k] = v
+		}
+	}
+	unstage(key)
+	stage(key, values)
+	return Save()
+}
+
+// GetIgnoreRules returns the active profile's rules for tickets that
+// watch modes should not report
+func GetIgnoreRules() ignore.Rules {
+	var rules ignore.Rules
+	settings().UnmarshalKey(profileKey("ignore"), &rules)
+	return rules
+}
+
+// SetIgnoreRules stores the active profile's ignore rules. Empty rules
+// remove the setting.
+func SetIgnoreRules(rules ignore.Rules) error {
+	if _, err := rules.Compile(); err != nil {
+		return err
+	}
+
+	key := profileKey("ignore")
+	unstage(key)
+	if rules.Empty() {
+		settings().Set(key, map[string]interface{}{})
+		return Save()
+	}
+
+	values := map[string]interface{}{}
+	if len(rules.Numbers) > 0 {
+		values["numbers"] = rules.Numbers
+	}
+	if len(rules.Subjects) > 0 {
+		values["subjects"] = rules.Subjects
+	}
+	if len(rules.Senders) > 0 {
+		values["senders"] = rules.Senders
+	}
+	if len(rules.Departments) > 0 {
+		values["departments"] = rules.Departments
+	}
+	stage(key, values)
+	return Save()
+}
+
+// GetEntitlementSpec returns the entitlement hook of the active profile
+// (Kind is empty when none is set)
+func GetEntitlementSpec() entitlement.Spec {
+	var spec entitlement.Spec
+	settings().UnmarshalKey(profileKey("entitlement"), &spec)
+	return spec
+}
+
+// SetEntitlementSpec stores the entitlement hook of the active profile.
+// An empty Kind removes it.
+func SetEntitlementSpec(spec entitlement.Spec) error {
+	key := profileKey("entitlement")
+	unstage(key)
+	if spec.Kind == "" {
+		settings().Set(key, map[string]interface{}{})
+		return Save()
+	}
+
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	values := map[string]interface{}{"kind": spec.Kind}
+	for k, v := range map[string]string{
+		"command":      spec.Command,
+		"url":          spec.URL,
+		"tier_field":   spec.TierField,
+		"expiry_field": spec.ExpiryField,
+	} {
+		if v != "" {
+			values[k] = v
+		}
+	}
+	if len(spec.SLAs) > 0 {
+		slas := map[string]interface{}{}
+		for tier, sla := range spec.SLAs {
+			slas[strings.ToLower(tier)] = sla
+		}
+		values["slas"] = slas
+	}
+	stage(key, values)
+	return Save()
+}
+
+// UsesCredentialProvider reports whether the API key must be fetched from
+// a credential provider, i.e. one is configured and no flag or environment
+// variable supplies the key
+func UsesCredentialProvider() bool {
+	return overrides.APIKey == "" && os.Getenv(EnvAPIKey) == "" && GetCredentialSpec().Provider != ""
 }
 
 // IsConfigured checks if the CLI is configured
 func IsConfigured() bool {
-	return GetBaseURL() != "" && GetAPIKey() != ""
+	return GetBaseURL() != "" && (GetAPIKey() != "" || UsesCredentialProvider() || GetSessionSpec().LoginURL != "")
 }
 
-// Clear clears all configuration
+// Clear clears the active profile's configuration. Named profiles are
+// removed entirely; the default profile is reset to empty values.
 func Clear() error {
-	cfg.Set("base_url", "")
-	cfg.Set("api_key", "")
+	if err := deleteAPIKey(settings().GetString(profileKey("api_key_store")), GetProfile()); err != nil {
+		return err
+	}
+	deleteSessionSecrets(GetProfile())
+
+	if profile := GetProfile(); profile != "" {
+		profiles := settings().GetStringMap("profiles")
+		delete(profiles, profile)
+		settings().Set("profiles", profiles)
+		unstage("profiles." + profile)
+		return Save()
+	}
+
+	stage("base_url", "")
+	stage("api_key", "")
+	stage("api_key_store", "")
+	settings().Set("credentials", map[string]interface{}{})
+	unstage("credentials")
+	settings().Set("session", map[string]interface{}{})
+	unstage("session")
 	return Save()
 }
 
 // GetConfigPath returns the path to the config file
 func GetConfigPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".osticket-cli", "config.yaml")
+	return filepath.Join(GetConfigDir(), "config.yaml")
 }
 
 // GetConfigSource returns where each config value is coming from
 func GetConfigSource() (baseURLSource, apiKeySource string) {
-	if os.Getenv(EnvBaseURL) != "" {
+	if overrides.BaseURL != "" {
+		baseURLSource = "flag:--url"
+	} else if os.Getenv(EnvBaseURL) != "" {
 		baseURLSource = "env:" + EnvBaseURL
-	} else if cfg.GetString("base_url") != "" {
+	} else if settings().GetString(profileKey("base_url")) != "" {
 		baseURLSource = "config"
 	} else {
 		baseURLSource = "not set"
 	}
 
-	if os.Getenv(EnvAPIKey) != "" {
+	if overrides.APIKey != "" {
+		apiKeySource = overrides.APIKeySource
+	} else if os.Getenv(EnvAPIKey) != "" {
 		apiKeySource = "env:" + EnvAPIKey
-	} else if cfg.GetString("api_key") != "" {
-		apiKeySource = "config"
+	} else if spec := GetCredentialSpec(); spec.Provider != "" {
+		apiKeySource = "provider:" + spec.Provider
+	} else if store := settings().GetString(profileKey("api_key_store")); store != "" {
+		apiKeySource = store
+	} else if settings().GetString(profileKey("api_key")) != "" {
+		apiKeySource = "config, plaintext"
 	} else {
 		apiKeySource = "not set"
 	}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	lockRetryInterval = 50 * time.Millisecond
+	lockTimeout       = 10 * time.Second
+	// lockStaleAfter is how old a lock file may get before it is assumed
+	// to belong to a crashed process
+	lockStaleAfter = 30 * time.Second
+)
+
+// change is a pending edit to a single config key. A nil value deletes it.
+type change struct {
+	key   string
+	value interface{}
+}
+
+// pending holds edits made in this process that Save has not written yet
+var pending []change
+
+// stage sets a value in memory and queues it for the next Save
+func stage(key string, value interface{}) {
+	settings().Set(key, value)
+	pending = append(pending, change{key: key, value: value})
+}
+
+// unstage queues deletion of a key for the next Save
+func unstage(key string) {
+	pending = append(pending, change{key: key})
+}
+
+// writeConfig applies pending changes to the config file on disk. The file
+// is re-read under a lock so values written by other processes since this
+// one started are kept, then replaced atomically via rename.
+func writeConfig(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	settings := map[string]interface{}{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("could not parse config: %w", err)
+		}
+	}
+
+	for _, c := range pending {
+		if c.value == nil {
+			deleteNested(settings, strings.Split(c.key, "."))
+		} else {
+			setNested(settings, strings.Split(c.key, "."), c.value)
+		}
+	}
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("could not encode config: %w", err)
+	}
+	if err := writeFileAtomic(path, out, 0600); err != nil {
+		return err
+	}
+
+	pending = nil
+	return nil
+}
+
+// setNested sets a value at a dotted key path, creating maps as needed
+func setNested(m map[string]interface{}, path []string, value interface{}) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+// deleteNested removes the value at a dotted key path
+func deleteNested(m map[string]interface{}, path []string) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, path[len(path)-1])
+}
+
+// writeFileAtomic writes data to a temp file in the same directory and
+// renames it over path so readers never see a partial file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temp config: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write temp config: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not sync temp config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not close temp config: %w", err)
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return fmt.Errorf("could not set config permissions: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("could not replace config: %w", err)
+	}
+	return nil
+}
+
+// lockFile takes an exclusive lock by creating path, waiting for other
+// holders up to lockTimeout. The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("could not lock config: %w", err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for config lock %s", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// appName is the directory name used under each base directory
+const appName = "osticket-cli"
+
+// XDG base directory environment variables
+const (
+	EnvXDGConfigHome = "XDG_CONFIG_HOME"
+	EnvXDGCacheHome  = "XDG_CACHE_HOME"
+	EnvXDGStateHome  = "XDG_STATE_HOME"
+)
+
+// GetConfigDir returns the config directory. SetConfigDir and then
+// OSTICKET_CONFIG_DIR override it. On Windows %APPDATA%\osticket-cli is
+// used unless a legacy ~/.osticket-cli directory already exists; elsewhere
+// $XDG_CONFIG_HOME/osticket-cli is used, falling back to ~/.osticket-cli
+// until it has been migrated.
+func GetConfigDir() string {
+	if configDirOverride != "" {
+		return configDirOverride
+	}
+	if envVal := os.Getenv(EnvConfigDir); envVal != "" {
+		return envVal
+	}
+
+	legacyDir := GetLegacyDir()
+
+	if runtime.GOOS == "windows" {
+		if appData, err := os.UserConfigDir(); err == nil {
+			appDir := filepath.Join(appData, appName)
+			if dirExists(appDir) || !dirExists(legacyDir) {
+				return appDir
+			}
+		}
+		return legacyDir
+	}
+
+	xdgDir := xdgConfigDir()
+	if !dirExists(xdgDir) && dirExists(legacyDir) {
+		return legacyDir
+	}
+	return xdgDir
+}
+
+// GetCacheDir returns the directory for disposable data such as offline
+// snapshots ($XDG_CACHE_HOME/osticket-cli)
+func GetCacheDir() string {
+	if !usesXDG() {
+		return filepath.Join(GetConfigDir(), "offline")
+	}
+	return xdgDir(EnvXDGCacheHome, ".cache")
+}
+
+// GetStateDir returns the directory for data that must persist between
+// runs but is not configuration, such as the outbox
+// ($XDG_STATE_HOME/osticket-cli)
+func GetStateDir() string {
+	if !usesXDG() {
+		return filepath.Join(GetConfigDir(), "offline")
+	}
+	return xdgDir(EnvXDGStateHome, filepath.Join(".local", "state"))
+}
+
+// GetSnapshotDir returns the offline snapshot directory for the active profile
+func GetSnapshotDir() string {
+	return filepath.Join(GetCacheDir(), profileSubdir(), "snapshots")
+}
+
+// GetListCacheDir returns the directory of cached lists (departments,
+// topics, SLAs, staff) for the active profile
+func GetListCacheDir() string {
+	return filepath.Join(GetCacheDir(), profileSubdir(), "lists")
+}
+
+// GetOutboxDir returns the outbox directory for the active profile
+func GetOutboxDir() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "outbox")
+}
+
+// GetCheckpointDir returns the bulk job checkpoint directory for the active
+// profile
+func GetCheckpointDir() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "checkpoints")
+}
+
+// GetExportStatePath returns the default delta export state file of a job
+// (e.g. "export") for the active profile
+func GetExportStatePath(job string) string {
+	return filepath.Join(GetStateDir(), profileSubdir(), job+".state")
+}
+
+// GetRetentionDir returns the directory holding retention dry-run plans
+// and evidence reports for the active profile
+func GetRetentionDir() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "retention")
+}
+
+// GetHoldPath returns the legal hold list of the active profile
+func GetHoldPath() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "holds.json")
+}
+
+// GetSurveyDir returns the directory holding the survey sends and
+// responses of the active profile
+func GetSurveyDir() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "surveys")
+}
+
+// GetDaemonStatePath returns the default daemon state file (tickets seen
+// and undelivered events) of the active profile
+func GetDaemonStatePath() string {
+	return filepath.Join(GetStateDir(), profileSubdir(), "daemon.json")
+}
+
+// GetTemplateDir returns the ticket template directory, shared by every
+// profile
+func GetTemplateDir() string {
+	return filepath.Join(GetConfigDir(), "templates")
+}
+
+// GetLegacyDir returns the pre-XDG ~/.osticket-cli directory
+func GetLegacyDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".osticket-cli")
+}
+
+// NeedsMigration reports whether a legacy ~/.osticket-cli directory is in
+// use where XDG locations apply
+func NeedsMigration() bool {
+	return runtime.GOOS != "windows" &&
+		configDirOverride == "" &&
+		os.Getenv(EnvConfigDir) == "" &&
+		GetConfigDir() == GetLegacyDir()
+}
+
+// Migrate moves the legacy ~/.osticket-cli directory to the XDG config,
+// cache and state directories. It returns the moves made as "from -> to".
+func Migrate() ([]string, error) {
+	if !NeedsMigration() {
+		return nil, fmt.Errorf("no legacy configuration to migrate")
+	}
+
+	legacyDir := GetLegacyDir()
+	configDir := xdgConfigDir()
+	cacheDir := xdgDir(EnvXDGCacheHome, ".cache")
+	stateDir := xdgDir(EnvXDGStateHome, filepath.Join(".local", "state"))
+
+	var moves []string
+	move := func(from, to string) error {
+		if _, err := os.Stat(to); err == nil {
+			return fmt.Errorf("%s already exists", to)
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+			return err
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		moves = append(moves, from+" -> "+to)
+		return nil
+	}
+
+	// Snapshots and outboxes live at offline[/profiles/<name>]/{snapshots,outbox}
+	offlineDir := filepath.Join(legacyDir, "offline")
+	var dirs []string
+	filepath.WalkDir(offlineDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() && (d.Name() == "snapshots" || d.Name() == "outbox") {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	for _, dir := range dirs {
+		rel, _ := filepath.Rel(offlineDir, dir)
+		base := cacheDir
+		if filepath.Base(dir) == "outbox" {
+			base = stateDir
+		}
+		if err := move(dir, filepath.Join(base, rel)); err != nil {
+			return moves, fmt.Errorf("could not migrate %s: %w", dir, err)
+		}
+	}
+
+	legacyConfig := filepath.Join(legacyDir, "config.yaml")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return moves, fmt.Errorf("could not create %s: %w", configDir, err)
+	}
+	if _, err := os.Stat(legacyConfig); err == nil {
+		if err := move(legacyConfig, filepath.Join(configDir, "config.yaml")); err != nil {
+			return moves, fmt.Errorf("could not migrate config: %w", err)
+		}
+	}
+
+	removeEmptyDirs(legacyDir)
+	cfg = nil
+	return moves, nil
+}
+
+// usesXDG reports whether the XDG layout is active
+func usesXDG() bool {
+	return GetConfigDir() == xdgConfigDir()
+}
+
+func xdgConfigDir() string {
+	return xdgDir(EnvXDGConfigHome, ".config")
+}
+
+// xdgDir returns $<env>/osticket-cli, or ~/<fallback>/osticket-cli when the
+// variable is unset or not absolute as the spec requires
+func xdgDir(env, fallback string) string {
+	if base := os.Getenv(env); base != "" && filepath.IsAbs(base) {
+		return filepath.Join(base, appName)
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, fallback, appName)
+}
+
+// profileSubdir returns the per-profile subdirectory ("" for the default)
+func profileSubdir() string {
+	if profile := GetProfile(); profile != "" {
+		return filepath.Join("profiles", profile)
+	}
+	return ""
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// removeEmptyDirs removes dir and its subdirectories if they are empty
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			removeEmptyDirs(filepath.Join(dir, e.Name()))
+		}
+	}
+	// Lock files left by crashed writers do not count as content
+	entries, _ = os.ReadDir(dir)
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".lock") {
+			return
+		}
+	}
+	os.RemoveAll(dir)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/osticket-cli-go/internal/keyring"
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// SessionSpec describes the login call of an API gateway that issues
+// session tokens (see osticket.Session). The password and the cached token are
+// kept in Store, the OS keyring or the encrypted secrets file, never in
+// the config file.
+type SessionSpec struct {
+	LoginURL   string `mapstructure:"login_url"`
+	Username   string `mapstructure:"username"`
+	TokenField string `mapstructure:"token_field"`
+	Header     string `mapstructure:"header"`
+	Store      string `mapstructure:"store"`
+}
+
+// Validate checks that the login URL is an absolute HTTP(S) URL and a
+// username is given
+func (s SessionSpec) Validate() error {
+	u, err := url.Parse(s.LoginURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid login URL %q: use an http or https URL", s.LoginURL)
+	}
+	if s.Username == "" {
+		return fmt.Errorf("a username is required for the session login")
+	}
+	return nil
+}
+
+// sessionKeyFor returns the secret store entry for a profile's session
+// password or token
+func sessionKeyFor(profile, kind string) string {
+	if profile != "" {
+		return "session_" + kind + ":" + profile
+	}
+	return "session_" + kind
+}
+
+// GetSessionSpec returns the active profile's session login (LoginURL is
+// empty when none is set)
+func GetSessionSpec() SessionSpec {
+	var spec SessionSpec
+	settings().UnmarshalKey(profileKey("session"), &spec)
+	return spec
+}
+
+// SetSession stores a session login for the active profile. The password
+// goes to the OS keyring when one is available, otherwise to the encrypted
+// secrets file; the store used is returned. A token cached for an earlier
+// login is dropped.
+func SetSession(spec SessionSpec, password string) (string, error) {
+	if err := spec.Validate(); err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("a password is required for the session login")
+	}
+
+	profile := GetProfile()
+	deleteSessionSecrets(profile)
+	spec.Store = FileStore
+	if keyring.Supported() && keyring.Set(sessionKeyFor(profile, "password"), password) == nil {
+		spec.Store = KeyringStore
+	} else if err := secretsFile().Set(sessionKeyFor(profile, "password"), password); err != nil {
+		return "", fmt.Errorf("could not store session password: %w", err)
+	}
+
+	values := map[string]interface{}{
+		"login_url": spec.LoginURL,
+		"username":  spec.Username,
+		"store":     spec.Store,
+	}
+	if spec.TokenField != "" {
+		values["token_field"] = spec.TokenField
+	}
+	if spec.Header != "" {
+		values["header"] = spec.Header
+	}
+	key := profileKey("session")
+	unstage(key)
+	stage(key, values)
+	return spec.Store, Save()
+}
+
+// ClearSession removes the active profile's session login, password and
+// cached token
+func ClearSession() error {
+	deleteSessionSecrets(GetProfile())
+	key := profileKey("session")
+	settings().Set(key, map[string]interface{}{})
+	unstage(key)
+	return Save()
+}
+
+// deleteSessionSecrets removes a profile's session password and token from
+// both stores. Missing entries are not an error.
+func deleteSessionSecrets(profile string) {
+	for _, kind := range []string{"password", "token"} {
+		key := sessionKeyFor(profile, kind)
+		if keyring.Supported() {
+			keyring.Delete(key)
+		}
+		secretsFile().Delete(key)
+	}
+}
+
+// GetSession returns the active profile's session login with its password
+// and a token cache, or nil when none is configured
+func GetSession() (*osticket.Session, error) {
+	spec := GetSessionSpec()
+	if spec.LoginURL == "" {
+		return nil, nil
+	}
+
+	profile := GetProfile()
+	password, err := getSecret(spec.Store, sessionKeyFor(profile, "password"))
+	if err != nil {
+		return nil, fmt.Errorf("could not read session password: %w", err)
+	}
+	return &osticket.Session{
+		LoginURL:   spec.LoginURL,
+		Username:   spec.Username,
+		Password:   password,
+		TokenField: spec.TokenField,
+		Header:     spec.Header,
+		Cache:      &sessionCache{store: spec.Store, key: sessionKeyFor(profile, "token")},
+	}, nil
+}
+
+// getSecret reads an entry from the keyring or the encrypted secrets file
+func getSecret(store, key string) (string, error) {
+	if store == KeyringStore {
+		return keyring.Get(key)
+	}
+	return secretsFile().Get(key)
+}
+
+// sessionCache keeps a profile's session token in the same store as its
+// password
+type sessionCache struct {
+	store string
+	key   string
+}
+
+// Load implements osticket.TokenCache
+func (c *sessionCache) Load() (*osticket.SessionToken, error) {
+	data, err := getSecret(c.store, c.key)
+	if err != nil {
+		return nil, err
+	}
+	var t osticket.SessionToken
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return nil, fmt.Errorf("invalid cached session token: %w", err)
+	}
+	return &t, nil
+}
+
+// Save implements osticket.TokenCache
+func (c *sessionCache) Save(t *osticket.SessionToken) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if c.store == KeyringStore {
+		return keyring.Set(c.key, string(data))
+	}
+	return secretsFile().Set(c.key, string(data))
+}
+
+// Clear implements osticket.TokenCache
+func (c *sessionCache) Clear() error {
+	var err error
+	if c.store == KeyringStore {
+		err = keyring.Delete(c.key)
+	} else {
+		err = secretsFile().Delete(c.key)
+	}
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
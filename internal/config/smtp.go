@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/osticket-cli-go/internal/keyring"
+	"github.com/osticket-cli-go/internal/mail"
+)
+
+// SMTPProfile is a named SMTP server that commands such as survey send
+// mail through. Profiles are shared by every configuration profile; the
+// password is kept in Store, the OS keyring or the encrypted secrets file.
+type SMTPProfile struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	From     string `mapstructure:"from"`
+	Security string `mapstructure:"security"`
+	Store    string `mapstructure:"store"`
+}
+
+// smtpNamePattern restricts profile names to what survives as a config key
+var smtpNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// smtpKey returns the config key of an SMTP profile
+func smtpKey(name string) string {
+	return "smtp." + name
+}
+
+// smtpSecretKey returns the secret store entry of an SMTP profile's password
+func smtpSecretKey(name string) string {
+	return "smtp_password:" + name
+}
+
+// ListSMTPProfiles returns the names of the SMTP profiles
+func ListSMTPProfiles() []string {
+	var names []string
+	for name := range settings().GetStringMap("smtp") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetSMTPProfile returns an SMTP profile without its password
+func GetSMTPProfile(name string) (SMTPProfile, bool) {
+	var p SMTPProfile
+	if !settings().IsSet(smtpKey(name)) {
+		return p, false
+	}
+	settings().UnmarshalKey(smtpKey(name), &p)
+	return p, true
+}
+
+// GetSMTPServer returns an SMTP profile with its password, ready to send
+func GetSMTPServer(name string) (*mail.Server, error) {
+	p, ok := GetSMTPProfile(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown SMTP profile %q: add it with osticket config smtp %s", name, name)
+	}
+	s := &mail.Server{
+		Host:     p.Host,
+		Port:     p.Port,
+		Username: p.Username,
+		From:     p.From,
+		Security: p.Security,
+	}
+	if p.Username != "" {
+		password, err := getSecret(p.Store, smtpSecretKey(name))
+		if err != nil {
+			return nil, fmt.Errorf("could not read password of SMTP profile %s: %w", name, err)
+		}
+		s.Password = password
+	}
+	return s, nil
+}
+
+// SetSMTPProfile adds or replaces an SMTP profile. The password, needed
+// when a username is set, goes to the OS keyring when one is available,
+// otherwise to the encrypted secrets file; the store used is returned.
+func SetSMTPProfile(name string, p SMTPProfile, password string) (string, error) {
+	if !smtpNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid SMTP profile name %q: use lowercase letters, digits, - and _", name)
+	}
+	server := mail.Server{Host: p.Host, Port: p.Port, From: p.From, Security: p.Security}
+	if err := server.Validate(); err != nil {
+		return "", err
+	}
+	if p.Username != "" && password == "" {
+		return "", fmt.Errorf("a password is required with an SMTP username")
+	}
+
+	deleteSMTPSecret(name)
+	values := map[string]interface{}{
+		"host": p.Host,
+		"port": p.Port,
+		"from": p.From,
+	}
+	if p.Security != "" {
+		values["security"] = p.Security
+	}
+	if p.Username != "" {
+		p.Store = FileStore
+		if keyring.Supported() && keyring.Set(smtpSecretKey(name), password) == nil {
+			p.Store = KeyringStore
+		} else if err := secretsFile().Set(smtpSecretKey(name), password); err != nil {
+			return "", fmt.Errorf("could not store SMTP password: %w", err)
+		}
+		values["username"] = p.Username
+		values["store"] = p.Store
+	}
+
+	key := smtpKey(name)
+	unstage(key)
+	stage(key, values)
+	return p.Store, Save()
+}
+
+// RemoveSMTPProfile deletes an SMTP profile and its password
+func RemoveSMTPProfile(name string) error {
+	if _, ok := GetSMTPProfile(name); !ok {
+		return fmt.Errorf("unknown SMTP profile %q", name)
+	}
+	deleteSMTPSecret(name)
+	profiles := settings().GetStringMap("smtp")
+	delete(profiles, name)
+	settings().Set("smtp", profiles)
+	unstage(smtpKey(name))
+	return Save()
+}
+
+// deleteSMTPSecret removes an SMTP password from both stores. A missing
+// entry is not an error.
+func deleteSMTPSecret(name string) {
+	if keyring.Supported() {
+		keyring.Delete(smtpSecretKey(name))
+	}
+	secretsFile().Delete(smtpSecretKey(name))
+}
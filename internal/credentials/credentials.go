@@ -0,0 +1,214 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Provider names
+const (
+	ProviderCommand = "command"
+	ProviderVault   = "vault"
+	ProviderAWS     = "aws"
+)
+
+// resolveTimeout bounds how long a provider may take to return the key
+const resolveTimeout = 30 * time.Second
+
+// Spec describes where to fetch the API key from
+type Spec struct {
+	Provider string `mapstructure:"provider"`
+	// Command is run through the shell; its trimmed stdout is the key
+	Command string `mapstructure:"command"`
+	// Addr is the Vault address (default $VAULT_ADDR)
+	Addr string `mapstructure:"addr"`
+	// Path is the Vault secret path, e.g. secret/data/osticket
+	Path string `mapstructure:"path"`
+	// Field selects a key inside a Vault or JSON AWS secret
+	Field string `mapstructure:"field"`
+	// SecretID is the AWS Secrets Manager secret name or ARN
+	SecretID string `mapstructure:"secret_id"`
+	// Region is the AWS region (default from the AWS CLI configuration)
+	Region string `mapstructure:"region"`
+}
+
+// Resolve fetches the API key described by spec
+func Resolve(ctx context.Context, spec Spec) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	var key string
+	var err error
+	switch spec.Provider {
+	case ProviderCommand:
+		key, err = fromCommand(ctx, spec)
+	case ProviderVault:
+		key, err = fromVault(ctx, spec)
+	case ProviderAWS:
+		key, err = fromAWS(ctx, spec)
+	default:
+		return "", fmt.Errorf("unknown credential provider %q", spec.Provider)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s credential provider: %w", spec.Provider, err)
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("%s credential provider returned an empty key", spec.Provider)
+	}
+	return key, nil
+}
+
+// Validate checks that the fields required by the provider are set
+func (s Spec) Validate() error {
+	switch s.Provider {
+	case ProviderCommand:
+		if s.Command == "" {
+			return fmt.Errorf("command provider requires a command")
+		}
+	case ProviderVault:
+		if s.Path == "" {
+			return fmt.Errorf("vault provider requires --path")
+		}
+	case ProviderAWS:
+		if s.SecretID == "" {
+			return fmt.Errorf("aws provider requires --secret-id")
+		}
+	default:
+		return fmt.Errorf("unknown credential provider %q (use command, vault or aws)", s.Provider)
+	}
+	return nil
+}
+
+// fromCommand runs a credential helper such as "pass show osticket" and
+// uses the first line of its output
+func fromCommand(ctx context.Context, spec Spec) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", spec.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", spec.Command)
+	}
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%q failed: %w", spec.Command, err)
+	}
+
+	line, _, _ := strings.Cut(string(out), "\n")
+	return line, nil
+}
+
+// fromVault reads a KV (v1 or v2) secret over the Vault HTTP API using
+// $VAULT_TOKEN or ~/.vault-token
+func fromVault(ctx context.Context, spec Spec) (string, error) {
+	addr := spec.Addr
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if addr == "" {
+		return "", fmt.Errorf("no Vault address: set VAULT_ADDR or --addr")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+	if token == "" {
+		return "", fmt.Errorf("no Vault token: set VAULT_TOKEN or run vault login")
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(spec.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vault returned %s for %s", resp.Status, spec.Path)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse Vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data
+	data := body.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		data = inner
+	}
+
+	field := spec.Field
+	if field == "" {
+		field = "api_key"
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("field %q not found in %s", field, spec.Path)
+	}
+	return value, nil
+}
+
+// fromAWS reads a secret from AWS Secrets Manager through the AWS CLI, so
+// the usual AWS credential chain (profiles, SSO, instance roles) applies
+func fromAWS(ctx context.Context, spec Spec) (string, error) {
+	args := []string{"secretsmanager", "get-secret-value",
+		"--secret-id", spec.SecretID,
+		"--query", "SecretString",
+		"--output", "text",
+	}
+	if spec.Region != "" {
+		args = append(args, "--region", spec.Region)
+	}
+
+	cmd := exec.CommandContext(ctx, "aws", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("aws cli failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	secret := strings.TrimSpace(string(out))
+	if spec.Field == "" {
+		return secret, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not JSON, cannot select field %q", spec.Field)
+	}
+	value, ok := fields[spec.Field].(string)
+	if !ok {
+		return "", fmt.Errorf("field %q not found in secret", spec.Field)
+	}
+	return value, nil
+}
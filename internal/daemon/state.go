@@ -0,0 +1,87 @@
+// Package daemon keeps the state of osticket daemon between polls and
+// runs: the last update of every ticket seen, so a restart only reports
+// what changed meanwhile, and the webhook events not delivered yet.
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaxPending caps the undelivered events kept for retry; the oldest are
+// dropped first when a webhook stays down
+const MaxPending = 1000
+
+// Delivery is a webhook event waiting to be delivered
+type Delivery struct {
+	Body     json.RawMessage `json:"body"`
+	Queued   time.Time       `json:"queued"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// State is what the daemon remembers between runs
+type State struct {
+	// Tickets maps the ID of each ticket matching the filters to its last
+	// update. Nil until the first poll, which only records the queue.
+	Tickets map[int]string `json:"tickets"`
+	Pending []Delivery     `json:"pending,omitempty"`
+	Polled  time.Time      `json:"polled,omitempty"`
+}
+
+// Load reads the state. A missing file is a daemon that never polled.
+func Load(path string) (*State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read daemon state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid daemon state %s: %w", path, err)
+	}
+	// Save indents the bodies with the rest of the file
+	for i, d := range s.Pending {
+		var b bytes.Buffer
+		if err := json.Compact(&b, d.Body); err == nil {
+			s.Pending[i].Body = b.Bytes()
+		}
+	}
+	return &s, nil
+}
+
+// Save writes the state atomically
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Queue adds an undelivered event, dropping the oldest beyond MaxPending.
+// It returns the number of events dropped.
+func (s *State) Queue(d Delivery) int {
+	s.Pending = append(s.Pending, d)
+	dropped := len(s.Pending) - MaxPending
+	if dropped <= 0 {
+		return 0
+	}
+	s.Pending = append([]Delivery(nil), s.Pending[dropped:]...)
+	return dropped
+}
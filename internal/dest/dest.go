@@ -0,0 +1,179 @@
+package dest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Server-side encryption modes for S3
+const (
+	SSEAES256 = "AES256"
+	SSEKMS    = "aws:kms"
+)
+
+// Options control uploads to object storage
+type Options struct {
+	// SSE is the S3 server-side encryption mode (AES256 or aws:kms)
+	SSE string
+	// KMSKey is the KMS key for aws:kms on S3, or the Cloud KMS key
+	// (projects/.../cryptoKeys/...) for GCS
+	KMSKey string
+}
+
+// Validate checks the options against a destination
+func (o Options) Validate(target string) error {
+	switch o.SSE {
+	case "", SSEAES256, SSEKMS:
+	default:
+		return fmt.Errorf("invalid server-side encryption %q: use %s or %s", o.SSE, SSEAES256, SSEKMS)
+	}
+	if o.SSE != "" && !strings.HasPrefix(target, "s3://") {
+		return fmt.Errorf("server-side encryption modes only apply to s3:// destinations")
+	}
+	if o.KMSKey != "" && !IsRemote(target) {
+		return fmt.Errorf("a KMS key only applies to s3:// or gs:// destinations")
+	}
+	return nil
+}
+
+// IsRemote reports whether target is an object storage URL
+func IsRemote(target string) bool {
+	return strings.HasPrefix(target, "s3://") || strings.HasPrefix(target, "gs://")
+}
+
+// Writer writes to a destination. Close completes the write; Abort
+// discards it, leaving any existing destination untouched.
+type Writer interface {
+	io.Writer
+	Close() error
+	Abort()
+}
+
+// Resolve returns the full destination for target. A target ending in "/"
+// is a directory or prefix, and name is appended to it.
+func Resolve(target, name string) string {
+	if strings.HasSuffix(target, "/") {
+		return target + name
+	}
+	return target
+}
+
+// Create opens a destination for writing: a local path, s3://bucket/key or
+// gs://bucket/key. Uploads are streamed through the aws or gcloud CLI, so
+// nothing is staged on local disk and their usual credentials apply. The
+// destination only appears once Close returns without error.
+func Create(ctx context.Context, target string, opts Options) (Writer, error) {
+	if err := opts.Validate(target); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(target, "s3://"):
+		args := []string{"s3", "cp", "--only-show-errors", "-", target}
+		if opts.SSE != "" {
+			args = append(args, "--sse", opts.SSE)
+		}
+		if opts.KMSKey != "" {
+			if opts.SSE == "" {
+				args = append(args, "--sse", SSEKMS)
+			}
+			args = append(args, "--sse-kms-key-id", opts.KMSKey)
+		}
+		return startUpload(ctx, "aws", args)
+	case strings.HasPrefix(target, "gs://"):
+		args := []string{"storage", "cp", "-", target}
+		if opts.KMSKey != "" {
+			args = append(args, "--encryption-key", opts.KMSKey)
+		}
+		return startUpload(ctx, "gcloud", args)
+	}
+	return createFile(target)
+}
+
+// upload streams writes to a CLI's stdin
+type upload struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+}
+
+func startUpload(ctx context.Context, name string, args []string) (*upload, error) {
+	u := &upload{cmd: exec.CommandContext(ctx, name, args...)}
+	u.cmd.Stderr = &u.stderr
+
+	stdin, err := u.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	u.stdin = stdin
+	if err := u.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not run %s: %w", name, err)
+	}
+	return u, nil
+}
+
+func (u *upload) Write(p []byte) (int, error) {
+	return u.stdin.Write(p)
+}
+
+// Close finishes the upload and waits for it to complete
+func (u *upload) Close() error {
+	u.stdin.Close()
+	if err := u.cmd.Wait(); err != nil {
+		msg := strings.TrimSpace(u.stderr.String())
+		if msg == "" {
+			return fmt.Errorf("upload failed: %w", err)
+		}
+		return fmt.Errorf("upload failed: %v: %s", err, msg)
+	}
+	return nil
+}
+
+// Abort stops the upload before it completes
+func (u *upload) Abort() {
+	u.cmd.Process.Kill()
+	u.stdin.Close()
+	u.cmd.Wait()
+}
+
+// file writes to a temporary file renamed into place on Close
+type file struct {
+	*os.File
+	path string
+}
+
+func createFile(path string) (*file, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &file{File: f, path: path}, nil
+}
+
+func (f *file) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (f *file) Abort() {
+	f.File.Close()
+	os.Remove(f.Name())
+}
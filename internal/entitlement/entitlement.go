@@ -0,0 +1,206 @@
+// Package entitlement asks an external command or HTTP endpoint about a
+// requester's support contract when a ticket is created, so the ticket
+// records the support tier and contract expiry and gets the tier's SLA.
+package entitlement
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Hook kinds
+const (
+	KindCommand = "command"
+	KindHTTP    = "http"
+)
+
+// ExpiredTier is the SLAs key used for contracts past their expiry date
+const ExpiredTier = "expired"
+
+// checkTimeout bounds how long a hook may take to answer
+const checkTimeout = 10 * time.Second
+
+// Spec describes the entitlement hook of a profile
+type Spec struct {
+	Kind string `mapstructure:"kind"`
+	// Command is run through the shell (cmd.exe on Windows) with the
+	// email as its last argument and in $OSTICKET_EMAIL; its stdout is the
+	// entitlement JSON
+	Command string `mapstructure:"command"`
+	// URL is fetched with GET and an email query parameter
+	URL string `mapstructure:"url"`
+	// TierField and ExpiryField are the custom form fields that receive
+	// the tier and expiry date (empty skips them)
+	TierField   string `mapstructure:"tier_field"`
+	ExpiryField string `mapstructure:"expiry_field"`
+	// SLAs maps support tiers, lowercase, to SLA IDs or names
+	SLAs map[string]string `mapstructure:"slas"`
+}
+
+// Entitlement is a requester's support contract as the hook reports it:
+//
+//	{"tier": "gold", "expires": "2027-03-31", "fields": {"contract": "C-1042"}}
+//
+// Fields are extra custom form field values to set on the ticket.
+type Entitlement struct {
+	Tier    string                 `json:"tier"`
+	Expires string                 `json:"expires,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Validate checks that the hook's command or URL is set
+func (s Spec) Validate() error {
+	switch s.Kind {
+	case KindCommand:
+		if s.Command == "" {
+			return fmt.Errorf("command hook requires a command")
+		}
+	case KindHTTP:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http hook requires an http or https URL")
+		}
+	default:
+		return fmt.Errorf("unknown entitlement hook %q (use command or http)", s.Kind)
+	}
+	return nil
+}
+
+// Check asks the hook about the requester with the given email. It
+// returns nil without an error when the hook knows no contract: empty
+// output or {} from a command, 404 or 204 from an endpoint.
+func Check(ctx context.Context, spec Spec, email string) (*Entitlement, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var out []byte
+	var err error
+	switch spec.Kind {
+	case KindCommand:
+		out, err = fromCommand(ctx, spec, email)
+	case KindHTTP:
+		out, err = fromHTTP(ctx, spec, email)
+	default:
+		return nil, fmt.Errorf("unknown entitlement hook %q", spec.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("entitlement %s hook: %w", spec.Kind, err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var e Entitlement
+	if err := json.Unmarshal(out, &e); err != nil {
+		return nil, fmt.Errorf("entitlement %s hook: invalid JSON: %w", spec.Kind, err)
+	}
+	if e.Tier == "" && e.Expires == "" && len(e.Fields) == 0 {
+		return nil, nil
+	}
+	if e.Expires != "" {
+		if _, err := expiryDate(e.Expires); err != nil {
+			return nil, fmt.Errorf("entitlement %s hook: invalid expires %q: use YYYY-MM-DD", spec.Kind, e.Expires)
+		}
+	}
+	return &e, nil
+}
+
+// Expired reports whether the contract ended before the given day
+func (e *Entitlement) Expired(now time.Time) bool {
+	if e.Expires == "" {
+		return false
+	}
+	end, err := expiryDate(e.Expires)
+	if err != nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return end.Before(today)
+}
+
+// SLA returns the SLA the spec maps the contract's tier to, or the one
+// for ExpiredTier once the contract has expired; "" when none is mapped
+func (e *Entitlement) SLA(spec Spec, now time.Time) string {
+	if e.Expired(now) {
+		return spec.SLAs[ExpiredTier]
+	}
+	return spec.SLAs[strings.ToLower(e.Tier)]
+}
+
+// Stamp adds the contract to a ticket's custom form fields. Values
+// already in fields, such as those given with --field, are kept.
+func (e *Entitlement) Stamp(spec Spec, fields map[string]interface{}) {
+	set := func(name string, value interface{}) {
+		if _, ok := fields[name]; name != "" && !ok {
+			fields[name] = value
+		}
+	}
+	if e.Tier != "" {
+		set(spec.TierField, e.Tier)
+	}
+	if e.Expires != "" {
+		set(spec.ExpiryField, e.Expires)
+	}
+	for name, value := range e.Fields {
+		set(name, value)
+	}
+}
+
+// expiryDate parses a contract expiry, a date or an RFC 3339 timestamp
+func expiryDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// fromCommand runs the hook command and returns its output
+func fromCommand(ctx context.Context, spec Spec, email string) ([]byte, error) {
+	cmd := hookCommand(ctx, spec.Command, email)
+	cmd.Env = append(os.Environ(), "OSTICKET_EMAIL="+email)
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%q failed: %w", spec.Command, err)
+	}
+	return out, nil
+}
+
+// fromHTTP fetches the hook URL for the email and returns the body
+func fromHTTP(ctx context.Context, spec Spec, email string) ([]byte, error) {
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("email", email)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent:
+		return nil, nil
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("%s returned %s", u.Host, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package entitlement
+
+import (
+	"context"
+	"os/exec"
+)
+
+// hookCommand runs the hook through the shell. As git does for helpers,
+// "$@" passes the email on as an argument without splicing it into the
+// command line.
+func hookCommand(ctx context.Context, command, email string) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", command+` "$@"`, "osticket-entitlement", email)
+}
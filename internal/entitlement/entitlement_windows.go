@@ -0,0 +1,22 @@
+package entitlement
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// hookCommand runs the hook through cmd.exe with the email quoted as its
+// last argument. cmd.exe has its own quoting rules, so the command line
+// is passed as it is; an email with a quote or a percent sign, which
+// cmd.exe would interpret, is only given in %OSTICKET_EMAIL%.
+func hookCommand(ctx context.Context, command, email string) *exec.Cmd {
+	line := command
+	if !strings.ContainsAny(email, `"%`) {
+		line += ` "` + email + `"`
+	}
+	cmd := exec.CommandContext(ctx, "cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + line + `"`}
+	return cmd
+}
@@ -0,0 +1,167 @@
+// Package filter evaluates --filter expressions such as "status_id=1" or
+// "created>2024-01-01" against records, for selections the API cannot
+// make itself.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is one comparison of a record field with a value
+type Expr struct {
+	Field string
+	// Op is one of = != > >= < <= and ~ (contains)
+	Op    string
+	Value string
+}
+
+func (e Expr) String() string { return e.Field + e.Op + e.Value }
+
+// Parse reads an expression of the form field<op>value, e.g.
+// "priority_id>=3", "created<2024-06-01" or "subject~printer"
+func Parse(s string) (Expr, error) {
+	at := strings.IndexAny(s, "=!<>~")
+	op := ""
+	switch {
+	case at < 0:
+	case strings.HasPrefix(s[at:], "!="), strings.HasPrefix(s[at:], ">="), strings.HasPrefix(s[at:], "<="):
+		op = s[at : at+2]
+	case s[at] != '!':
+		op = s[at : at+1]
+	}
+	if op == "" {
+		return Expr{}, fmt.Errorf("invalid filter %q: use field=value, field!=value, field>value, field<value or field~text", s)
+	}
+	field := strings.TrimSpace(s[:at])
+	if field == "" {
+		return Expr{}, fmt.Errorf("invalid filter %q: missing field name", s)
+	}
+	return Expr{Field: field, Op: op, Value: strings.TrimSpace(s[at+len(op):])}, nil
+}
+
+// ParseAll parses each expression
+func ParseAll(exprs []string) ([]Expr, error) {
+	var parsed []Expr
+	for _, s := range exprs {
+		e, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, e)
+	}
+	return parsed, nil
+}
+
+// Match reports whether a record satisfies the expression.
+//
+// Numbers, including numeric strings, compare as numbers. Everything else
+// compares as text, case-insensitively, which orders the API's timestamps
+// ("2006-01-02 15:04:05") by time. A date-only value compares with the
+// date part of a timestamp, so "created>2024-01-01" means created on a
+// later day. A missing or empty field only matches !=.
+func (e Expr) Match(record map[string]interface{}) bool {
+	field, ok := text(record[e.Field])
+	if !ok {
+		return e.Op == "!="
+	}
+	if e.Op == "~" {
+		return strings.Contains(strings.ToLower(field), strings.ToLower(e.Value))
+	}
+
+	var cmp int
+	a, errA := strconv.ParseFloat(field, 64)
+	b, errB := strconv.ParseFloat(e.Value, 64)
+	switch {
+	case errA == nil && errB == nil:
+		cmp = compareFloat(a, b)
+	default:
+		if isDate(e.Value) && len(field) > len(e.Value) && isDate(field[:len(e.Value)]) {
+			field = field[:len(e.Value)]
+		}
+		cmp = strings.Compare(strings.ToLower(field), strings.ToLower(e.Value))
+	}
+
+	switch e.Op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// MatchAll reports whether a record satisfies every expression
+func MatchAll(exprs []Expr, record map[string]interface{}) bool {
+	for _, e := range exprs {
+		if !e.Match(record) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the records that satisfy every expression
+func Apply(exprs []Expr, records []map[string]interface{}) []map[string]interface{} {
+	if len(exprs) == 0 {
+		return records
+	}
+	matched := []map[string]interface{}{}
+	for _, r := range records {
+		if MatchAll(exprs, r) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// text returns a field's value as text; ok is false for missing and empty
+// values, including the API's zero dates
+func text(v interface{}) (s string, ok bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.HasPrefix(s, "0000-00-00") {
+		return "", false
+	}
+	return s, true
+}
+
+// isDate reports whether s looks like a YYYY-MM-DD date
+func isDate(s string) bool {
+	if len(s) != 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	for i, c := range s {
+		if i != 4 && i != 7 && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
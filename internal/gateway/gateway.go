@@ -0,0 +1,297 @@
+// Package gateway serves a small REST API in front of the osTicket API
+// plugin, translating plain resource URLs into its query/condition
+// requests so other tools can use osTicket over HTTP without the Go client.
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// maxBody caps request bodies; the gateway takes no attachments
+const maxBody = 1 << 20
+
+// Server handles the gateway's routes:
+//
+//	GET  /tickets/{id}        a ticket, by number or ID
+//	POST /tickets             create a ticket
+//	POST /tickets/{id}/reply  reply to a ticket
+//	GET  /users?email=|id=    look up a user
+//
+// Every request needs one of Keys, sent as "Authorization: Bearer <key>"
+// or in an X-API-Key header.
+type Server struct {
+	Client osticket.OSTicketAPI
+	Keys   []string
+	// Logger receives one line per request (nil discards them)
+	Logger *slog.Logger
+}
+
+// CreateRequest is the body of POST /tickets. The requester is UserID or
+// else the user with Email; the other IDs default as in ticket create.
+type CreateRequest struct {
+	Title      string                 `json:"title"`
+	Subject    string                 `json:"subject"`
+	UserID     int                    `json:"user_id"`
+	Email      string                 `json:"email"`
+	PriorityID int                    `json:"priority_id"`
+	StatusID   int                    `json:"status_id"`
+	DeptID     int                    `json:"dept_id"`
+	SLAID      int                    `json:"sla_id"`
+	TopicID    int                    `json:"topic_id"`
+	Fields     map[string]interface{} `json:"fields"`
+}
+
+// ReplyRequest is the body of POST /tickets/{id}/reply
+type ReplyRequest struct {
+	Body    string `json:"body"`
+	StaffID int    `json:"staff_id"`
+}
+
+// httpError is a failure answered with its status and message
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string { return e.message }
+
+func badRequest(format string, a ...interface{}) error {
+	return &httpError{http.StatusBadRequest, fmt.Sprintf(format, a...)}
+}
+
+// ServeHTTP authenticates and routes a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		s.logger().Info("gateway request", "method", r.Method, "path", r.URL.Path,
+			"status", rec.status, "elapsed", time.Since(start).Round(time.Millisecond))
+	}()
+
+	if !s.authorized(r) {
+		rec.Header().Set("WWW-Authenticate", `Bearer realm="osticket"`)
+		writeError(rec, &httpError{http.StatusUnauthorized, "missing or invalid API key"})
+		return
+	}
+	r.Body = http.MaxBytesReader(rec, r.Body, maxBody)
+
+	status, value, err := s.route(r)
+	if err != nil {
+		writeError(rec, err)
+		return
+	}
+	writeJSON(rec, status, value)
+}
+
+// route dispatches a request by path and method
+func (s *Server) route(r *http.Request) (int, interface{}, error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	ctx := r.Context()
+	switch {
+	case len(parts) == 1 && parts[0] == "tickets":
+		if err := allow(r, http.MethodPost); err != nil {
+			return 0, nil, err
+		}
+		return s.createTicket(ctx, r.Body)
+	case len(parts) == 2 && parts[0] == "tickets" && parts[1] != "":
+		if err := allow(r, http.MethodGet); err != nil {
+			return 0, nil, err
+		}
+		return s.getTicket(ctx, parts[1])
+	case len(parts) == 3 && parts[0] == "tickets" && parts[2] == "reply":
+		if err := allow(r, http.MethodPost); err != nil {
+			return 0, nil, err
+		}
+		return s.reply(ctx, parts[1], r.Body)
+	case len(parts) == 1 && parts[0] == "users":
+		if err := allow(r, http.MethodGet); err != nil {
+			return 0, nil, err
+		}
+		return s.getUsers(ctx, r.URL.Query().Get("email"), r.URL.Query().Get("id"))
+	}
+	return 0, nil, &httpError{http.StatusNotFound, "no such resource: " + r.URL.Path}
+}
+
+func (s *Server) getTicket(ctx context.Context, id string) (int, interface{}, error) {
+	data, err := s.Client.GetTicket(ctx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data.Tickets) == 0 {
+		return 0, nil, &httpError{http.StatusNotFound, fmt.Sprintf("ticket %s not found", id)}
+	}
+	return http.StatusOK, data.Tickets[0], nil
+}
+
+func (s *Server) createTicket(ctx context.Context, body io.Reader) (int, interface{}, error) {
+	req := CreateRequest{PriorityID: 2, StatusID: 1, DeptID: 1, SLAID: 1, TopicID: 1}
+	if err := decode(body, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Title == "" || req.Subject == "" {
+		return 0, nil, badRequest("title and subject are required")
+	}
+	if req.UserID == 0 {
+		if req.Email == "" {
+			return 0, nil, badRequest("user_id or email is required")
+		}
+		users, err := s.Client.GetUserByEmail(ctx, req.Email)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(users.Users) == 0 {
+			return 0, nil, badRequest("no user with email %s", req.Email)
+		}
+		req.UserID = users.Users[0].UserID
+	}
+
+	ticketID, err := s.Client.CreateTicket(ctx, osticket.CreateTicketParams{
+		Title:      req.Title,
+		Subject:    req.Subject,
+		UserID:     req.UserID,
+		PriorityID: req.PriorityID,
+		StatusID:   req.StatusID,
+		DeptID:     req.DeptID,
+		SLAID:      req.SLAID,
+		TopicID:    req.TopicID,
+		Fields:     req.Fields,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, map[string]int{"ticket_id": ticketID}, nil
+}
+
+func (s *Server) reply(ctx context.Context, id string, body io.Reader) (int, interface{}, error) {
+	ticketID, err := strconv.Atoi(id)
+	if err != nil || ticketID <= 0 {
+		return 0, nil, badRequest("invalid ticket ID %q", id)
+	}
+	var req ReplyRequest
+	if err := decode(body, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Body == "" || req.StaffID == 0 {
+		return 0, nil, badRequest("body and staff_id are required")
+	}
+	if err := s.Client.ReplyToTicket(ctx, ticketID, req.Body, req.StaffID); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, map[string]int{"ticket_id": ticketID}, nil
+}
+
+func (s *Server) getUsers(ctx context.Context, email, id string) (int, interface{}, error) {
+	var data *osticket.UserData
+	var err error
+	switch {
+	case email != "":
+		data, err = s.Client.GetUserByEmail(ctx, email)
+	case id != "":
+		data, err = s.Client.GetUserByID(ctx, id)
+	default:
+		return 0, nil, badRequest("email or id is required: the API cannot list all users")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if data.Users == nil {
+		data.Users = []osticket.User{}
+	}
+	return http.StatusOK, data, nil
+}
+
+// authorized reports whether the request carries one of the keys
+func (s *Server) authorized(r *http.Request) bool {
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return false
+	}
+	ok := false
+	for _, k := range s.Keys {
+		// Compare every key in constant time, not stopping at a match
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+func (s *Server) logger() *slog.Logger {
+	if s.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return s.Logger
+}
+
+// allow refuses methods a route does not handle
+func allow(r *http.Request, method string) error {
+	if r.Method != method {
+		return &httpError{http.StatusMethodNotAllowed, "use " + method}
+	}
+	return nil
+}
+
+// decode reads a JSON body, refusing unknown fields so typos surface
+func decode(body io.Reader, v interface{}) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid JSON: %v", err)
+	}
+	return nil
+}
+
+// statusCode maps an error to the status the gateway answers with:
+// osTicket's validation and not-found errors are the client's, anything
+// else is a failure of the upstream API
+func statusCode(err error) int {
+	var he *httpError
+	var apiErr *osticket.Error
+	switch {
+	case errors.As(err, &he):
+		return he.status
+	case errors.As(err, &apiErr) && apiErr.Kind == osticket.ErrNotFound:
+		return http.StatusNotFound
+	case errors.As(err, &apiErr) && apiErr.Kind == osticket.ErrValidation:
+		return http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusCode(err), map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusRecorder keeps the status written, for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
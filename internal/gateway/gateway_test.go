@@ -0,0 +1,126 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/osticket-cli-go/pkg/osticket/osticketest"
+)
+
+const testKey = "gw-key"
+
+func newServer() (*Server, *osticketest.Fake) {
+	fake := osticketest.NewFake()
+	fake.Users = []osticket.User{{UserID: 12, Name: "Ann Example", Email: "ann@example.com"}}
+	fake.Tickets = []map[string]interface{}{{"ticket_id": 42, "number": "100042", "subject": "Printer on fire", "user_id": 12, "status_id": 1}}
+	return &Server{Client: fake, Keys: []string{testKey}}, fake
+}
+
+// do sends a request with the gateway key and decodes the JSON answer
+func do(t *testing.T, s *Server, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testKey)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	var v map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("%s %s: answer is not JSON: %v\n%s", method, path, err, rec.Body)
+	}
+	return rec.Code, v
+}
+
+func TestUnauthorized(t *testing.T) {
+	s, fake := newServer()
+	for _, key := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/tickets/100042", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("key %q: status %d, want 401 with a challenge", key, rec.Code)
+		}
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("unauthorized requests reached the API: %v", calls)
+	}
+}
+
+func TestGetTicket(t *testing.T) {
+	s, _ := newServer()
+
+	status, v := do(t, s, http.MethodGet, "/tickets/100042", "")
+	if status != http.StatusOK || v["subject"] != "Printer on fire" {
+		t.Errorf("status %d, ticket %v", status, v)
+	}
+
+	status, v = do(t, s, http.MethodGet, "/tickets/999999", "")
+	if status != http.StatusNotFound {
+		t.Errorf("missing ticket: status %d (%v), want 404", status, v)
+	}
+}
+
+func TestCreateTicketByEmail(t *testing.T) {
+	s, fake := newServer()
+
+	status, v := do(t, s, http.MethodPost, "/tickets", `{"title":"VPN drops","subject":"Every hour or so","email":"ann@example.com"}`)
+	if status != http.StatusCreated || v["ticket_id"] != float64(43) {
+		t.Fatalf("status %d, answer %v", status, v)
+	}
+	if len(fake.Tickets) != 2 || fake.Tickets[1]["user_id"] != 12 {
+		t.Errorf("tickets = %v", fake.Tickets)
+	}
+
+	status, _ = do(t, s, http.MethodPost, "/tickets", `{"title":"VPN drops","subject":"Again","email":"nobody@example.com"}`)
+	if status != http.StatusBadRequest {
+		t.Errorf("unknown email: status %d, want 400", status)
+	}
+}
+
+func TestReply(t *testing.T) {
+	s, fake := newServer()
+
+	status, _ := do(t, s, http.MethodPost, "/tickets/42/reply", `{"body":"On our way","staff_id":1}`)
+	if status != http.StatusCreated || len(fake.Threads[42]) != 1 {
+		t.Errorf("status %d, thread %+v", status, fake.Threads[42])
+	}
+
+	status, _ = do(t, s, http.MethodPost, "/tickets/7/reply", `{"body":"Hello","staff_id":1}`)
+	if status != http.StatusNotFound {
+		t.Errorf("missing ticket: status %d, want 404", status)
+	}
+}
+
+func TestRouting(t *testing.T) {
+	s, _ := newServer()
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/tickets", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/nowhere", http.StatusNotFound},
+		{http.MethodGet, "/users", http.StatusBadRequest},
+		{http.MethodGet, "/users?email=ann@example.com", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if status, v := do(t, s, tt.method, tt.path, ""); status != tt.want {
+			t.Errorf("%s %s: status %d (%v), want %d", tt.method, tt.path, status, v, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorStatus(t *testing.T) {
+	s, fake := newServer()
+	fake.Errors = map[string]error{"GetTicket": &osticket.Error{Kind: osticket.ErrAuth, StatusCode: 401, Message: "Valid API key required"}}
+
+	// The gateway's own key was fine; its key for osTicket was not
+	status, _ := do(t, s, http.MethodGet, "/tickets/100042", "")
+	if status != http.StatusBadGateway {
+		t.Errorf("status %d, want 502", status)
+	}
+}
@@ -0,0 +1,130 @@
+// Package handoff renders the shift handoff summary: what came in during
+// a shift and what the next shift has to pick up.
+package handoff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats of the rendered summary
+const (
+	Markdown = "md"
+	Slack    = "slack"
+)
+
+// Ticket is a ticket as listed in the summary
+type Ticket struct {
+	ID       int        `json:"ticket_id"`
+	Number   string     `json:"number"`
+	Subject  string     `json:"subject"`
+	Status   string     `json:"status"`
+	Priority string     `json:"priority,omitempty"`
+	Dept     string     `json:"dept,omitempty"`
+	Assignee string     `json:"assignee,omitempty"`
+	Age      string     `json:"age,omitempty"`
+	Overdue  bool       `json:"overdue,omitempty"`
+	Due      *time.Time `json:"due,omitempty"`
+}
+
+// Summary is the handoff of one shift. Escalations are open tickets
+// osTicket flagged overdue or past their priority's age threshold;
+// emergencies are listed separately.
+type Summary struct {
+	Generated   time.Time `json:"generated"`
+	Since       time.Time `json:"since"`
+	DueBefore   time.Time `json:"due_before"`
+	New         []Ticket  `json:"new"`
+	Escalations []Ticket  `json:"escalations"`
+	Emergencies []Ticket  `json:"emergencies"`
+	DueSoon     []Ticket  `json:"due_soon"`
+}
+
+// section is a titled list of tickets
+type section struct {
+	title   string
+	tickets []Ticket
+}
+
+// Write renders the summary as Markdown, or as Slack mrkdwn, ready to
+// paste into a team channel
+func Write(w io.Writer, format string, s *Summary) error {
+	sections := []section{
+		{"New tickets", s.New},
+		{"Escalations", s.Escalations},
+		{"Open emergencies", s.Emergencies},
+		{"Due soon", s.DueSoon},
+	}
+	shift := fmt.Sprintf("%s – %s", s.Since.Format("Jan 2 15:04"), s.Generated.Format("Jan 2 15:04"))
+	counts := fmt.Sprintf("%d new · %d escalated · %d open emergencies · %d due soon",
+		len(s.New), len(s.Escalations), len(s.Emergencies), len(s.DueSoon))
+
+	switch format {
+	case Markdown:
+		fmt.Fprintf(w, "## Shift handoff (%s)\n\n%s\n", shift, counts)
+		for _, sec := range sections {
+			fmt.Fprintf(w, "\n### %s (%d)\n\n", sec.title, len(sec.tickets))
+			if len(sec.tickets) == 0 {
+				fmt.Fprintln(w, "_None_")
+			}
+			for _, t := range sec.tickets {
+				fmt.Fprintf(w, "- **#%s** %s — %s\n", t.Number, escapeMarkdown(t.Subject), escapeMarkdown(details(t, s.Generated)))
+			}
+		}
+	case Slack:
+		fmt.Fprintf(w, "*Shift handoff* (%s)\n%s\n", shift, counts)
+		for _, sec := range sections {
+			fmt.Fprintf(w, "\n*%s (%d)*\n", sec.title, len(sec.tickets))
+			if len(sec.tickets) == 0 {
+				fmt.Fprintln(w, "_None_")
+			}
+			for _, t := range sec.tickets {
+				fmt.Fprintf(w, "• *#%s* %s — %s\n", t.Number, escapeSlack(t.Subject), escapeSlack(details(t, s.Generated)))
+			}
+		}
+	default:
+		return fmt.Errorf("invalid format %q: use %s or %s", format, Markdown, Slack)
+	}
+	return nil
+}
+
+// details describes a ticket after its subject, e.g. "Emergency, Support,
+// jdoe, age 3h10m, overdue"
+func details(t Ticket, now time.Time) string {
+	var parts []string
+	for _, p := range []string{t.Priority, t.Dept} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if t.Assignee != "" {
+		parts = append(parts, t.Assignee)
+	} else {
+		parts = append(parts, "unassigned")
+	}
+	parts = append(parts, t.Status)
+	if t.Age != "" {
+		parts = append(parts, "age "+t.Age)
+	}
+	if t.Overdue {
+		parts = append(parts, "overdue")
+	}
+	if t.Due != nil {
+		layout := "15:04"
+		if t.Due.YearDay() != now.YearDay() || t.Due.Year() != now.Year() {
+			layout = "Jan 2 15:04"
+		}
+		parts = append(parts, "due "+t.Due.Format(layout))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escapeMarkdown keeps ticket text from being read as Markdown markup
+var escapeMarkdown = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+).Replace
+
+// escapeSlack escapes the characters Slack reads as markup
+var escapeSlack = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
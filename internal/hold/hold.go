@@ -0,0 +1,133 @@
+package hold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of legal hold
+const (
+	Ticket = "ticket"
+	User   = "user"
+)
+
+// Hold keeps a ticket, or every ticket of a user, from being archived,
+// deleted or anonymized. Ticket holds name a ticket ID or number; user
+// holds name an email and keep the user's ID, as tickets carry only that.
+type Hold struct {
+	Kind    string    `json:"kind"`
+	Value   string    `json:"value"`
+	UserID  int       `json:"user_id,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Added   time.Time `json:"added"`
+	AddedBy string    `json:"added_by"`
+}
+
+// String describes the hold, e.g. "user jane@example.com"
+func (h Hold) String() string {
+	return h.Kind + " " + h.Value
+}
+
+// List is the set of holds of a profile
+type List struct {
+	Holds []Hold `json:"holds"`
+}
+
+// Load reads a hold list. A missing file is an empty list.
+func Load(path string) (*List, error) {
+	var l List
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read legal holds: %w", err)
+	}
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid legal hold list %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// Save writes the list atomically
+func (l *List) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Add adds a hold, returning false when an equal hold exists
+func (l *List) Add(h Hold) bool {
+	if l.find(h.Kind, h.Value) >= 0 {
+		return false
+	}
+	l.Holds = append(l.Holds, h)
+	return true
+}
+
+// Remove releases a hold, returning false when there is none
+func (l *List) Remove(kind, value string) bool {
+	i := l.find(kind, value)
+	if i < 0 {
+		return false
+	}
+	l.Holds = append(l.Holds[:i], l.Holds[i+1:]...)
+	return true
+}
+
+func (l *List) find(kind, value string) int {
+	for i, h := range l.Holds {
+		if h.Kind == kind && equal(kind, h.Value, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Target is the part of a ticket holds look at
+type Target struct {
+	TicketID int
+	Number   string
+	UserID   int
+}
+
+// Match returns the hold covering t
+func (l *List) Match(t Target) (Hold, bool) {
+	for _, h := range l.Holds {
+		switch h.Kind {
+		case Ticket:
+			if h.Value == strconv.Itoa(t.TicketID) || t.Number != "" && h.Value == t.Number {
+				return h, true
+			}
+		case User:
+			if h.UserID != 0 && h.UserID == t.UserID {
+				return h, true
+			}
+		}
+	}
+	return Hold{}, false
+}
+
+// equal compares hold values; email addresses ignore case
+func equal(kind, a, b string) bool {
+	if kind == User {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
@@ -0,0 +1,145 @@
+package ignore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rules describe tickets that watch, notify and webhook modes should not
+// report, e.g. test tickets or monitoring self-tests
+type Rules struct {
+	Numbers     []string `mapstructure:"numbers" json:"numbers"`
+	Subjects    []string `mapstructure:"subjects" json:"subjects"`
+	Senders     []string `mapstructure:"senders" json:"senders"`
+	Departments []int    `mapstructure:"departments" json:"departments"`
+}
+
+// Empty reports whether there are no rules
+func (r Rules) Empty() bool {
+	return len(r.Numbers) == 0 && len(r.Subjects) == 0 && len(r.Senders) == 0 && len(r.Departments) == 0
+}
+
+// Add merges other into r, skipping rules r already has
+func (r *Rules) Add(other Rules) {
+	r.Numbers = appendNew(r.Numbers, other.Numbers...)
+	r.Subjects = appendNew(r.Subjects, other.Subjects...)
+	r.Senders = appendNew(r.Senders, other.Senders...)
+	for _, d := range other.Departments {
+		if !containsInt(r.Departments, d) {
+			r.Departments = append(r.Departments, d)
+		}
+	}
+}
+
+// Remove deletes the rules in other from r and returns how many were found
+func (r *Rules) Remove(other Rules) int {
+	removed := 0
+	r.Numbers, removed = without(r.Numbers, other.Numbers, removed)
+	r.Subjects, removed = without(r.Subjects, other.Subjects, removed)
+	r.Senders, removed = without(r.Senders, other.Senders, removed)
+
+	var depts []int
+	for _, d := range r.Departments {
+		if containsInt(other.Departments, d) {
+			removed++
+			continue
+		}
+		depts = append(depts, d)
+	}
+	r.Departments = depts
+	return removed
+}
+
+// Ticket is the part of a ticket the rules look at
+type Ticket struct {
+	Number  string
+	Subject string
+	Sender  string
+	DeptID  int
+}
+
+// Matcher checks tickets against compiled rules
+type Matcher struct {
+	rules    Rules
+	subjects []*regexp.Regexp
+}
+
+// Compile checks the rules and prepares them for matching
+func (r Rules) Compile() (*Matcher, error) {
+	m := &Matcher{rules: r}
+	for _, pattern := range r.Subjects {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subject pattern %q: %w", pattern, err)
+		}
+		m.subjects = append(m.subjects, re)
+	}
+	return m, nil
+}
+
+// Match returns the rule that ignores t, e.g. "dept 4", or false when no
+// rule applies. Senders match an email address or, when the rule starts
+// with "@", a whole domain.
+func (m *Matcher) Match(t Ticket) (string, bool) {
+	for _, n := range m.rules.Numbers {
+		if t.Number != "" && t.Number == n {
+			return "number " + n, true
+		}
+	}
+	for _, re := range m.subjects {
+		if re.MatchString(t.Subject) {
+			return "subject " + re.String(), true
+		}
+	}
+	sender := strings.ToLower(t.Sender)
+	for _, s := range m.rules.Senders {
+		s = strings.ToLower(s)
+		if sender != "" && (sender == s || strings.HasPrefix(s, "@") && strings.HasSuffix(sender, s)) {
+			return "sender " + s, true
+		}
+	}
+	if containsInt(m.rules.Departments, t.DeptID) {
+		return fmt.Sprintf("dept %d", t.DeptID), true
+	}
+	return "", false
+}
+
+func appendNew(list []string, values ...string) []string {
+	for _, v := range values {
+		if !contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func without(list, drop []string, removed int) ([]string, int) {
+	var kept []string
+	for _, v := range list {
+		if contains(drop, v) {
+			removed++
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept, removed
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
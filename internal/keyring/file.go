@@ -0,0 +1,148 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// File stores secrets encrypted with AES-256-GCM, for systems without a
+// keyring. The encryption key lives in a separate file (KeyPath), so a
+// copied, shared or committed secrets file does not expose the secrets.
+// It does not protect against someone who can read both files.
+type File struct {
+	Path    string
+	KeyPath string
+}
+
+// Get decrypts a secret from the file
+func (f *File) Get(key string) (string, error) {
+	entries, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	sealed, ok := entries[target(key)]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("corrupt entry in %s: %w", f.Path, err)
+	}
+	gcm, err := f.cipher(false)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("corrupt entry in %s", f.Path)
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, []byte(target(key)))
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt %s with %s: %w", f.Path, f.KeyPath, err)
+	}
+	return string(plain), nil
+}
+
+// Set encrypts a secret into the file, creating the encryption key on
+// first use
+func (f *File) Set(key, secret string) error {
+	entries, err := f.read()
+	if err != nil {
+		return err
+	}
+	gcm, err := f.cipher(true)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), []byte(target(key)))
+	entries[target(key)] = base64.StdEncoding.EncodeToString(sealed)
+	return f.write(entries)
+}
+
+// Delete removes a secret from the file
+func (f *File) Delete(key string) error {
+	entries, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[target(key)]; !ok {
+		return ErrNotFound
+	}
+	delete(entries, target(key))
+	return f.write(entries)
+}
+
+func (f *File) read() (map[string]string, error) {
+	entries := map[string]string{}
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read secrets: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", f.Path, err)
+	}
+	return entries, nil
+}
+
+func (f *File) write(entries map[string]string) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(f.Path, data)
+}
+
+// cipher loads the encryption key, generating it when create is set and
+// none exists yet
+func (f *File) cipher(create bool) (cipher.AEAD, error) {
+	key, err := os.ReadFile(f.KeyPath)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := writePrivate(f.KeyPath, key); err != nil {
+			return nil, fmt.Errorf("could not save encryption key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid encryption key in %s", f.KeyPath)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writePrivate writes a file readable only by the owner, atomically
+func writePrivate(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,65 @@
+package keyring
+
+import (
+	"errors"
+	"sync"
+
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// service prefixes every credential target so entries are easy to find
+// in the OS credential store
+const service = "osticket-cli"
+
+// probeKey is looked up to find out whether a keyring answers
+const probeKey = "probe"
+
+// ErrNotFound is returned when no credential exists for a key
+var ErrNotFound = errors.New("credential not found in keyring")
+
+// ErrUnsupported is returned when no keyring backend is available
+var ErrUnsupported = errors.New("no OS keyring available")
+
+// Get reads a secret from the OS credential store
+func Get(key string) (string, error) {
+	secret, err := gokeyring.Get(service, key)
+	return secret, translate(err)
+}
+
+// Set stores a secret in the OS credential store
+func Set(key, secret string) error {
+	return translate(gokeyring.Set(service, key, secret))
+}
+
+// Delete removes a secret from the OS credential store
+func Delete(key string) error {
+	return translate(gokeyring.Delete(service, key))
+}
+
+var supported = sync.OnceValue(func() bool {
+	_, err := gokeyring.Get(service, probeKey)
+	return err == nil || errors.Is(err, gokeyring.ErrNotFound)
+})
+
+// Supported reports whether a keyring backend answers: Windows Credential
+// Manager, the macOS keychain, or the Secret Service (GNOME Keyring,
+// KWallet) on Linux. The first call looks up an entry to find out.
+func Supported() bool {
+	return supported()
+}
+
+// translate maps go-keyring's errors to this package's
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gokeyring.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, gokeyring.ErrUnsupportedPlatform):
+		return ErrUnsupported
+	}
+	return err
+}
+
+// target names a key in the encrypted secrets file
+func target(key string) string {
+	return service + ":" + key
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transport logs every request and response passing through it at debug
+// level: method, URL, headers, body, status and timing. Headers listed in
+// Redact are logged with their value hidden.
+type Transport struct {
+	// Base performs the requests; nil uses http.DefaultTransport
+	Base   http.RoundTripper
+	Log    *slog.Logger
+	Redact []string
+}
+
+// printf logs a debug line
+func (t *Transport) printf(format string, args ...interface{}) {
+	t.Log.Debug(fmt.Sprintf(format, args...))
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.printf("--> %s %s", req.Method, req.URL)
+	t.logHeaders(req.Header)
+	t.logBody(reqBody)
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.printf("<-- %s %s failed after %s: %v", req.Method, req.URL, elapsed, err)
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.printf("<-- %s (%s)", resp.Status, elapsed)
+	t.logHeaders(resp.Header)
+	t.logBody(respBody)
+	return resp, nil
+}
+
+func (t *Transport) logHeaders(h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if t.redacted(name) {
+			value = "[redacted]"
+		}
+		t.printf("    %s: %s", name, value)
+	}
+}
+
+func (t *Transport) logBody(body []byte) {
+	if len(body) > 0 {
+		t.printf("    %s", body)
+	}
+}
+
+func (t *Transport) redacted(name string) bool {
+	for _, r := range t.Redact {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody reads a request or response body and replaces it with a copy,
+// so it can still be sent or read by the caller
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
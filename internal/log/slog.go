@@ -0,0 +1,152 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ParseLevel parses a log level name: debug, info, warn or error
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q: use debug, info, warn or error", s)
+}
+
+// Options configure Open
+type Options struct {
+	Level slog.Level
+	// File receives the log as JSON lines when set; otherwise records go
+	// to Console as plain diagnostic lines
+	File    string
+	Console io.Writer
+	// Label returns the prefix of a console line, e.g. "Warning:"
+	Label func(slog.Level) string
+}
+
+// Open creates the logger diagnostics go through. A log file is opened
+// for appending and created with owner-only permissions.
+func Open(o Options) (*slog.Logger, error) {
+	if o.File == "" {
+		return slog.New(NewConsoleHandler(o.Console, o.Level, o.Label)), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(o.File), 0700); err != nil {
+		return nil, fmt.Errorf("could not create log directory: %w", err)
+	}
+	f, err := os.OpenFile(o.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log file: %w", err)
+	}
+	return slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: o.Level})), nil
+}
+
+// Discard returns a logger that drops every record
+func Discard() *slog.Logger {
+	return slog.New(NewConsoleHandler(io.Discard, slog.LevelError+1, nil))
+}
+
+// DefaultLabel prefixes console lines with the level as the CLI has
+// always shown it: "[debug]", nothing for info, "Warning:" and "Error:"
+func DefaultLabel(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "[debug]"
+	case level < slog.LevelWarn:
+		return ""
+	case level < slog.LevelError:
+		return "Warning:"
+	}
+	return "Error:"
+}
+
+// ConsoleHandler writes records as single diagnostic lines, e.g.
+// "Warning: slow API call elapsed=3s", for people reading stderr
+type ConsoleHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	label func(slog.Level) string
+	attrs string
+}
+
+// NewConsoleHandler creates a handler writing records at or above level
+// to w. label may be nil for DefaultLabel.
+func NewConsoleHandler(w io.Writer, level slog.Leveler, label func(slog.Level) string) *ConsoleHandler {
+	if label == nil {
+		label = DefaultLabel
+	}
+	return &ConsoleHandler{mu: &sync.Mutex{}, w: w, level: level, label: label}
+}
+
+// Enabled implements slog.Handler
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if label := h.label(r.Level); label != "" {
+		b.WriteString(label + " ")
+	}
+	b.WriteString(strings.TrimSuffix(r.Message, "\n"))
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		b.WriteString(formatAttr("", a))
+		return true
+	})
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	for _, a := range attrs {
+		h2.attrs += formatAttr("", a)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler. Groups are not shown on the console.
+func (h *ConsoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// formatAttr formats an attribute as " key=value", quoting values with
+// spaces
+func formatAttr(prefix string, a slog.Attr) string {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return ""
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		var b strings.Builder
+		for _, ga := range a.Value.Group() {
+			b.WriteString(formatAttr(prefix+a.Key+".", ga))
+		}
+		return b.String()
+	}
+	value := a.Value.String()
+	if strings.ContainsAny(value, " \t\"") || value == "" {
+		value = fmt.Sprintf("%q", value)
+	}
+	return " " + prefix + a.Key + "=" + value
+}
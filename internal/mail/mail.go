@@ -0,0 +1,171 @@
+// Package mail sends HTML email through an SMTP server.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport security of an SMTP connection
+const (
+	StartTLS = "starttls"
+	TLS      = "tls"
+	None     = "none"
+)
+
+// Server is an SMTP server and the account mail is sent with
+type Server struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// Security is starttls (the default), tls for implicit TLS (usually
+	// port 465) or none
+	Security string
+}
+
+// Validate checks that the server has a host, a valid port and sender
+// address and a known security mode
+func (s Server) Validate() error {
+	if s.Host == "" {
+		return fmt.Errorf("an SMTP host is required")
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("invalid SMTP port %d", s.Port)
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.From, err)
+	}
+	switch s.Security {
+	case "", StartTLS, TLS, None:
+	default:
+		return fmt.Errorf("invalid SMTP security %q: use %s, %s or %s", s.Security, StartTLS, TLS, None)
+	}
+	return nil
+}
+
+// Message is an HTML email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Send delivers a message. Credentials are only sent over TLS or to a
+// server on localhost.
+func (s Server) Send(ctx context.Context, msg Message) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	if s.Security == TLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("could not connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake with %s failed: %w", addr, err)
+	}
+	defer c.Close()
+
+	if s.Security == "" || s.Security == StartTLS {
+		if err := c.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return fmt.Errorf("STARTTLS with %s failed: %w", addr, err)
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("SMTP login as %s failed: %w", s.Username, err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("recipient %s refused: %w", to.Address, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(Build(s.From, msg, time.Now())); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Build formats a message as MIME with a base64 HTML body
+func Build(from string, msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+	}
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/html; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "base64")
+	b.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(msg.HTML))
+	for len(body) > 76 {
+		b.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	b.WriteString(body + "\r\n")
+	return b.Bytes()
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
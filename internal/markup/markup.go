@@ -0,0 +1,59 @@
+// Package markup converts ticket bodies between the formats people write
+// in and the HTML osTicket stores: Markdown and raw HTML on the way in,
+// readable terminal text on the way out.
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Body formats
+const (
+	Text     = "text"
+	Markdown = "markdown"
+	HTML     = "html"
+)
+
+// Formats are the body formats, in the order help text lists them
+var Formats = []string{Text, Markdown, HTML}
+
+// markdown renders GitHub-flavored Markdown; raw HTML in it is kept, to be
+// sanitized with the rest
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(html.WithHardWraps(), html.WithUnsafe()),
+)
+
+// policy allows the formatting a ticket body can use and drops scripts,
+// styles, event handlers and the like
+var policy = bluemonday.UGCPolicy()
+
+// Convert returns a body written in format as osTicket should receive it.
+// Text is sent as it is; Markdown becomes HTML, and HTML is sanitized.
+func Convert(body, format string) (string, error) {
+	switch format {
+	case Text, "":
+		return body, nil
+	case Markdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(body), &buf); err != nil {
+			return "", fmt.Errorf("invalid Markdown: %w", err)
+		}
+		return Sanitize(buf.String()), nil
+	case HTML:
+		return Sanitize(body), nil
+	}
+	return "", fmt.Errorf("unknown body format %q: use %s", format, strings.Join(Formats, ", "))
+}
+
+// Sanitize removes what a ticket body must not contain from HTML
+func Sanitize(s string) string {
+	return strings.TrimSpace(policy.Sanitize(s))
+}
@@ -0,0 +1,225 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// looksHTML matches bodies that contain markup
+var looksHTML = regexp.MustCompile(`(?i)<(p|br|div|span|b|i|strong|em|a|ul|ol|li|table|tr|td|h[1-6]|blockquote|pre|img|font)\b[^>]*>`)
+
+// IsHTML reports whether a body appears to be HTML, for entries whose
+// format the server does not say
+func IsHTML(s string) bool {
+	return looksHTML.MatchString(s)
+}
+
+// ToText renders an HTML body as plain text for a terminal: paragraphs
+// and line breaks become newlines, list items get bullets or numbers,
+// links show their address, quotes are prefixed with "> ", and scripts,
+// styles and comments are dropped.
+func ToText(s string) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	t := &textWriter{}
+	t.node(doc)
+	return t.String()
+}
+
+// textWriter builds the text of a document
+type textWriter struct {
+	b strings.Builder
+	// quote is the blockquote depth; pre is set inside <pre>
+	quote int
+	pre   bool
+	// space is set when the source had whitespace before the next text
+	space bool
+	// lists holds the next number of each open list, 0 for bullets
+	lists []int
+}
+
+// blockElements end the line before and after them
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Ul: true, atom.Ol: true,
+	atom.Table: true, atom.Tr: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Hr: true, atom.Section: true, atom.Article: true, atom.Header: true,
+	atom.Footer: true,
+}
+
+func (t *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		t.text(n.Data)
+		return
+	case html.CommentNode:
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Script, atom.Style, atom.Head, atom.Title:
+			return
+		case atom.Br:
+			t.newline()
+			return
+		case atom.Hr:
+			t.paragraph()
+			t.write("----")
+			t.paragraph()
+			return
+		case atom.Img:
+			if alt := attr(n, "alt"); alt != "" {
+				t.write("[" + alt + "]")
+			}
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		t.paragraph()
+	}
+	switch n.DataAtom {
+	case atom.Blockquote:
+		t.quote++
+		defer func() { t.quote-- }()
+	case atom.Pre:
+		t.pre = true
+		defer func() { t.pre = false }()
+	case atom.Ul:
+		t.lists = append(t.lists, 0)
+		defer func() { t.lists = t.lists[:len(t.lists)-1] }()
+	case atom.Ol:
+		t.lists = append(t.lists, 1)
+		defer func() { t.lists = t.lists[:len(t.lists)-1] }()
+	case atom.Li:
+		t.newline()
+		t.write(strings.Repeat("  ", max(len(t.lists)-1, 0)))
+		if depth := len(t.lists); depth > 0 && t.lists[depth-1] > 0 {
+			t.write(strconv.Itoa(t.lists[depth-1]) + ". ")
+			t.lists[depth-1]++
+		} else {
+			t.write("- ")
+		}
+	case atom.Td, atom.Th:
+		if n.PrevSibling != nil {
+			t.write(" | ")
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		t.node(c)
+	}
+
+	if n.DataAtom == atom.A {
+		if href := attr(n, "href"); href != "" && !strings.HasPrefix(href, "#") && href != textOf(n) {
+			t.write(" (" + strings.TrimPrefix(href, "mailto:") + ")")
+		}
+	}
+	if block {
+		t.paragraph()
+	}
+}
+
+// text writes a text node, collapsing whitespace outside <pre>
+func (t *textWriter) text(s string) {
+	if t.pre {
+		for i, line := range strings.Split(s, "\n") {
+			if i > 0 {
+				t.newline()
+			}
+			t.write(line)
+		}
+		return
+	}
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		t.space = t.space || s != ""
+		return
+	}
+	t.space = t.space || unicode.IsSpace(rune(s[0]))
+	t.write(strings.Join(words, " "))
+	t.space = unicode.IsSpace(rune(s[len(s)-1]))
+}
+
+// write adds text, starting a line with the quote prefix as needed
+func (t *textWriter) write(s string) {
+	if s == "" {
+		return
+	}
+	lineStart := t.b.Len() == 0 || strings.HasSuffix(t.b.String(), "\n")
+	switch {
+	case lineStart && t.quote > 0:
+		t.b.WriteString(strings.Repeat("> ", t.quote))
+	case !lineStart && t.space:
+		t.b.WriteByte(' ')
+	}
+	t.space = false
+	t.b.WriteString(s)
+}
+
+// newline ends the current line
+func (t *textWriter) newline() {
+	t.b.WriteByte('\n')
+	t.space = false
+}
+
+// paragraph leaves one blank line before what comes next
+func (t *textWriter) paragraph() {
+	t.space = false
+	cur := t.b.String()
+	switch {
+	case cur == "", strings.HasSuffix(cur, "\n\n"):
+	case strings.HasSuffix(cur, "\n"):
+		t.b.WriteByte('\n')
+	default:
+		t.b.WriteString("\n\n")
+	}
+}
+
+// String returns the text with runs of blank lines collapsed
+func (t *textWriter) String() string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(t.b.String(), "\n") {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+			lines = append(lines, "")
+			continue
+		}
+		blank = false
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textOf returns the text inside a node
+func textOf(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textOf(c))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,228 @@
+// Package metrics keeps counters, gauges and histograms and serves them in
+// the Prometheus text exposition format, so scrapers can alert on them
+// without the CLI depending on a metrics client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of latency histograms
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metric families and writes them in registration order.
+// It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a metric and its series, one per combination of label values
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series is the value of a family for one combination of label values
+type series struct {
+	values []string
+	value  float64
+	// counts, sum and count are those of histograms; counts[i] is the
+	// number of observations up to buckets[i]
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (r *Registry) add(name, help, kind string, buckets []float64, labels []string) *family {
+	f := &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: map[string]*series{}}
+	r.mu.Lock()
+	r.families = append(r.families, f)
+	r.mu.Unlock()
+	return f
+}
+
+// get returns the series for the label values, creating it. The caller
+// holds the registry's lock.
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter is a value that only goes up, such as a number of errors
+type Counter struct {
+	r *Registry
+	f *family
+}
+
+// Counter registers a counter with the given label names
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r: r, f: r.add(name, help, "counter", nil, labels)}
+}
+
+// Inc adds one to the counter of the label values
+func (c *Counter) Inc(values ...string) {
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	c.f.get(values).value++
+}
+
+// Gauge is a value that goes up and down, such as a queue length
+type Gauge struct {
+	r *Registry
+	f *family
+}
+
+// Gauge registers a gauge with the given label names
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r: r, f: r.add(name, help, "gauge", nil, labels)}
+}
+
+// Set sets the gauge of the label values
+func (g *Gauge) Set(v float64, values ...string) {
+	g.r.mu.Lock()
+	defer g.r.mu.Unlock()
+	g.f.get(values).value = v
+}
+
+// Reset removes every series, for gauges whose label values come and go
+func (g *Gauge) Reset() {
+	g.r.mu.Lock()
+	defer g.r.mu.Unlock()
+	g.f.series = map[string]*series{}
+}
+
+// Histogram counts observations, such as latencies, in buckets
+type Histogram struct {
+	r *Registry
+	f *family
+}
+
+// Histogram registers a histogram with the given bucket upper bounds, in
+// increasing order, and label names
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{r: r, f: r.add(name, help, "histogram", buckets, labels)}
+}
+
+// Observe records a value for the label values
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.f.get(values)
+	for i, bound := range h.f.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// Write writes every metric in the text exposition format, series sorted
+// by label values
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	for _, f := range r.families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := f.series[k]
+			if f.kind != "histogram" {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatFloat(s.value))
+				continue
+			}
+			for i, bound := range f.buckets {
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, labelSet(f.labels, s.values, "le", formatFloat(bound)), s.counts[i])
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, labelSet(f.labels, s.values, "le", "+Inf"), s.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatFloat(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", f.name, labelSet(f.labels, s.values, "", ""), s.count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry's metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		r.Write(w)
+	})
+}
+
+// labelSet formats {name="value",...}, with an extra label when extraName
+// is set, or nothing without labels
+func labelSet(names, values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
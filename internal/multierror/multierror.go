@@ -0,0 +1,202 @@
+// Package multierror collects the per-item failures of a bulk job, so the
+// job can carry on past them (or stop at the first, with fail-fast) and
+// report them together at the end.
+package multierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// Failure is one item of a job that failed
+type Failure struct {
+	// Item identifies the item, e.g. a ticket number
+	Item string
+	// Op is what was being done to it, e.g. "archive"; optional
+	Op  string
+	Err error
+}
+
+func (f Failure) Error() string {
+	if f.Op != "" {
+		return fmt.Sprintf("%s (%s): %v", f.Item, f.Op, f.Err)
+	}
+	return fmt.Sprintf("%s: %v", f.Item, f.Err)
+}
+
+func (f Failure) Unwrap() error { return f.Err }
+
+// MarshalJSON writes the failure with its error as a message, and the
+// kind ("not_found", "network", ...) and HTTP status when known
+func (f Failure) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Item   string `json:"item"`
+		Op     string `json:"op,omitempty"`
+		Error  string `json:"error"`
+		Kind   string `json:"kind,omitempty"`
+		Status int    `json:"status,omitempty"`
+	}{Item: f.Item, Op: f.Op, Error: f.Err.Error()}
+
+	var apiErr *osticket.Error
+	switch {
+	case errors.As(f.Err, &apiErr):
+		v.Kind, v.Status = apiErr.Kind.String(), apiErr.StatusCode
+	case osticket.IsNetworkError(f.Err):
+		v.Kind = "network"
+	}
+	return json.Marshal(v)
+}
+
+// Errors collects the failures of a job. It is safe for concurrent use.
+type Errors struct {
+	// What names the job's items, e.g. "ticket"
+	What string
+	// FailFast makes Add ask the job to stop at the first failure
+	FailFast bool
+	// Total is the number of items in the job, when known
+	Total int
+
+	mu       sync.Mutex
+	failures []Failure
+	skipped  int
+}
+
+// New creates a collector for a job's items
+func New(what string, failFast bool) *Errors {
+	return &Errors{What: what, FailFast: failFast}
+}
+
+// Add records a failed item and reports whether the job should stop
+func (e *Errors) Add(item, op string, err error) (stop bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, Failure{Item: item, Op: op, Err: err})
+	return e.FailFast
+}
+
+// Skip records items the job did not attempt after stopping
+func (e *Errors) Skip(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.skipped += n
+}
+
+// Len returns the number of failures
+func (e *Errors) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.failures)
+}
+
+// Failures returns the failures in the order they were added
+func (e *Errors) Failures() []Failure {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Failure(nil), e.failures...)
+}
+
+// Err returns e, or nil when nothing failed
+func (e *Errors) Err() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error summarizes the failures, naming the first
+func (e *Errors) Error() string {
+	failures := e.Failures()
+	if len(failures) == 0 {
+		return "no failures"
+	}
+	msg := e.Summary()
+	if len(failures) == 1 {
+		return fmt.Sprintf("%s %s", e.What, failures[0].Error())
+	}
+	return fmt.Sprintf("%s; first: %s %s", msg, e.What, failures[0].Error())
+}
+
+// Unwrap returns the failures' errors, so errors.Is and errors.As see
+// them
+func (e *Errors) Unwrap() []error {
+	var errs []error
+	for _, f := range e.Failures() {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// Summary counts the failures, e.g. "2 of 10 ticket(s) failed"
+func (e *Errors) Summary() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	msg := fmt.Sprintf("%d %s(s) failed", len(e.failures), e.What)
+	if e.Total > 0 {
+		msg = fmt.Sprintf("%d of %d %s(s) failed", len(e.failures), e.Total, e.What)
+	}
+	if e.skipped > 0 {
+		msg += fmt.Sprintf(", %d not attempted", e.skipped)
+	}
+	return msg
+}
+
+// MarshalJSON writes the counts and the failures, e.g.
+//
+//	{"item_kind": "ticket", "failed": 1, "total": 3,
+//	 "errors": [{"item": "100042", "error": "...", "kind": "not_found"}]}
+func (e *Errors) MarshalJSON() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	failures := e.failures
+	if failures == nil {
+		failures = []Failure{}
+	}
+	return json.Marshal(struct {
+		What     string    `json:"item_kind"`
+		Failed   int       `json:"failed"`
+		Total    int       `json:"total,omitempty"`
+		Skipped  int       `json:"not_attempted,omitempty"`
+		FailFast bool      `json:"fail_fast,omitempty"`
+		Errors   []Failure `json:"errors"`
+	}{e.What, len(failures), e.Total, e.skipped, e.FailFast, failures})
+}
+
+// WriteTable renders the failures as a table followed by the summary
+func (e *Errors) WriteTable(w io.Writer) {
+	failures := e.Failures()
+	withOp := false
+	for _, f := range failures {
+		withOp = withOp || f.Op != ""
+	}
+
+	header := []string{strings.ToUpper(e.What[:1]) + e.What[1:]}
+	if withOp {
+		header = append(header, "Action")
+	}
+	header = append(header, "Error")
+	colors := make([]tablewriter.Colors, len(header))
+	for i := range colors {
+		colors[i] = tablewriter.Colors{tablewriter.FgRedColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetHeaderColor(colors...)
+	table.SetColWidth(60)
+	for _, f := range failures {
+		row := []string{f.Item}
+		if withOp {
+			row = append(row, f.Op)
+		}
+		table.Append(append(row, f.Err.Error()))
+	}
+	table.Render()
+	fmt.Fprintln(w, e.Summary())
+}
@@ -0,0 +1,16 @@
+// Package notify shows desktop notifications using the tools each
+// platform ships with.
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnsupported is returned when no notification tool is available
+var ErrUnsupported = errors.New("desktop notifications are not supported on this system")
+
+// Send shows a desktop notification
+func Send(ctx context.Context, title, message string) error {
+	return send(ctx, title, message)
+}
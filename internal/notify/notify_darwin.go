@@ -0,0 +1,19 @@
+//go:build darwin
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+func send(ctx context.Context, title, message string) error {
+	// strconv.Quote produces a string literal AppleScript accepts
+	script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+	if out, err := exec.CommandContext(ctx, "osascript", "-e", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build !darwin && !windows
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+func send(ctx context.Context, title, message string) error {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("%w (install notify-send)", ErrUnsupported)
+	}
+	if out, err := exec.CommandContext(ctx, path, "--app-name=osticket", title, message).CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %v: %s", err, out)
+	}
+	return nil
+}
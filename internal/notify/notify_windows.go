@@ -0,0 +1,30 @@
+//go:build windows
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// balloonScript shows a tray balloon tip; the text is passed through
+// environment variables so it never needs escaping
+const balloonScript = `
+Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:OSTICKET_NOTIFY_TITLE, $env:OSTICKET_NOTIFY_MESSAGE, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()
+`
+
+func send(ctx context.Context, title, message string) error {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", balloonScript)
+	cmd.Env = append(cmd.Environ(), "OSTICKET_NOTIFY_TITLE="+title, "OSTICKET_NOTIFY_MESSAGE="+message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("powershell: %v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package offline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSnapshot is returned when no snapshot exists for a read request
+var ErrNoSnapshot = errors.New("no offline snapshot available for this request")
+
+// Store keeps snapshots of read responses and an outbox of queued mutations.
+// Snapshots are disposable cache data while the outbox is state that must
+// survive, so they may live in different directories.
+type Store struct {
+	SnapshotDir string
+	OutboxDir   string
+}
+
+// Entry represents a queued mutation in the outbox
+type Entry struct {
+	ID      string          `json:"id"`
+	Created time.Time       `json:"created"`
+	Method  string          `json:"method"`
+	Request json.RawMessage `json:"request"`
+}
+
+// NewStore creates a store using the given snapshot and outbox directories
+func NewStore(snapshotDir, outboxDir string) *Store {
+	return &Store{SnapshotDir: snapshotDir, OutboxDir: outboxDir}
+}
+
+// snapshotKey derives a stable file name from the request
+func snapshotKey(method string, body []byte) string {
+	sum := sha256.Sum256(append([]byte(method+" "), body...))
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveSnapshot stores the raw response of a read request
+func (s *Store) SaveSnapshot(method string, body, response []byte) error {
+	if err := os.MkdirAll(s.SnapshotDir, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	path := filepath.Join(s.SnapshotDir, snapshotKey(method, body)+".json")
+	return os.WriteFile(path, response, 0600)
+}
+
+// LoadSnapshot returns the last stored response for a read request
+func (s *Store) LoadSnapshot(method string, body []byte) ([]byte, error) {
+	path := filepath.Join(s.SnapshotDir, snapshotKey(method, body)+".json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoSnapshot
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Enqueue appends a mutation to the outbox
+func (s *Store) Enqueue(method string, body []byte) (*Entry, error) {
+	if err := os.MkdirAll(s.OutboxDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	now := time.Now()
+	entry := &Entry{
+		ID:      fmt.Sprintf("%d", now.UnixNano()),
+		Created: now,
+		Method:  method,
+		Request: json.RawMessage(body),
+	}
+	if err := s.Save(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Save writes an outbox entry to disk
+func (s *Store) Save(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+	return os.WriteFile(filepath.Join(s.OutboxDir, entry.ID+".json"), data, 0600)
+}
+
+// List returns queued outbox entries in the order they were queued
+func (s *Store) List() ([]*Entry, error) {
+	files, err := os.ReadDir(s.OutboxDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	var entries []*Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.OutboxDir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox entry: %w", err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse outbox entry %s: %w", f.Name(), err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+	return entries, nil
+}
+
+// Remove deletes an outbox entry
+func (s *Store) Remove(id string) error {
+	err := os.Remove(filepath.Join(s.OutboxDir, id+".json"))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("outbox entry %s not found", id)
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+// Package output renders command results in the format chosen with
+// --output. Each format is a Formatter; commands describe their result once
+// as a Result and every format renders from it.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Built-in formats
+const (
+	Table   = "table"
+	JSON    = "json"
+	NDJSON  = "ndjson"
+	YAML    = "yaml"
+	CSV     = "csv"
+	TSV     = "tsv"
+	Raw     = "raw"
+	Parquet = "parquet"
+)
+
+// ErrNoRaw is returned by the raw formatter for results that have no raw
+// API response
+var ErrNoRaw = errors.New("raw output is not available for this command, use --output json")
+
+// Result is what a command renders. Formatters use the parts they need.
+type Result struct {
+	// Value is the complete result, used by json and yaml
+	Value interface{}
+	// Rows is the list of records for csv, tsv and the generic table.
+	// Value is used when Rows is nil; a single object is one record.
+	Rows interface{}
+	// Table renders the human-readable view. When nil, or when Fields are
+	// selected, a table is built from Rows instead.
+	Table func(w io.Writer)
+	// Raw is the unparsed API response
+	Raw []byte
+	// Fields selects and orders the columns of csv, tsv and table output
+	Fields []string
+	// IDs are the primary identifiers of the result, such as a new
+	// ticket's ID or the IDs of listed tickets
+	IDs []string
+}
+
+// records returns the rows to use for tabular formats
+func (r *Result) records() interface{} {
+	if r.Rows != nil {
+		return r.Rows
+	}
+	return r.Value
+}
+
+// jsonValue returns what JSON-based formats write: Value, or the records
+// when perRecord, as for ndjson. With Fields selected it is the records
+// cut down to those fields, in that order.
+func (r *Result) jsonValue(perRecord bool) (interface{}, error) {
+	if len(r.Fields) > 0 {
+		return selectFields(r.records(), r.Fields)
+	}
+	if perRecord {
+		return r.records(), nil
+	}
+	return r.Value, nil
+}
+
+// selectFields returns records as a JSON array of objects with only fields,
+// in that order; a missing field is null
+func selectFields(records interface{}, fields []string) (json.RawMessage, error) {
+	_, objects, err := decodeRecords(records, fields)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, object := range objects {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, field := range fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(field)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if value, ok := object[field]; ok {
+				buf.Write(value)
+			} else {
+				buf.WriteString("null")
+			}
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// Formatter writes a result in one format
+type Formatter interface {
+	Format(w io.Writer, r *Result) error
+}
+
+// FormatterFunc adapts a function to a Formatter
+type FormatterFunc func(w io.Writer, r *Result) error
+
+// Format calls f(w, r)
+func (f FormatterFunc) Format(w io.Writer, r *Result) error {
+	return f(w, r)
+}
+
+var formatters = map[string]Formatter{
+	Table:   FormatterFunc(formatTable),
+	JSON:    FormatterFunc(formatJSON),
+	NDJSON:  FormatterFunc(formatNDJSON),
+	YAML:    FormatterFunc(formatYAML),
+	CSV:     FormatterFunc(formatCSV),
+	TSV:     FormatterFunc(formatTSV),
+	Raw:     FormatterFunc(formatRaw),
+	Parquet: FormatterFunc(formatParquet),
+}
+
+// Register adds or replaces the formatter for a format name
+func Register(name string, f Formatter) {
+	formatters[strings.ToLower(name)] = f
+}
+
+// Lookup returns the formatter for a format name
+func Lookup(name string) (Formatter, error) {
+	f, ok := formatters[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q: use %s", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Names returns the registered format names in sorted order
+func Names() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write renders r to w in the named format
+func Write(w io.Writer, format string, r *Result) error {
+	f, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+	return f.Format(w, r)
+}
+
+func formatJSON(w io.Writer, r *Result) error {
+	value, err := r.jsonValue(false)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+// formatNDJSON writes each record on a line of its own, so a list can be
+// written in parts as it is fetched and read by line-oriented tools
+func formatNDJSON(w io.Writer, r *Result) error {
+	value, err := r.jsonValue(true)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var records []json.RawMessage
+	if json.Unmarshal(data, &records) != nil {
+		// A single object is one record
+		records = []json.RawMessage{data}
+	}
+	for _, record := range records {
+		if _, err := fmt.Fprintf(w, "%s\n", record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatRaw(w io.Writer, r *Result) error {
+	if r.Raw == nil {
+		return ErrNoRaw
+	}
+	if _, err := w.Write(r.Raw); err != nil {
+		return err
+	}
+	if len(r.Raw) > 0 && r.Raw[len(r.Raw)-1] != '\n' {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Column kinds inferred from the values of a field
+const (
+	kindString = iota
+	kindInt
+	kindDouble
+	kindBool
+	kindTimestamp
+	kindDate
+)
+
+// Parquet physical types, converted types and encodings
+const (
+	pqBoolean   = 0
+	pqInt32     = 1
+	pqInt64     = 2
+	pqDouble    = 5
+	pqByteArray = 6
+
+	pqUTF8            = 0
+	pqDate            = 6
+	pqTimestampMillis = 9
+
+	pqOptional = 1
+	pqPlain    = 0
+	pqRLE      = 3
+)
+
+// API timestamps are in the server's local time without a zone
+var (
+	timestampLayouts = []string{"2006-01-02 15:04:05", time.RFC3339Nano}
+	dateLayout       = "2006-01-02"
+	intPattern       = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,17})$`)
+)
+
+// parquetColumn holds one field's values. Nulls are recorded in defined
+// and omitted from the values.
+type parquetColumn struct {
+	name    string
+	kind    int
+	defined []bool
+	values  bytes.Buffer // PLAIN encoded non-null values
+	bools   []bool
+}
+
+// formatParquet writes records as a Parquet file. Column types are
+// inferred from the values: whole numbers (including numeric strings)
+// become INT64, other numbers DOUBLE, API timestamps TIMESTAMP_MILLIS,
+// dates DATE, booleans BOOLEAN and everything else UTF8 strings. Empty
+// values in non-string columns are null.
+func formatParquet(w io.Writer, r *Result) error {
+	fields, objects, err := decodeRecords(r.records(), r.Fields)
+	if err != nil {
+		return err
+	}
+
+	columns := make([]*parquetColumn, len(fields))
+	for i, field := range fields {
+		values := make([]interface{}, len(objects))
+		for j, object := range objects {
+			values[j] = decodeValue(object[field])
+		}
+		columns[i] = buildColumn(field, values)
+	}
+
+	return writeParquet(w, columns, len(objects))
+}
+
+// decodeValue parses a JSON value, keeping numbers exact
+func decodeValue(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if dec.Decode(&v) != nil {
+		return nil
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		// Nested values are stored as their JSON text
+		return string(raw)
+	}
+	return v
+}
+
+// isBlank reports values that are null in non-string columns
+func isBlank(v interface{}) bool {
+	s, ok := v.(string)
+	return v == nil || ok && (s == "" || strings.HasPrefix(s, "0000-00-00"))
+}
+
+// inferKind picks the narrowest kind that fits every non-blank value
+func inferKind(values []interface{}) int {
+	candidates := map[int]bool{kindInt: true, kindDouble: true, kindBool: true, kindTimestamp: true, kindDate: true}
+	seen := false
+	for _, v := range values {
+		if isBlank(v) {
+			continue
+		}
+		seen = true
+		switch val := v.(type) {
+		case json.Number:
+			if _, err := val.Int64(); err != nil {
+				candidates[kindInt] = false
+			}
+			candidates[kindBool], candidates[kindTimestamp], candidates[kindDate] = false, false, false
+		case bool:
+			candidates[kindInt], candidates[kindDouble], candidates[kindTimestamp], candidates[kindDate] = false, false, false, false
+		case string:
+			candidates[kindBool] = false
+			if !intPattern.MatchString(val) {
+				candidates[kindInt], candidates[kindDouble] = false, false
+			}
+			if _, ok := parseTimestamp(val); !ok {
+				candidates[kindTimestamp] = false
+			}
+			if _, err := time.Parse(dateLayout, val); err != nil {
+				candidates[kindDate] = false
+			}
+		}
+	}
+	if !seen {
+		return kindString
+	}
+	for _, kind := range []int{kindBool, kindInt, kindDouble, kindDate, kindTimestamp} {
+		if candidates[kind] {
+			return kind
+		}
+	}
+	return kindString
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// buildColumn converts a field's values to a typed column
+func buildColumn(name string, values []interface{}) *parquetColumn {
+	c := &parquetColumn{name: name, kind: inferKind(values), defined: make([]bool, len(values))}
+	var b [8]byte
+
+	for i, v := range values {
+		if v == nil || c.kind != kindString && isBlank(v) {
+			continue
+		}
+		c.defined[i] = true
+
+		switch c.kind {
+		case kindString:
+			s, ok := v.(string)
+			if !ok {
+				s = jsonText(v)
+			}
+			binary.LittleEndian.PutUint32(b[:4], uint32(len(s)))
+			c.values.Write(b[:4])
+			c.values.WriteString(s)
+		case kindInt:
+			n, _ := json.Number(jsonText(v)).Int64()
+			binary.LittleEndian.PutUint64(b[:], uint64(n))
+			c.values.Write(b[:])
+		case kindDouble:
+			f, _ := json.Number(jsonText(v)).Float64()
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+			c.values.Write(b[:])
+		case kindBool:
+			c.bools = append(c.bools, v.(bool))
+		case kindTimestamp:
+			t, _ := parseTimestamp(v.(string))
+			binary.LittleEndian.PutUint64(b[:], uint64(t.UnixMilli()))
+			c.values.Write(b[:])
+		case kindDate:
+			t, _ := time.Parse(dateLayout, v.(string))
+			binary.LittleEndian.PutUint32(b[:4], uint32(t.Unix()/86400))
+			c.values.Write(b[:4])
+		}
+	}
+
+	if c.kind == kindBool {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		c.values.Write(packed)
+	}
+	return c
+}
+
+// jsonText returns a decoded value's JSON text, e.g. a number's digits
+func jsonText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// physicalType returns the Parquet type of a column kind
+func physicalType(kind int) int32 {
+	switch kind {
+	case kindInt, kindTimestamp:
+		return pqInt64
+	case kindDouble:
+		return pqDouble
+	case kindBool:
+		return pqBoolean
+	case kindDate:
+		return pqInt32
+	}
+	return pqByteArray
+}
+
+// definitionLevels RLE-encodes which values are present, with the 4-byte
+// length prefix used in data pages
+func definitionLevels(defined []bool) []byte {
+	var runs bytes.Buffer
+	var b [binary.MaxVarintLen64]byte
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		n := binary.PutUvarint(b[:], uint64(j-i)<<1)
+		runs.Write(b[:n])
+		if defined[i] {
+			runs.WriteByte(1)
+		} else {
+			runs.WriteByte(0)
+		}
+		i = j
+	}
+
+	out := make([]byte, 4, 4+runs.Len())
+	binary.LittleEndian.PutUint32(out, uint32(runs.Len()))
+	return append(out, runs.Bytes()...)
+}
+
+// writeParquet writes the file: magic, one data page per column, footer
+func writeParquet(w io.Writer, columns []*parquetColumn, numRows int) error {
+	var file bytes.Buffer
+	file.WriteString("PAR1")
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(columns))
+	var totalSize int64
+	for i, c := range columns {
+		page := append(definitionLevels(c.defined), c.values.Bytes()...)
+
+		h := newThriftWriter()
+		h.i32(1, 0) // DATA_PAGE
+		h.i32(2, int32(len(page)))
+		h.i32(3, int32(len(page)))
+		h.begin(5)
+		h.i32(1, int32(numRows))
+		h.i32(2, pqPlain)
+		h.i32(3, pqRLE)
+		h.i32(4, pqRLE)
+		h.end()
+		header := h.bytes()
+
+		chunks[i] = chunk{offset: int64(file.Len()), size: int64(len(header) + len(page))}
+		totalSize += chunks[i].size
+		file.Write(header)
+		file.Write(page)
+	}
+
+	m := newThriftWriter()
+	m.i32(1, 1)
+	m.list(2, thriftStruct, len(columns)+1)
+	m.elem()
+	m.string(4, "schema")
+	m.i32(5, int32(len(columns)))
+	m.end()
+	for _, c := range columns {
+		m.elem()
+		m.i32(1, physicalType(c.kind))
+		m.i32(3, pqOptional)
+		m.string(4, c.name)
+		switch c.kind {
+		case kindString:
+			m.i32(6, pqUTF8)
+			m.begin(10)
+			m.begin(1) // STRING
+			m.end()
+			m.end()
+		case kindDate:
+			m.i32(6, pqDate)
+			m.begin(10)
+			m.begin(6) // DATE
+			m.end()
+			m.end()
+		case kindTimestamp:
+			m.i32(6, pqTimestampMillis)
+			m.begin(10)
+			m.begin(8) // TIMESTAMP
+			m.bool(1, true)
+			m.begin(2)
+			m.begin(1) // MILLIS
+			m.end()
+			m.end()
+			m.end()
+			m.end()
+		}
+		m.end()
+	}
+	m.i64(3, int64(numRows))
+	if len(columns) == 0 || numRows == 0 {
+		m.list(4, thriftStruct, 0)
+	} else {
+		m.list(4, thriftStruct, 1)
+		m.elem()
+		m.list(1, thriftStruct, len(columns))
+		for i, c := range columns {
+			m.elem()
+			m.i64(2, chunks[i].offset)
+			m.begin(3)
+			m.i32(1, physicalType(c.kind))
+			m.list(2, thriftI32, 2)
+			m.i32Elem(pqPlain)
+			m.i32Elem(pqRLE)
+			m.list(3, thriftBinary, 1)
+			m.stringElem(c.name)
+			m.i32(4, 0) // UNCOMPRESSED
+			m.i64(5, int64(numRows))
+			m.i64(6, chunks[i].size)
+			m.i64(7, chunks[i].size)
+			m.i64(9, chunks[i].offset)
+			m.end()
+			m.end()
+		}
+		m.i64(2, totalSize)
+		m.i64(3, int64(numRows))
+		m.end()
+	}
+	m.string(6, "osticket-cli")
+	footer := m.bytes()
+
+	file.Write(footer)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	file.Write(size[:])
+	file.WriteString("PAR1")
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
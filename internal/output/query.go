@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+)
+
+// Query is a jq expression run over a result's JSON, as with --jq, so
+// scripts can pick out values without a jq binary
+type Query struct {
+	code *gojq.Code
+}
+
+// ParseQuery compiles a jq expression
+func ParseQuery(expr string) (*Query, error) {
+	parsed, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression: %w", err)
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression: %w", err)
+	}
+	return &Query{code: code}, nil
+}
+
+// Write runs the query over the JSON r is written as and writes each value
+// it produces on a line: strings as they are, anything else as compact
+// JSON. With perRecord, as for ndjson, the query runs over each record in
+// turn instead.
+func (q *Query) Write(w io.Writer, r *Result, perRecord bool) error {
+	data, err := r.jsonValue(perRecord)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := decodeJSON(data, &value); err != nil {
+		return err
+	}
+	inputs := []interface{}{value}
+	if perRecord {
+		switch v := value.(type) {
+		case []interface{}:
+			inputs = v
+		case nil:
+			inputs = nil
+		}
+	}
+
+	for _, input := range inputs {
+		iter := q.code.Run(input)
+		for {
+			v, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := v.(error); ok {
+				var halt *gojq.HaltError
+				if errors.As(err, &halt) && halt.Value() == nil {
+					break
+				}
+				return fmt.Errorf("--jq: %w", err)
+			}
+			if err := writeQueryValue(w, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeJSON converts a value to the generic form jq works on
+func decodeJSON(value interface{}, out *interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// writeQueryValue writes one value produced by a query
+func writeQueryValue(w io.Writer, v interface{}) error {
+	if s, ok := v.(string); ok {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+	data, err := gojq.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
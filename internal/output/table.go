@@ -0,0 +1,167 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// formatTable uses the command's own table unless columns were selected,
+// in which case it builds one from the records
+func formatTable(w io.Writer, r *Result) error {
+	if r.Table != nil && len(r.Fields) == 0 {
+		r.Table(w)
+		return nil
+	}
+
+	fields, rows, err := tabulate(r.records(), r.Fields)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	table := tablewriter.NewWriter(w)
+	header := make([]string, len(fields))
+	colors := make([]tablewriter.Colors, len(fields))
+	for i, f := range fields {
+		header[i] = strings.ToUpper(f)
+		colors[i] = tablewriter.Colors{tablewriter.FgCyanColor}
+	}
+	table.SetHeader(header)
+	table.SetHeaderColor(colors...)
+	table.SetAutoFormatHeaders(false)
+	table.AppendBulk(rows)
+	table.Render()
+	return nil
+}
+
+func formatCSV(w io.Writer, r *Result) error {
+	fields, rows, err := tabulate(r.records(), r.Fields)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := csv.NewWriter(w)
+	out.Write(fields)
+	out.WriteAll(rows)
+	return out.Error()
+}
+
+// tsvEscaper keeps every record on one line with one tab per column
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func formatTSV(w io.Writer, r *Result) error {
+	fields, rows, err := tabulate(r.records(), r.Fields)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	for _, row := range append([][]string{fields}, rows...) {
+		for i, cell := range row {
+			row[i] = tsvEscaper.Replace(cell)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tabulate flattens records into rows of cells. Without fields, the
+// columns are the keys of the first record in their JSON order, so struct
+// records keep their declared order. Nested values become JSON and missing
+// fields empty cells.
+func tabulate(records interface{}, fields []string) ([]string, [][]string, error) {
+	fields, objects, err := decodeRecords(records, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([][]string, len(objects))
+	for i, object := range objects {
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			row[j] = cellValue(object[field])
+		}
+		rows[i] = row
+	}
+	return fields, rows, nil
+}
+
+// decodeRecords converts records to JSON objects and picks the columns:
+// fields when given, otherwise the keys of the first record
+func decodeRecords(records interface{}, fields []string) ([]string, []map[string]json.RawMessage, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		data = append(append([]byte{'['}, data...), ']')
+	}
+
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, nil, fmt.Errorf("this result cannot be shown as columns, use --output json")
+	}
+
+	if len(fields) == 0 && len(objects) > 0 {
+		if fields, err = objectKeys(data); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fields, objects, nil
+}
+
+// objectKeys returns the keys of the first object in a JSON array in the
+// order they appear
+func objectKeys(array []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(array))
+	// Skip '[' and '{'
+	for i := 0; i < 2; i++ {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("failed to read fields: %w", err)
+		}
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fields: %w", err)
+		}
+		keys = append(keys, tok.(string))
+
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, fmt.Errorf("failed to read fields: %w", err)
+		}
+	}
+	return keys, nil
+}
+
+// cellValue formats a JSON value as a cell
+func cellValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	// Numbers, booleans, objects and arrays are written as JSON
+	return string(raw)
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formatYAML renders the JSON value as block-style YAML. The value goes through JSON
+// first so the json struct tags name the keys and their order is kept.
+func formatYAML(w io.Writer, r *Result) error {
+	value, err := r.jsonValue(false)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles JSON input parses with.
+// Strings that would otherwise read as another type keep their quotes.
+func blockStyle(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		n.Style = yaml.DoubleQuotedStyle
+		var probe interface{}
+		if yaml.Unmarshal([]byte(n.Value), &probe) == nil {
+			if s, ok := probe.(string); ok && s == n.Value {
+				n.Style = 0
+			}
+		}
+	} else {
+		n.Style = 0
+	}
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,99 @@
+// Package refsync compares the reference data (departments, help topics,
+// SLA plans...) of two osTicket instances, matching objects by name since
+// their IDs differ between instances.
+package refsync
+
+import (
+	"sort"
+	"strings"
+)
+
+// Actions that bring the target in line with the source
+const (
+	Add    = "add"
+	Update = "update"
+	Remove = "remove"
+)
+
+// Item is a reference data object of one instance
+type Item struct {
+	ID   int
+	Name string
+	// Fields are the compared attributes besides the name
+	Fields map[string]string
+}
+
+// FieldChange is an attribute that differs between the instances
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Change is what the target needs to match the source for one object
+type Change struct {
+	Object string        `json:"object"`
+	Action string        `json:"action"`
+	Name   string        `json:"name"`
+	FromID int           `json:"from_id,omitempty"`
+	ToID   int           `json:"to_id,omitempty"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Diff returns the changes that make the target's objects match the
+// source's: objects only in the source are added, objects only in the
+// target removed and objects whose fields differ updated. Names match
+// regardless of case and surrounding spaces.
+func Diff(object string, from, to []Item) []Change {
+	key := func(name string) string {
+		return strings.ToLower(strings.TrimSpace(name))
+	}
+	target := map[string]Item{}
+	for _, it := range to {
+		target[key(it.Name)] = it
+	}
+
+	var changes []Change
+	seen := map[string]bool{}
+	for _, src := range from {
+		k := key(src.Name)
+		seen[k] = true
+		dst, ok := target[k]
+		if !ok {
+			changes = append(changes, Change{Object: object, Action: Add, Name: src.Name, FromID: src.ID})
+			continue
+		}
+		var fields []FieldChange
+		for _, field := range sortedKeys(src.Fields) {
+			if src.Fields[field] != dst.Fields[field] {
+				fields = append(fields, FieldChange{Field: field, From: dst.Fields[field], To: src.Fields[field]})
+			}
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Object: object, Action: Update, Name: src.Name, FromID: src.ID, ToID: dst.ID, Fields: fields})
+		}
+	}
+	for _, dst := range to {
+		if !seen[key(dst.Name)] {
+			changes = append(changes, Change{Object: object, Action: Remove, Name: dst.Name, ToID: dst.ID})
+		}
+	}
+
+	order := map[string]int{Add: 0, Update: 1, Remove: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return order[changes[i].Action] < order[changes[j].Action]
+		}
+		return key(changes[i].Name) < key(changes[j].Name)
+	})
+	return changes
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,200 @@
+package retention
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions a rule can take on matching tickets
+const (
+	Archive = "archive"
+	Delete  = "delete"
+)
+
+// Status IDs set by each action
+var actionStatus = map[string]int{
+	Archive: 4,
+	Delete:  5,
+}
+
+// PlanMaxAge is how long a dry run stays valid for a real run
+const PlanMaxAge = 24 * time.Hour
+
+// Rule selects tickets by department and status that have had no activity
+// for longer than OlderThan. Dept and Status are names or IDs; empty
+// matches any.
+type Rule struct {
+	Dept      string `yaml:"dept" json:"dept,omitempty"`
+	Status    string `yaml:"status" json:"status,omitempty"`
+	OlderThan string `yaml:"older_than" json:"older_than"`
+	Action    string `yaml:"action" json:"action"`
+
+	// Resolved by the caller before matching
+	DeptID   int           `yaml:"-" json:"-"`
+	StatusID int           `yaml:"-" json:"-"`
+	MaxAge   time.Duration `yaml:"-" json:"-"`
+}
+
+// String describes the rule for reports and comments
+func (r Rule) String() string {
+	parts := []string{r.Action}
+	if r.Dept != "" {
+		parts = append(parts, "dept "+r.Dept)
+	}
+	if r.Status != "" {
+		parts = append(parts, "status "+r.Status)
+	}
+	return strings.Join(append(parts, "older than "+r.OlderThan), ", ")
+}
+
+// TargetStatus returns the status the rule's action sets
+func (r Rule) TargetStatus() int {
+	return actionStatus[r.Action]
+}
+
+// Policy is a retention policy file. Rules are checked in order and the
+// first that matches a ticket applies.
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+
+	// Digest is the SHA-256 of the policy file, tying plans to it
+	Digest string `yaml:"-"`
+}
+
+// Load reads and checks a policy file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read policy: %w", err)
+	}
+
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("policy %s has no rules", path)
+	}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if _, ok := actionStatus[r.Action]; !ok {
+			return nil, fmt.Errorf("rule %d: invalid action %q: use %s or %s", i+1, r.Action, Archive, Delete)
+		}
+		if r.MaxAge, err = ParseAge(r.OlderThan); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	p.Digest = hex.EncodeToString(sum[:])
+	return &p, nil
+}
+
+// ParseAge parses an age such as "90d", "2y" or any Go duration ("36h")
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "y": 365 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n > 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid age %q: use a value like 90d, 2y or 720h", s)
+}
+
+// Ticket is what rules match against
+type Ticket struct {
+	DeptID   int
+	StatusID int
+	// Idle is how long the ticket has had no activity
+	Idle time.Duration
+}
+
+// Match returns the index of the first rule that applies to t. Tickets
+// already in (or past) a rule's target status are left alone.
+func (p *Policy) Match(t Ticket) (int, bool) {
+	for i, r := range p.Rules {
+		if r.DeptID != 0 && r.DeptID != t.DeptID {
+			continue
+		}
+		if r.StatusID != 0 && r.StatusID != t.StatusID {
+			continue
+		}
+		if t.Idle < r.MaxAge {
+			continue
+		}
+		if t.StatusID >= r.TargetStatus() {
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// Item is a ticket selected by a policy
+type Item struct {
+	TicketID     int    `json:"ticket_id"`
+	Number       string `json:"number"`
+	DeptID       int    `json:"dept_id"`
+	StatusID     int    `json:"status_id"`
+	LastActivity string `json:"last_activity"`
+	Rule         int    `json:"rule"`
+	Action       string `json:"action"`
+	Email        string `json:"email,omitempty"`
+	// Hold names the legal hold that keeps the ticket from being changed
+	Hold string `json:"hold,omitempty"`
+	// Result is set when the plan is applied
+	Result string `json:"result,omitempty"`
+}
+
+// Plan is the outcome of a dry run, which a real run must match
+type Plan struct {
+	Policy  string    `json:"policy"`
+	Digest  string    `json:"policy_sha256"`
+	Server  string    `json:"server"`
+	Created time.Time `json:"created"`
+	Items   []Item    `json:"items"`
+}
+
+// Check reports why a plan cannot be applied with a policy and server
+func (pl *Plan) Check(p *Policy, server string, now time.Time) error {
+	switch {
+	case pl.Digest != p.Digest:
+		return fmt.Errorf("the policy changed since the dry run")
+	case pl.Server != server:
+		return fmt.Errorf("the dry run was against %s", pl.Server)
+	case now.Sub(pl.Created) > PlanMaxAge:
+		return fmt.Errorf("the dry run is older than %.0f hours", PlanMaxAge.Hours())
+	}
+	return nil
+}
+
+// Report is the evidence record of an applied plan
+type Report struct {
+	Policy   string    `json:"policy"`
+	Digest   string    `json:"policy_sha256"`
+	Rules    []Rule    `json:"rules"`
+	Server   string    `json:"server"`
+	Operator string    `json:"operator"`
+	DryRun   time.Time `json:"dry_run"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Applied  int       `json:"applied"`
+	Skipped  int       `json:"skipped"`
+	Failed   int       `json:"failed"`
+	Items    []Item    `json:"items"`
+}
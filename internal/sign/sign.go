@@ -0,0 +1,96 @@
+package sign
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Supported signing tools
+const (
+	GPG      = "gpg"
+	Minisign = "minisign"
+)
+
+// ManifestExt is appended to an artifact's name for its checksum manifest
+const ManifestExt = ".sha256"
+
+// Manifest returns a SHA-256 manifest for an artifact in sha256sum format,
+// so it can be checked with "sha256sum -c" next to the artifact
+func Manifest(sum []byte, artifact string) []byte {
+	return []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum), path.Base(artifact)))
+}
+
+// Extension returns the file extension of a tool's detached signatures
+func Extension(tool string) (string, error) {
+	switch tool {
+	case GPG:
+		return ".asc", nil
+	case Minisign:
+		return ".minisig", nil
+	}
+	return "", fmt.Errorf("invalid signing tool %q: use %s or %s", tool, GPG, Minisign)
+}
+
+// Sign returns a detached signature of data made with gpg or minisign.
+// key is a gpg key ID or a minisign secret key file; when empty the tool's
+// default key is used. Either tool may prompt for a passphrase.
+func Sign(ctx context.Context, tool, key string, data []byte) ([]byte, error) {
+	if _, err := Extension(tool); err != nil {
+		return nil, err
+	}
+	if tool == GPG {
+		args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", "-"}
+		if key != "" {
+			args = append(args, "--local-user", key)
+		}
+		return run(ctx, GPG, args, data)
+	}
+
+	// minisign only signs files
+	dir, err := os.MkdirTemp("", "osticket-sign-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "manifest")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return nil, err
+	}
+	args := []string{"-S", "-m", file, "-x", file + ".minisig"}
+	if key != "" {
+		args = append(args, "-s", key)
+	}
+	if _, err := run(ctx, Minisign, args, nil); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(file + ".minisig")
+}
+
+// run runs a signing tool. Without input the terminal is passed through
+// so the tool can prompt for a passphrase.
+func run(ctx context.Context, name string, args []string, input []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stderr = &stderr
+	} else {
+		cmd.Stdin = os.Stdin
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %v: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,236 @@
+// Package survey keeps track of satisfaction surveys: which tickets were
+// sent one, so no customer gets two, and the responses posted back.
+package survey
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Ratings run from MinRating to MaxRating
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Send records a survey emailed for a ticket. Token identifies the send in
+// the survey link and in the response posted back.
+type Send struct {
+	TicketID int       `json:"ticket_id"`
+	Number   string    `json:"number"`
+	Email    string    `json:"email"`
+	DeptID   int       `json:"dept_id,omitempty"`
+	StaffID  int       `json:"staff_id,omitempty"`
+	Token    string    `json:"token"`
+	Sent     time.Time `json:"sent"`
+}
+
+// Sends is the list of surveys sent for a profile
+type Sends struct {
+	Sends []Send `json:"sends"`
+}
+
+// LoadSends reads the list of sent surveys. A missing file is an empty
+// list.
+func LoadSends(path string) (*Sends, error) {
+	var s Sends
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read survey sends: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid survey send list %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the list atomically
+func (s *Sends) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Sent reports whether a survey was sent for a ticket
+func (s *Sends) Sent(ticketID int) bool {
+	for _, send := range s.Sends {
+		if send.TicketID == ticketID {
+			return true
+		}
+	}
+	return false
+}
+
+// ByToken returns the send with a token
+func (s *Sends) ByToken(token string) (Send, bool) {
+	for _, send := range s.Sends {
+		if send.Token == token {
+			return send, true
+		}
+	}
+	return Send{}, false
+}
+
+// NewToken returns a random, unguessable survey token
+func NewToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Link adds a send's token and ticket number to the survey page URL, as
+// the token and ticket query parameters
+func Link(base, token, number string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid survey URL %q: use an http or https URL", base)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	q.Set("ticket", number)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Response is a survey answer posted back by the survey page
+type Response struct {
+	Token    string    `json:"token"`
+	TicketID int       `json:"ticket_id"`
+	Rating   int       `json:"rating"`
+	Comment  string    `json:"comment,omitempty"`
+	Received time.Time `json:"received"`
+}
+
+// Validate checks the rating of a response
+func (r Response) Validate() error {
+	if r.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	}
+	return nil
+}
+
+// AppendResponse adds a response to a JSON lines file. Appending keeps the
+// receiver from rewriting data a concurrent survey send is saving.
+func AppendResponse(path string, r Response) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadResponses reads the responses of a JSON lines file. A later response
+// for the same token replaces an earlier one, so a customer can change
+// their answer. A missing file has no responses.
+func LoadResponses(path string) ([]Response, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read survey responses: %w", err)
+	}
+	defer f.Close()
+
+	var responses []Response
+	index := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Response
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("invalid survey response on line %d of %s: %w", line, path, err)
+		}
+		if i, ok := index[r.Token]; ok {
+			responses[i] = r
+			continue
+		}
+		index[r.Token] = len(responses)
+		responses = append(responses, r)
+	}
+	return responses, scanner.Err()
+}
+
+// Summary sums up the surveys sent in a period and their responses
+type Summary struct {
+	Since     *time.Time  `json:"since,omitempty"`
+	Sent      int         `json:"sent"`
+	Responded int         `json:"responded"`
+	Rate      float64     `json:"response_rate"`
+	Average   float64     `json:"average_rating"`
+	Ratings   map[int]int `json:"ratings"`
+}
+
+// Summarize counts the surveys sent since a time (all when zero) and the
+// responses to them
+func Summarize(sends *Sends, responses []Response, since time.Time) Summary {
+	sum := Summary{Ratings: map[int]int{}}
+	if !since.IsZero() {
+		sum.Since = &since
+	}
+	byToken := map[string]Response{}
+	for _, r := range responses {
+		byToken[r.Token] = r
+	}
+
+	total := 0
+	for _, send := range sends.Sends {
+		if send.Sent.Before(since) {
+			continue
+		}
+		sum.Sent++
+		r, ok := byToken[send.Token]
+		if !ok {
+			continue
+		}
+		sum.Responded++
+		sum.Ratings[r.Rating]++
+		total += r.Rating
+	}
+	if sum.Sent > 0 {
+		sum.Rate = float64(sum.Responded) / float64(sum.Sent)
+	}
+	if sum.Responded > 0 {
+		sum.Average = float64(total) / float64(sum.Responded)
+	}
+	return sum
+}
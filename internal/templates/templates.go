@@ -0,0 +1,264 @@
+// Package templates stores reusable ticket templates. A template is a YAML
+// file whose title, body and custom field values are Go templates, filled
+// in with variables when a ticket is created from it.
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotFound is returned when no template has the requested name
+var ErrNotFound = errors.New("template not found")
+
+// Template is a reusable ticket shape. Dept, Topic and Priority take an ID
+// or a name, as the ticket create flags do.
+type Template struct {
+	Name        string            `yaml:"-" json:"name"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Title       string            `yaml:"title" json:"title"`
+	Body        string            `yaml:"body" json:"body"`
+	Dept        string            `yaml:"dept,omitempty" json:"dept,omitempty"`
+	Topic       string            `yaml:"topic,omitempty" json:"topic,omitempty"`
+	Priority    string            `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Fields      map[string]string `yaml:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// namePattern keeps template names usable as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidName checks that a template name is letters, digits, - and _
+func ValidName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// Validate checks the name, that a title and body are given and that every
+// placeholder parses
+func (t *Template) Validate() error {
+	if err := ValidName(t.Name); err != nil {
+		return err
+	}
+	if t.Title == "" || t.Body == "" {
+		return fmt.Errorf("template %s needs a title and a body", t.Name)
+	}
+	_, err := t.Vars()
+	return err
+}
+
+// texts returns the template's placeholder texts by a label for errors
+func (t *Template) texts() map[string]string {
+	texts := map[string]string{"title": t.Title, "body": t.Body}
+	for k, v := range t.Fields {
+		texts["field "+k] = v
+	}
+	return texts
+}
+
+// Vars returns the names of the variables the template uses, sorted
+func (t *Template) Vars() ([]string, error) {
+	seen := map[string]bool{}
+	for label, text := range t.texts() {
+		tmpl, err := template.New(label).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in template %s: %w", label, t.Name, err)
+		}
+		collectVars(tmpl.Tree.Root, seen)
+	}
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
+
+// collectVars adds the top-level fields referenced by a template tree
+func collectVars(node parse.Node, seen map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			collectVars(c, seen)
+		}
+	case *parse.ActionNode:
+		collectVars(n.Pipe, seen)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			collectVars(c, seen)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			collectVars(a, seen)
+		}
+	case *parse.FieldNode:
+		seen[n.Ident[0]] = true
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, seen)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, seen)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, seen)
+	}
+}
+
+func collectBranch(b *parse.BranchNode, seen map[string]bool) {
+	collectVars(b.Pipe, seen)
+	collectVars(b.List, seen)
+	collectVars(b.ElseList, seen)
+}
+
+// Apply fills in the title, body and fields with vars. Every variable the
+// template uses must be given.
+func (t *Template) Apply(vars map[string]string) (*Template, error) {
+	var missing []string
+	used, err := t.Vars()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range used {
+		if _, ok := vars[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s needs --var for: %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	fill := func(label, text string) (string, error) {
+		tmpl, err := template.New(label).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s in template %s: %w", label, t.Name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, vars); err != nil {
+			return "", fmt.Errorf("could not fill in %s of template %s: %w", label, t.Name, err)
+		}
+		return buf.String(), nil
+	}
+
+	out := *t
+	if out.Title, err = fill("title", t.Title); err != nil {
+		return nil, err
+	}
+	if out.Body, err = fill("body", t.Body); err != nil {
+		return nil, err
+	}
+	if len(t.Fields) > 0 {
+		out.Fields = make(map[string]string, len(t.Fields))
+		for k, v := range t.Fields {
+			if out.Fields[k], err = fill("field "+k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &out, nil
+}
+
+// Parse reads a template from YAML
+func Parse(name string, data []byte) (*Template, error) {
+	var t Template
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	t.Name = name
+	return &t, nil
+}
+
+// path returns the file of a template in dir
+func path(dir, name string) string {
+	return filepath.Join(dir, name+".yaml")
+}
+
+// Load reads the template called name from dir
+func Load(dir, name string) (*Template, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read template %s: %w", name, err)
+	}
+	return Parse(name, data)
+}
+
+// List reads every template in dir, sorted by name. A missing directory
+// has no templates.
+func List(dir string) ([]*Template, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read templates: %w", err)
+	}
+
+	var list []*Template
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if !ok || e.IsDir() || ValidName(name) != nil {
+			continue
+		}
+		t, err := Load(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// Save validates a template and writes it to dir atomically, replacing a
+// template of the same name
+func Save(dir string, t *Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp := path(dir, t.Name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path(dir, t.Name))
+}
+
+// Delete removes the template called name from dir
+func Delete(dir, name string) error {
+	if err := ValidName(name); err != nil {
+		return err
+	}
+	err := os.Remove(path(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package thresholds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Priority IDs osTicket ships with
+const (
+	Low       = 1
+	Normal    = 2
+	High      = 3
+	Emergency = 4
+)
+
+// priorityNames maps priority names to IDs
+var priorityNames = map[string]int{
+	"low":       Low,
+	"normal":    Normal,
+	"high":      High,
+	"emergency": Emergency,
+}
+
+// Defaults are the ages after which a ticket of each priority is late
+var Defaults = map[int]time.Duration{
+	Low:       24 * time.Hour,
+	Normal:    8 * time.Hour,
+	High:      2 * time.Hour,
+	Emergency: 30 * time.Minute,
+}
+
+// Level is how urgent a ticket's age is
+type Level int
+
+// Age levels. A ticket is Warn once it has used half its allowance.
+const (
+	OK Level = iota
+	Warn
+	Late
+)
+
+func (l Level) String() string {
+	switch l {
+	case Warn:
+		return "warn"
+	case Late:
+		return "late"
+	}
+	return "ok"
+}
+
+// TimeLayout is the timestamp format the API uses
+const TimeLayout = "2006-01-02 15:04:05"
+
+// Thresholds holds the late age for each priority
+type Thresholds struct {
+	late map[int]time.Duration
+}
+
+// New returns the defaults with overrides applied. Overrides map a
+// priority name or ID to a duration, e.g. {"emergency": "30m"}.
+func New(overrides map[string]string) (*Thresholds, error) {
+	t := &Thresholds{late: map[int]time.Duration{}}
+	for id, d := range Defaults {
+		t.late[id] = d
+	}
+
+	for name, value := range overrides {
+		id, err := PriorityID(name)
+		if err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid age threshold %q for %s: use a value like 30m or 8h", value, name)
+		}
+		t.late[id] = d
+	}
+	return t, nil
+}
+
+// PriorityID resolves a priority name (case-insensitive) or numeric ID
+func PriorityID(name string) (int, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if id, ok := priorityNames[key]; ok {
+		return id, nil
+	}
+	var id int
+	if _, err := fmt.Sscanf(key, "%d", &id); err == nil && id > 0 {
+		return id, nil
+	}
+
+	names := make([]string, 0, len(priorityNames))
+	for n := range priorityNames {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return 0, fmt.Errorf("unknown priority %q: use an ID or one of %s", name, strings.Join(names, ", "))
+}
+
+// Late returns the age after which a ticket of the given priority is
+// late. Unknown priorities use the normal threshold.
+func (t *Thresholds) Late(priority int) time.Duration {
+	if d, ok := t.late[priority]; ok {
+		return d
+	}
+	return t.late[Normal]
+}
+
+// Classify returns the level of a ticket of the given priority and age.
+// Tickets osTicket has flagged overdue are always late.
+func (t *Thresholds) Classify(priority int, age time.Duration, overdue bool) Level {
+	late := t.Late(priority)
+	switch {
+	case overdue || age >= late:
+		return Late
+	case age >= late/2:
+		return Warn
+	}
+	return OK
+}
+
+// Age returns how long ago an API timestamp was, or false when it cannot
+// be parsed
+func Age(created string, now time.Time) (time.Duration, bool) {
+	at, err := time.ParseInLocation(TimeLayout, created, time.Local)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(at), true
+}
+
+// Format renders an age compactly, e.g. "45m", "3h20m" or "2d4h"
+func Format(age time.Duration) string {
+	if age < 0 {
+		age = 0
+	}
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(age.Hours()), int(age.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(age.Hours())/24, int(age.Hours())%24)
+}
@@ -0,0 +1,254 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the timezone database so checks work on systems without tzdata
+	_ "time/tzdata"
+)
+
+// FieldError describes an invalid flag value
+type FieldError struct {
+	Flag   string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid --%s %q: %s", e.Flag, e.Value, e.Reason)
+}
+
+// Email parses an RFC 5322 address and returns it normalized with a
+// lower-cased domain. Display names ("Jane <jane@example.com>") are rejected.
+func Email(flag, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "email is empty"}
+	}
+
+	addr, err := mail.ParseAddress(trimmed)
+	if err != nil {
+		return "", &FieldError{Flag: flag, Value: value, Reason: strings.TrimPrefix(err.Error(), "mail: ")}
+	}
+	if addr.Name != "" || addr.Address != strings.Trim(trimmed, "<>") {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "expected a bare address like user@example.com"}
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	local, domain := addr.Address[:at], addr.Address[at+1:]
+	if !strings.Contains(domain, ".") {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "domain must contain a dot"}
+	}
+
+	return local + "@" + strings.ToLower(domain), nil
+}
+
+// Phone normalizes a phone number to E.164 (+<country><number>). Numbers
+// without a leading + or 00 are prefixed with defaultCountry.
+func Phone(flag, value, defaultCountry string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "phone number is empty"}
+	}
+
+	international := strings.HasPrefix(trimmed, "+")
+	var digits strings.Builder
+	for i, r := range trimmed {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", &FieldError{Flag: flag, Value: value, Reason: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	number := digits.String()
+	if !international && strings.HasPrefix(number, "00") {
+		number = number[2:]
+		international = true
+	}
+	if !international {
+		number = strings.TrimPrefix(defaultCountry, "+") + number
+	}
+
+	if number[0] == '0' {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "country code cannot start with 0"}
+	}
+	if len(number) < 8 || len(number) > 15 {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "E.164 numbers have 8 to 15 digits including the country code"}
+	}
+
+	return "+" + number, nil
+}
+
+// Timezone checks that value is an IANA timezone name such as
+// "America/New_York"
+func Timezone(flag, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || trimmed == "Local" {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "expected an IANA timezone name like America/New_York"}
+	}
+	if _, err := time.LoadLocation(trimmed); err != nil {
+		return "", &FieldError{Flag: flag, Value: value, Reason: "unknown timezone"}
+	}
+	return trimmed, nil
+}
+
+// Choice is an allowed value for an ID flag
+type Choice struct {
+	ID   int
+	Name string
+}
+
+// ID checks that value is one of the allowed choices and suggests the
+// closest valid ID when it is not
+func ID(flag string, value int, choices []Choice) error {
+	if len(choices) == 0 {
+		return nil
+	}
+
+	closest := choices[0]
+	for _, c := range choices {
+		if c.ID == value {
+			return nil
+		}
+		if abs(c.ID-value) < abs(closest.ID-value) {
+			closest = c
+		}
+	}
+
+	return &FieldError{
+		Flag:   flag,
+		Value:  fmt.Sprint(value),
+		Reason: fmt.Sprintf("unknown ID (did you mean --%s %d %q?)", flag, closest.ID, closest.Name),
+	}
+}
+
+// Named resolves a value that is either a numeric ID or the name of one
+// of choices (case-insensitive). Numeric IDs are returned as is, since
+// servers may define IDs beyond the known choices.
+func Named(flag, value string, choices []Choice) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+
+	names := make([]string, len(choices))
+	for i, c := range choices {
+		if strings.EqualFold(c.Name, value) {
+			return c.ID, nil
+		}
+		names[i] = strings.ToLower(c.Name)
+	}
+
+	return 0, &FieldError{
+		Flag:   flag,
+		Value:  value,
+		Reason: "expected an ID or one of " + strings.Join(names, ", "),
+	}
+}
+
+// Resolve resolves a value that is either a numeric ID or the name of one
+// of choices, such as a department. Names match case-insensitively. A name
+// shared by several choices is ambiguous and must be given as an ID;
+// unknown names are reported with the closest names as suggestions.
+func Resolve(flag, kind, value string, choices []Choice) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+
+	var matches []Choice
+	for _, c := range choices {
+		if strings.EqualFold(strings.TrimSpace(c.Name), strings.TrimSpace(value)) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0].ID, nil
+	case 0:
+	default:
+		return 0, &FieldError{Flag: flag, Value: value, Reason: fmt.Sprintf("ambiguous %s name, use one of the IDs: %s", kind, describe(matches))}
+	}
+
+	reason := "unknown " + kind
+	if similar := Similar(value, choices, 3); len(similar) > 0 {
+		reason += " (did you mean " + describe(similar) + "?)"
+	} else if len(choices) > 0 {
+		reason += ", expected an ID or one of " + describe(choices)
+	}
+	return 0, &FieldError{Flag: flag, Value: value, Reason: reason}
+}
+
+// Similar returns up to max choices whose names contain value or are a few
+// edits away from it, closest first
+func Similar(value string, choices []Choice, max int) []Choice {
+	value = strings.ToLower(strings.TrimSpace(value))
+	type scored struct {
+		choice   Choice
+		distance int
+	}
+	var found []scored
+	for _, c := range choices {
+		name := strings.ToLower(strings.TrimSpace(c.Name))
+		d := distance(value, name)
+		switch {
+		case strings.Contains(name, value) || strings.Contains(value, name):
+			// Partial names rank ahead of misspellings
+			found = append(found, scored{c, d - len(name)})
+		case d <= len(value)/3+1:
+			found = append(found, scored{c, d})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].distance < found[j].distance })
+
+	var out []Choice
+	for i := 0; i < len(found) && i < max; i++ {
+		out = append(out, found[i].choice)
+	}
+	return out
+}
+
+// describe lists choices as "Name" (ID n)
+func describe(choices []Choice) string {
+	parts := make([]string, len(choices))
+	for i, c := range choices {
+		parts[i] = fmt.Sprintf("%q (ID %d)", c.Name, c.ID)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// distance is the Levenshtein distance between a and b
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
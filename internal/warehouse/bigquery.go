@@ -0,0 +1,149 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// bigQuery loads tables through the bq CLI, so gcloud credentials apply.
+// Rows are loaded into a staging table and merged into the target.
+type bigQuery struct {
+	project string
+	dataset string
+}
+
+var bqTypes = map[string]string{
+	Int:       "INT64",
+	Text:      "STRING",
+	Date:      "DATE",
+	Timestamp: "DATETIME",
+}
+
+// bqLoadTypes are the type names bq load accepts in a schema string
+var bqLoadTypes = map[string]string{
+	Int:       "INTEGER",
+	Text:      "STRING",
+	Date:      "DATE",
+	Timestamp: "DATETIME",
+}
+
+func (q *bigQuery) staging(t Table) string {
+	return t.Name + "_staging"
+}
+
+// schema returns the bq load schema string, e.g. "id:INTEGER,name:STRING"
+func (q *bigQuery) schema(t Table) string {
+	fields := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		fields[i] = c.Name + ":" + bqLoadTypes[c.Type]
+	}
+	return strings.Join(fields, ",")
+}
+
+// script returns the SQL script that merges the staging table
+func (q *bigQuery) script(t Table) string {
+	target := fmt.Sprintf("`%s.%s.%s`", q.project, q.dataset, t.Name)
+	staging := fmt.Sprintf("`%s.%s.%s`", q.project, q.dataset, q.staging(t))
+
+	var b strings.Builder
+	defs := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		defs[i] = c.Name + " " + bqTypes[c.Type]
+	}
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (%s);\n", target, strings.Join(defs, ", "))
+	for _, c := range t.Columns {
+		fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s;\n", target, c.Name, bqTypes[c.Type])
+	}
+
+	on := make([]string, len(t.Key))
+	for i, k := range t.Key {
+		on[i] = "T." + k + " = S." + k
+	}
+	names := make([]string, len(t.Columns))
+	var updates []string
+	for i, c := range t.Columns {
+		names[i] = c.Name
+		if !t.isKey(c.Name) {
+			updates = append(updates, c.Name+" = S."+c.Name)
+		}
+	}
+	fmt.Fprintf(&b, "MERGE %s T USING %s S ON %s\n", target, staging, strings.Join(on, " AND "))
+	if len(updates) > 0 {
+		fmt.Fprintf(&b, "WHEN MATCHED THEN UPDATE SET %s\n", strings.Join(updates, ", "))
+	}
+	fmt.Fprintf(&b, "WHEN NOT MATCHED THEN INSERT (%s) VALUES (S.%s);\n",
+		strings.Join(names, ", "), strings.Join(names, ", S."))
+	fmt.Fprintf(&b, "DROP TABLE %s;\n", staging)
+	return b.String()
+}
+
+// Plan returns the bq commands and merge script Load runs
+func (q *bigQuery) Plan(t Table, rows []Row) string {
+	return fmt.Sprintf("bq load --replace --source_format=NEWLINE_DELIMITED_JSON %s:%s.%s <%d rows> %s\nbq query --use_legacy_sql=false <<EOF\n%sEOF\n",
+		q.project, q.dataset, q.staging(t), len(rows), q.schema(t), q.script(t))
+}
+
+// Load stages the rows as newline-delimited JSON and merges them
+func (q *bigQuery) Load(ctx context.Context, t Table, rows []Row) error {
+	if err := CheckTable(t); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "osticket-bq-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	enc := json.NewEncoder(f)
+	for _, row := range rows {
+		record := map[string]interface{}{}
+		for _, c := range t.Columns {
+			v := row[c.Name]
+			if s, ok := v.(string); ok && s == "" && c.Type != Text {
+				v = nil
+			}
+			record[c.Name] = v
+		}
+		if err := enc.Encode(record); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := q.bq(ctx, nil, "load", "--replace", "--source_format=NEWLINE_DELIMITED_JSON",
+		fmt.Sprintf("%s:%s.%s", q.project, q.dataset, q.staging(t)), f.Name(), q.schema(t)); err != nil {
+		return fmt.Errorf("bq load failed for %s: %w", t.Name, err)
+	}
+	if err := q.bq(ctx, strings.NewReader(q.script(t)), "query", "--use_legacy_sql=false"); err != nil {
+		return fmt.Errorf("bq merge failed for %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// bq runs the bq CLI against the DSN's project
+func (q *bigQuery) bq(ctx context.Context, stdin *strings.Reader, args ...string) error {
+	args = append([]string{"--project_id=" + q.project, "--quiet"}, args...)
+	cmd := exec.CommandContext(ctx, "bq", args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return err
+		}
+		return fmt.Errorf("%v: %s", err, msg)
+	}
+	return nil
+}
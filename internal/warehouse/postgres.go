@@ -0,0 +1,132 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// insertBatch is the number of rows per INSERT statement
+const insertBatch = 500
+
+// postgres loads tables through the psql client, so the usual libpq
+// configuration (~/.pgpass, PGSSLMODE, service files) applies
+type postgres struct {
+	dsn      string
+	password string
+}
+
+func newPostgres(u *url.URL) *postgres {
+	p := &postgres{}
+	// Keep the password out of the process list
+	if pw, ok := u.User.Password(); ok {
+		p.password = pw
+		stripped := *u
+		stripped.User = url.User(u.User.Username())
+		u = &stripped
+	}
+	p.dsn = u.String()
+	return p
+}
+
+var pgTypes = map[string]string{
+	Int:       "bigint",
+	Text:      "text",
+	Date:      "date",
+	Timestamp: "timestamp",
+}
+
+// Plan returns the SQL script Load runs
+func (p *postgres) Plan(t Table, rows []Row) string {
+	var b strings.Builder
+
+	defs := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		defs[i] = c.Name + " " + pgTypes[c.Type]
+	}
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (%s, PRIMARY KEY (%s));\n",
+		t.Name, strings.Join(defs, ", "), strings.Join(t.Key, ", "))
+	for _, c := range t.Columns {
+		fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s;\n", t.Name, c.Name, pgTypes[c.Type])
+	}
+
+	names := make([]string, len(t.Columns))
+	var updates []string
+	for i, c := range t.Columns {
+		names[i] = c.Name
+		if !t.isKey(c.Name) {
+			updates = append(updates, c.Name+" = EXCLUDED."+c.Name)
+		}
+	}
+	conflict := "DO NOTHING"
+	if len(updates) > 0 {
+		conflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	for start := 0; start < len(rows); start += insertBatch {
+		end := start + insertBatch
+		if end > len(rows) {
+			end = len(rows)
+		}
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES\n", t.Name, strings.Join(names, ", "))
+		for i, row := range rows[start:end] {
+			values := make([]string, len(t.Columns))
+			for j, c := range t.Columns {
+				values[j] = pgLiteral(c.Type, row[c.Name])
+			}
+			sep := ","
+			if i == end-start-1 {
+				sep = ""
+			}
+			fmt.Fprintf(&b, "  (%s)%s\n", strings.Join(values, ", "), sep)
+		}
+		fmt.Fprintf(&b, "ON CONFLICT (%s) %s;\n", strings.Join(t.Key, ", "), conflict)
+	}
+	return b.String()
+}
+
+// Load runs the script in a single transaction
+func (p *postgres) Load(ctx context.Context, t Table, rows []Row) error {
+	if err := CheckTable(t); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "psql",
+		"--no-psqlrc", "--quiet", "--single-transaction",
+		"--set", "ON_ERROR_STOP=1",
+		"--file", "-",
+		"--dbname", p.dsn,
+	)
+	cmd.Stdin = strings.NewReader(p.Plan(t, rows))
+	cmd.Env = os.Environ()
+	if p.password != "" {
+		cmd.Env = append(cmd.Env, "PGPASSWORD="+p.password)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("psql failed loading %s: %v: %s", t.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// pgLiteral renders a value as an SQL literal
+func pgLiteral(typ string, v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case int:
+		return fmt.Sprint(val)
+	case string:
+		if val == "" && (typ == Date || typ == Timestamp) {
+			return "NULL"
+		}
+		return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(fmt.Sprint(v), "'", "''") + "'"
+}
@@ -0,0 +1,97 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Column types
+const (
+	Int       = "int"
+	Text      = "text"
+	Date      = "date"
+	Timestamp = "timestamp"
+)
+
+// Column is one column of a warehouse table
+type Column struct {
+	Name string
+	Type string
+}
+
+// Table describes a warehouse table. Rows are upserted by Key.
+type Table struct {
+	Name    string
+	Columns []Column
+	Key     []string
+}
+
+// Row maps column names to values: ints, strings, or "" for NULL dates
+// and timestamps
+type Row map[string]interface{}
+
+// Loader creates or extends tables and upserts rows into them
+type Loader interface {
+	// Load makes sure the table has every column, then upserts rows
+	Load(ctx context.Context, table Table, rows []Row) error
+	// Plan describes what Load would run, for --dry-run
+	Plan(table Table, rows []Row) string
+}
+
+// Open returns the loader for a DSN: postgres://user@host/db or
+// bigquery://project/dataset
+func Open(dsn string) (Loader, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return newPostgres(u), nil
+	case "bigquery":
+		dataset := strings.Trim(u.Path, "/")
+		if u.Host == "" || dataset == "" || strings.Contains(dataset, "/") {
+			return nil, fmt.Errorf("invalid BigQuery DSN %q: use bigquery://project/dataset", u.Redacted())
+		}
+		return &bigQuery{project: u.Host, dataset: dataset}, nil
+	}
+	return nil, fmt.Errorf("unsupported DSN scheme %q: use postgres:// or bigquery://", u.Scheme)
+}
+
+// CheckTable rejects table and column names that would need quoting
+func CheckTable(t Table) error {
+	if !validName(t.Name) {
+		return fmt.Errorf("invalid table name %q: use letters, digits and underscores", t.Name)
+	}
+	for _, c := range t.Columns {
+		if !validName(c.Name) {
+			return fmt.Errorf("invalid column name %q", c.Name)
+		}
+	}
+	return nil
+}
+
+func validName(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isKey reports whether a column is part of the table's key
+func (t Table) isKey(name string) bool {
+	for _, k := range t.Key {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+// Package workspace lays out a local folder per ticket with its metadata,
+// thread transcript and the engineer's notes, for offline investigation,
+// and an outbox of files to upload to the ticket.
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// Files and folders of a workspace
+const (
+	TicketFile     = "ticket.json"
+	ThreadFile     = "thread.md"
+	NotesFile      = "notes.md"
+	AttachmentsDir = "attachments"
+	// OutboxDir holds files waiting to be attached to the ticket; they
+	// move to AttachmentsDir once uploaded
+	OutboxDir = "outbox"
+)
+
+// Workspace is the folder of one ticket
+type Workspace struct {
+	Dir string
+}
+
+// New returns the workspace of a ticket number under parent, named
+// ticket-<number>
+func New(parent, number string) *Workspace {
+	return &Workspace{Dir: filepath.Join(parent, "ticket-"+number)}
+}
+
+// Find returns the workspace of a ticket under parent, by ticket number
+// (the folder name) or by the ticket ID recorded in ticket.json
+func Find(parent, id string) (*Workspace, error) {
+	w := New(parent, id)
+	if _, err := os.Stat(filepath.Join(w.Dir, TicketFile)); err == nil {
+		return w, nil
+	}
+	dirs, _ := filepath.Glob(filepath.Join(parent, "ticket-*"))
+	for _, dir := range dirs {
+		w := &Workspace{Dir: dir}
+		if ticketID, err := w.TicketID(); err == nil && strconv.Itoa(ticketID) == id {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("no workspace for ticket %s in %s: create it with osticket ticket workspace %s", id, parent, id)
+}
+
+// Init creates the workspace folders
+func (w *Workspace) Init() error {
+	for _, dir := range []string{AttachmentsDir, OutboxDir} {
+		if err := os.MkdirAll(filepath.Join(w.Dir, dir), 0700); err != nil {
+			return fmt.Errorf("could not create workspace: %w", err)
+		}
+	}
+	return nil
+}
+
+// TicketID returns the ID of the workspace's ticket from ticket.json
+func (w *Workspace) TicketID() (int, error) {
+	data, err := os.ReadFile(filepath.Join(w.Dir, TicketFile))
+	if err != nil {
+		return 0, fmt.Errorf("could not read workspace ticket: %w", err)
+	}
+	var t struct {
+		ID json.Number `json:"ticket_id"`
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return 0, fmt.Errorf("invalid workspace ticket %s: %w", w.Dir, err)
+	}
+	id, err := strconv.Atoi(string(t.ID))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("no ticket ID in %s", filepath.Join(w.Dir, TicketFile))
+	}
+	return id, nil
+}
+
+// Outbox returns the paths of the files waiting in outbox/, by name.
+// Hidden files and folders are left alone.
+func (w *Workspace) Outbox() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(w.Dir, OutboxDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		paths = append(paths, filepath.Join(w.Dir, OutboxDir, e.Name()))
+	}
+	return paths, nil
+}
+
+// Archive moves uploaded outbox files to attachments/, adding -1, -2...
+// to a name already taken there
+func (w *Workspace) Archive(paths []string) error {
+	dir := filepath.Join(w.Dir, AttachmentsDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		ext := filepath.Ext(name)
+		target := filepath.Join(dir, name)
+		for i := 1; ; i++ {
+			if _, err := os.Lstat(target); errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+			target = filepath.Join(dir, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), i, ext))
+		}
+		if err := os.Rename(path, target); err != nil {
+			return fmt.Errorf("could not move %s to %s: %w", name, AttachmentsDir, err)
+		}
+	}
+	return nil
+}
+
+// WriteTicket saves the ticket's metadata as indented JSON
+func (w *Workspace) WriteTicket(ticket map[string]interface{}) error {
+	data, err := json.MarshalIndent(ticket, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.write(TicketFile, append(data, '\n'))
+}
+
+// WriteThread saves the ticket's thread as a Markdown transcript, oldest
+// entry first
+func (w *Workspace) WriteThread(number, subject string, entries []osticket.ThreadEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Ticket #%s: %s\n", number, subject)
+	for _, e := range entries {
+		poster := e.Poster
+		if poster == "" {
+			poster = "(unknown)"
+		}
+		fmt.Fprintf(&b, "\n## %s — %s — %s\n\n", e.TypeName(), poster, e.Created)
+		if e.Title != "" {
+			fmt.Fprintf(&b, "**%s**\n\n", e.Title)
+		}
+		b.WriteString(strings.TrimSpace(e.Body) + "\n")
+	}
+	if len(entries) == 0 {
+		b.WriteString("\n_No thread entries._\n")
+	}
+	return w.write(ThreadFile, []byte(b.String()))
+}
+
+// WriteNotes starts notes.md for the engineer. Existing notes are never
+// touched; created is false when the file was already there.
+func (w *Workspace) WriteNotes(number, subject string) (created bool, err error) {
+	path := filepath.Join(w.Dir, NotesFile)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(f, "# Notes: ticket #%s\n\n%s\n\n## Findings\n\n## Next steps\n", number, subject)
+	return true, f.Close()
+}
+
+// write replaces a workspace file atomically
+func (w *Workspace) write(name string, data []byte) error {
+	path := filepath.Join(w.Dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,67 @@
+package osticket
+
+import "context"
+
+// OSTicketAPI is the set of calls Client makes, for code that should also
+// work with a fake, such as osticketest.Fake in tests. Client's
+// configuration fields and CLI-only calls (Replay) are not part of it.
+type OSTicketAPI interface {
+	// Tickets
+	GetTicket(ctx context.Context, id string) (*SimpleTicketResponse, error)
+	GetTicketRaw(ctx context.Context, id string) ([]byte, error)
+	GetTicketThread(ctx context.Context, id string) (*ThreadData, error)
+	GetTicketsBatch(ctx context.Context, ids []string, concurrency int) []BatchResult
+	GetTicketsByStatus(ctx context.Context, status int, page Page) (*SimpleTicketResponse, error)
+	GetTicketsByStatusRaw(ctx context.Context, status int, page Page) ([]byte, error)
+	GetTicketsByDateRange(ctx context.Context, startDate, endDate string, page Page) (*SimpleTicketResponse, error)
+	GetTicketsByDateRangeRaw(ctx context.Context, startDate, endDate string, page Page) ([]byte, error)
+	SearchTicketsByTerm(ctx context.Context, term, startDate, endDate string, status int, page Page) (*SimpleTicketResponse, error)
+	SearchTicketsByTermRaw(ctx context.Context, term, startDate, endDate string, status int, page Page) ([]byte, error)
+	SearchTicketsByEmail(ctx context.Context, email string, status int) (*SimpleTicketResponse, *User, error)
+	CreateTicket(ctx context.Context, params CreateTicketParams) (int, error)
+	ReplyToTicket(ctx context.Context, ticketID int, body string, staffID int, attachments ...Attachment) error
+	AddInternalNote(ctx context.Context, ticketID int, title, body string, staffID int, attachments ...Attachment) error
+	CloseTicket(ctx context.Context, params CloseTicketParams) error
+	AssignTicket(ctx context.Context, params AssignTicketParams) error
+	UpdateTicketStatus(ctx context.Context, params UpdateTicketStatusParams) error
+	UpdateTicketPriority(ctx context.Context, params UpdateTicketPriorityParams) error
+	ArchiveTicket(ctx context.Context, ticketID, staffID int, comment string) error
+	DeleteTicket(ctx context.Context, ticketID, staffID int, comment string) error
+
+	// Tasks
+	GetTicketTasks(ctx context.Context, ticketID int) (*TaskData, error)
+	CreateTask(ctx context.Context, params CreateTaskParams) (int, error)
+	CloseTask(ctx context.Context, taskID, staffID int, comment string) error
+	AssignTask(ctx context.Context, params AssignTaskParams) error
+
+	// Users and organizations
+	GetUserByID(ctx context.Context, id string) (*UserData, error)
+	GetUserByEmail(ctx context.Context, email string) (*UserData, error)
+	GetUserByEmailRaw(ctx context.Context, email string) ([]byte, error)
+	CreateUser(ctx context.Context, params CreateUserParams) (int, error)
+	UpdateUser(ctx context.Context, params UpdateUserParams) error
+	DisableUser(ctx context.Context, userID int) error
+	GetOrganization(ctx context.Context, id int) (*OrganizationData, error)
+	GetOrganizations(ctx context.Context) (*OrganizationData, error)
+	CreateOrganization(ctx context.Context, params CreateOrganizationParams) (int, error)
+	AddUserToOrganization(ctx context.Context, orgID, userID int) error
+
+	// Agents and reference data
+	GetStaff(ctx context.Context, id int) (*StaffData, error)
+	GetStaffByUsername(ctx context.Context, username string) (*StaffData, error)
+	GetStaffList(ctx context.Context) (*StaffData, error)
+	GetDepartments(ctx context.Context) (*DepartmentData, error)
+	GetTopics(ctx context.Context) (*TopicData, error)
+	GetSLAs(ctx context.Context) (*SLAData, error)
+	GetTeams(ctx context.Context) (*TeamData, error)
+	GetPriorities(ctx context.Context) (*PriorityData, error)
+	GetStatuses(ctx context.Context) (*StatusData, error)
+	GetCannedResponses(ctx context.Context) (*CannedData, error)
+
+	// Server
+	Ping(ctx context.Context) error
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+	Call(ctx context.Context, method string, req Request) ([]byte, error)
+}
+
+var _ OSTicketAPI = (*Client)(nil)
@@ -0,0 +1,75 @@
+package osticket
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxAttachmentSize is the upload limit used when none is configured
+const DefaultMaxAttachmentSize = 10 * 1024 * 1024
+
+// Attachment represents a file sent with a ticket or reply
+type Attachment struct {
+	Name string
+	Type string
+	Data []byte
+}
+
+// LoadAttachment reads a file from disk, detects its MIME type and checks
+// it against maxSize (bytes, 0 for no limit)
+func LoadAttachment(path string, maxSize int64) (*Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read attachment: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("attachment %s is a directory", path)
+	}
+	if maxSize > 0 && info.Size() > maxSize {
+		return nil, fmt.Errorf("attachment %s is %d bytes, exceeding the %d byte limit", path, info.Size(), maxSize)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read attachment: %w", err)
+	}
+
+	return &Attachment{
+		Name: filepath.Base(path),
+		Type: detectMIME(path, data),
+		Data: data,
+	}, nil
+}
+
+// detectMIME prefers the extension and falls back to content sniffing.
+// Parameters such as charset are dropped since data URLs carry none.
+func detectMIME(path string, data []byte) string {
+	t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if t == "" {
+		t = http.DetectContentType(data)
+	}
+	if mediaType, _, err := mime.ParseMediaType(t); err == nil {
+		return mediaType
+	}
+	return t
+}
+
+// DataURL encodes the attachment as a base64 data URL
+func (a Attachment) DataURL() string {
+	return "data:" + a.Type + ";base64," + base64.StdEncoding.EncodeToString(a.Data)
+}
+
+// attachmentsParam builds the osTicket API attachments payload:
+// a list of {"filename": "data:<mime>;base64,<data>"} objects
+func attachmentsParam(attachments []Attachment) []map[string]string {
+	param := make([]map[string]string, 0, len(attachments))
+	for _, a := range attachments {
+		param = append(param, map[string]string{a.Name: a.DataURL()})
+	}
+	return param
+}
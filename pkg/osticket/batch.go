@@ -0,0 +1,56 @@
+package osticket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is how many tickets GetTicketsBatch fetches at
+// once unless told otherwise
+const DefaultBatchConcurrency = 8
+
+// BatchResult is the outcome of fetching one ticket of a batch
+type BatchResult struct {
+	ID     string
+	Ticket map[string]interface{}
+	Err    error
+}
+
+// GetTicketsBatch fetches tickets by ID or number with up to concurrency
+// requests in flight. Results are in the order of ids; a ticket that
+// cannot be fetched has Err set and does not stop the others.
+func (c *Client) GetTicketsBatch(ctx context.Context, ids []string, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]BatchResult, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		results[i].ID = id
+		wg.Add(1)
+		go func(r *BatchResult) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				r.Err = fmt.Errorf("request failed: %w", ctx.Err())
+				return
+			}
+
+			data, err := c.GetTicket(ctx, r.ID)
+			switch {
+			case err != nil:
+				r.Err = err
+			case len(data.Tickets) == 0:
+				r.Err = fmt.Errorf("ticket not found")
+			default:
+				r.Ticket = data.Tickets[0]
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
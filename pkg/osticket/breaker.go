@@ -0,0 +1,101 @@
+package osticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerWindow    = time.Minute
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// Breaker fails requests fast once the server has failed repeatedly, so
+// bulk jobs stop instead of waiting out a timeout on every item. After
+// Threshold failures within Window the circuit opens for Cooldown; the
+// first request after that is let through as a trial, and a single
+// further failure opens the circuit again.
+type Breaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  []time.Time
+	openUntil time.Time
+}
+
+// NewBreaker creates a breaker with the default settings
+func NewBreaker() *Breaker {
+	return &Breaker{
+		Threshold: DefaultBreakerThreshold,
+		Window:    DefaultBreakerWindow,
+		Cooldown:  DefaultBreakerCooldown,
+	}
+}
+
+// CircuitOpenError is returned while the breaker is refusing requests
+type CircuitOpenError struct {
+	Failures   int
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("server unhealthy after %d failed requests, retry after %s",
+		e.Failures, e.RetryAfter.Round(time.Second))
+}
+
+// Allow returns a *CircuitOpenError while the circuit is open
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return &CircuitOpenError{Failures: len(b.failures), RetryAfter: wait}
+	}
+	return nil
+}
+
+// Record counts the outcome of a request against the server's health
+func (b *Breaker) Record(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if !isServerFailure(resp, err) {
+		b.failures = nil
+		b.openUntil = time.Time{}
+		return
+	}
+
+	// A failed trial after the cooldown reopens the circuit immediately
+	trial := !b.openUntil.IsZero()
+
+	cutoff := now.Add(-b.Window)
+	kept := b.failures[:0]
+	for _, t := range b.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.failures = append(kept, now)
+
+	if trial || (b.Threshold > 0 && len(b.failures) >= b.Threshold) {
+		b.openUntil = now.Add(b.Cooldown)
+	}
+}
+
+// isServerFailure reports outcomes that suggest the server is unhealthy:
+// network errors and 5xx responses. Rate limiting is not a health problem.
+func isServerFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= 500
+}
@@ -0,0 +1,85 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CannedData represents canned response data
+type CannedData struct {
+	Total     int              `json:"total"`
+	Responses []CannedResponse `json:"canned"`
+}
+
+// CannedResponse is a reply agents reuse, as kept in osTicket's canned
+// response library. Response is HTML and may contain variables such as
+// %{ticket.number}.
+type CannedResponse struct {
+	ID        int    `json:"-"` // Parsed manually due to API returning string or int
+	DeptID    int    `json:"-"`
+	IsEnabled bool   `json:"-"`
+	Title     string `json:"title"`
+	Response  string `json:"response"`
+	Notes     string `json:"notes,omitempty"`
+	Lang      string `json:"lang,omitempty"`
+	Created   string `json:"created,omitempty"`
+	Updated   string `json:"updated,omitempty"`
+}
+
+// UnmarshalJSON handles IDs and flags sent as string or int
+func (r *CannedResponse) UnmarshalJSON(data []byte) error {
+	type Alias CannedResponse
+	aux := &struct {
+		ID        interface{} `json:"canned_id"`
+		DeptID    interface{} `json:"dept_id"`
+		IsEnabled interface{} `json:"isenabled"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.ID = toInt(aux.ID)
+	r.DeptID = toInt(aux.DeptID)
+	// Plugins that leave out the flag only list enabled responses
+	r.IsEnabled = aux.IsEnabled == nil || toInt(aux.IsEnabled) == 1
+	return nil
+}
+
+// MarshalJSON includes the manually parsed fields in JSON output
+func (r CannedResponse) MarshalJSON() ([]byte, error) {
+	type Alias CannedResponse
+	return json.Marshal(&struct {
+		ID        int  `json:"canned_id"`
+		DeptID    int  `json:"dept_id"`
+		IsEnabled bool `json:"isenabled"`
+		Alias
+	}{
+		ID:        r.ID,
+		DeptID:    r.DeptID,
+		IsEnabled: r.IsEnabled,
+		Alias:     Alias(r),
+	})
+}
+
+// GetCannedResponses gets the canned response library
+func (c *Client) GetCannedResponses(ctx context.Context) (*CannedData, error) {
+	resp, err := c.cachedRequest(ctx, "POST", Request{
+		Query:      "canned",
+		Condition:  "all",
+		Sort:       "all",
+		Parameters: map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data CannedData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse canned response data: %w", err)
+	}
+
+	return &data, nil
+}
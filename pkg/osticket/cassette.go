@@ -0,0 +1,329 @@
+package osticket
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cassette is a directory of recorded API interactions, one file per
+// distinct request, so automation can be developed offline and tested
+// against real response shapes. Record saves what a server answers;
+// Replay serves it back without a network.
+//
+// Requests are matched by method and body. A request not recorded as such
+// is answered with the recording of the same call (method, query and
+// condition) when there is exactly one, so requests whose parameters
+// change between runs, such as dates, still replay; otherwise with HTTP
+// 501 and a plugin-style error naming the call. A request recorded
+// several times gets the responses in order, then the last one again.
+type Cassette struct {
+	Dir string
+
+	mu sync.Mutex
+	// loaded is set once the files have been read for replay, recording
+	// once this process has recorded to the cassette
+	loaded       bool
+	recording    bool
+	interactions map[string]*Interaction
+	served       map[string]int
+}
+
+// Interaction is one request of a cassette and the responses it got, in
+// order
+type Interaction struct {
+	// Call describes the request, e.g. "GET ticket/specific (id)"
+	Call      string             `json:"call"`
+	Method    string             `json:"method"`
+	Request   json.RawMessage    `json:"request"`
+	Responses []RecordedResponse `json:"responses"`
+}
+
+// RecordedResponse is a response as the server sent it. JSON bodies are
+// kept as JSON, so cassettes can be read and edited; anything else, such
+// as an HTML error page, is kept as Text.
+type RecordedResponse struct {
+	Status   int             `json:"status"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Text     string          `json:"text,omitempty"`
+	Recorded time.Time       `json:"recorded"`
+}
+
+// skippedHeaders are response headers not worth keeping in a cassette
+var skippedHeaders = []string{"Date", "Set-Cookie", "Content-Length", "Connection", "Keep-Alive"}
+
+// NewCassette returns the cassette in dir. Recording creates the
+// directory; replaying needs it to exist.
+func NewCassette(dir string) *Cassette {
+	return &Cassette{Dir: dir}
+}
+
+// Record wraps base in a transport that saves every request and response
+// passing through it. A request recorded by an earlier run is replaced
+// rather than added to.
+func (c *Cassette) Record(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		if err := c.save(req.Method, body, resp, respBody); err != nil {
+			return nil, fmt.Errorf("recording to cassette: %w", err)
+		}
+		return resp, nil
+	})
+}
+
+// Replay returns a transport that answers requests from the cassette
+func (c *Cassette) Replay() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+		r, ok := c.next(req.Method, body)
+		if !ok {
+			msg, _ := json.Marshal(fmt.Sprintf("no recorded response for %s in cassette %s", describeBody(req.Method, body), c.Dir))
+			r = RecordedResponse{
+				Status: http.StatusNotImplemented,
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   json.RawMessage(`{"status":"Error","message":` + string(msg) + `}`),
+			}
+		}
+		// Bodies are served compact, as servers send them, rather than
+		// as indented in the file
+		var compact bytes.Buffer
+		respBody := []byte(r.Text)
+		if r.Body != nil && json.Compact(&compact, r.Body) == nil {
+			respBody = compact.Bytes()
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+			StatusCode:    r.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        r.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	})
+}
+
+// Interactions returns the recorded interactions, ordered by call
+func (c *Cassette) Interactions() ([]*Interaction, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]*Interaction, 0, len(c.interactions))
+	for _, in := range c.interactions {
+		list = append(list, in)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Call < list[j].Call })
+	return list, nil
+}
+
+// save adds a response to the interaction of its request and writes it
+func (c *Cassette) save(method string, body []byte, resp *http.Response, respBody []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.recording {
+		c.recording = true
+		c.interactions = map[string]*Interaction{}
+	}
+
+	// The file of an earlier run's recording of the request is replaced
+	key := cassetteKey(method, body)
+	in := c.interactions[key]
+	if in == nil {
+		in = &Interaction{Call: describeBody(method, body), Method: method, Request: jsonOrString(body)}
+		c.interactions[key] = in
+	}
+
+	r := RecordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone(), Recorded: time.Now().UTC()}
+	for _, h := range skippedHeaders {
+		r.Header.Del(h)
+	}
+	if json.Valid(respBody) {
+		r.Body = respBody
+	} else {
+		r.Text = string(respBody)
+	}
+	in.Responses = append(in.Responses, r)
+
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, cassetteFile(in, key)), data, 0600)
+}
+
+// load reads the cassette's files once
+func (c *Cassette) load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded || c.recording {
+		return nil
+	}
+	if _, err := os.Stat(c.Dir); err != nil {
+		return fmt.Errorf("cassette %s: %w", c.Dir, err)
+	}
+	paths, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	c.interactions = map[string]*Interaction{}
+	c.served = map[string]int{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return fmt.Errorf("cassette file %s: %w", path, err)
+		}
+		c.interactions[cassetteKey(in.Method, requestBytes(in.Request))] = &in
+	}
+	c.loaded = true
+	return nil
+}
+
+// next returns the response to serve for a request
+func (c *Cassette) next(method string, body []byte) (RecordedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cassetteKey(method, body)
+	in := c.interactions[key]
+	if in == nil {
+		// The only recording of the same call stands in for it
+		call := callOf(method, body)
+		for k, candidate := range c.interactions {
+			if callOf(candidate.Method, requestBytes(candidate.Request)) != call {
+				continue
+			}
+			if in != nil {
+				return RecordedResponse{}, false
+			}
+			in, key = candidate, k
+		}
+	}
+	if in == nil || len(in.Responses) == 0 {
+		return RecordedResponse{}, false
+	}
+	n := c.served[key]
+	c.served[key]++
+	return in.Responses[min(n, len(in.Responses)-1)], true
+}
+
+// readBody reads a request's body and puts it back for the next reader
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// cassetteKey identifies a request by method and body. JSON bodies are
+// compacted first, as cassette files store them indented.
+func cassetteKey(method string, body []byte) string {
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	sum := sha256.Sum256(append([]byte(method+" "), body...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// unsafeFileChars are replaced in cassette file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// cassetteFile names an interaction's file after its call, e.g.
+// "get-ticket-specific-1a2b3c4d5e6f7a8b.json"
+func cassetteFile(in *Interaction, key string) string {
+	name := strings.ToLower(callOf(in.Method, requestBytes(in.Request)))
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "-"), "-")
+	return name + "-" + key + ".json"
+}
+
+// callOf returns the method, query and condition of a request, e.g.
+// "GET ticket/specific"
+func callOf(method string, body []byte) string {
+	var req Request
+	json.Unmarshal(body, &req)
+	return method + " " + req.Query + "/" + req.Condition
+}
+
+// describeBody describes an encoded request as describeCall does
+func describeBody(method string, body []byte) string {
+	var req Request
+	json.Unmarshal(body, &req)
+	return describeCall(method, req)
+}
+
+// jsonOrString keeps a request body as JSON, or as a JSON string when it
+// is not JSON
+func jsonOrString(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return append(json.RawMessage(nil), body...)
+	}
+	s, _ := json.Marshal(string(body))
+	return s
+}
+
+// requestBytes returns the body a recorded request was sent with
+func requestBytes(raw json.RawMessage) []byte {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+	return raw
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,405 @@
+package osticket_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osticket-cli-go/internal/offline"
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/osticket-cli-go/pkg/osticket/osticketest"
+)
+
+func TestNewClientRequiresURL(t *testing.T) {
+	if _, err := osticket.NewClient("", "key"); err == nil {
+		t.Fatal("NewClient with no base URL: got no error")
+	}
+}
+
+func TestGetDepartments(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/departments.json")...)
+	srv.APIKey = "s3cret"
+
+	data, err := srv.Client(t).GetDepartments(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Total != 2 || len(data.Departments) != 2 || data.Departments[1].Name != "Billing" {
+		t.Errorf("departments = %+v", data)
+	}
+}
+
+func TestWrongAPIKey(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/departments.json")...)
+	srv.APIKey = "s3cret"
+	client, err := osticket.NewClient(srv.URL+"/", "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetDepartments(context.Background())
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrAuth || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want an auth error", err)
+	}
+}
+
+func TestGetTicketNormalizes(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/ticket.json")...)
+
+	data, err := srv.Client(t).GetTicket(context.Background(), "100042")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The nested, repeated rows the plugin returns are flattened once
+	if len(data.Tickets) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(data.Tickets))
+	}
+	ticket := data.Tickets[0]
+	if ticket["number"] != "100042" || ticket["priority"] != "High" || ticket["priority_id"] != 3 {
+		t.Errorf("ticket = %v", ticket)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Query != "ticket" || reqs[0].Condition != "specific" || reqs[0].Parameters["id"] != "100042" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
+
+func TestGetTicketsByStatusNewestFirst(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/tickets_by_status.json")...)
+
+	data, err := srv.Client(t).GetTicketsByStatus(context.Background(), 1, osticket.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []string
+	for _, ticket := range data.Tickets {
+		numbers = append(numbers, ticket["number"].(string))
+	}
+	want := []string{"100042", "100041", "100040"}
+	if len(numbers) != len(want) {
+		t.Fatalf("numbers = %v, want %v", numbers, want)
+	}
+	for i := range want {
+		if numbers[i] != want[i] {
+			t.Fatalf("numbers = %v, want %v", numbers, want)
+		}
+	}
+}
+
+func TestGetTicketsByStatusPageKeepsServerOrder(t *testing.T) {
+	srv := osticketest.NewServer(t)
+	srv.Add("GET ticket/all (status)", json.RawMessage(`{"status": "Success", "data": {"total": 4, "tickets": [
+		{"ticket_id": 40, "number": "100040", "created": "2026-09-28 14:00:00"},
+		{"ticket_id": 41, "number": "100041", "created": "2026-09-30 11:20:00"}]}}`))
+
+	// Sorting the page alone would not order it against the other pages
+	data, err := srv.Client(t).GetTicketsByStatus(context.Background(), 1, osticket.Page{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tickets) != 2 || data.Tickets[0]["number"] != "100040" || data.Tickets[1]["number"] != "100041" {
+		t.Errorf("tickets = %v, want the server's order", data.Tickets)
+	}
+}
+
+func TestCreateTicketStringID(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/create_ticket.json")...)
+
+	id, err := srv.Client(t).CreateTicket(context.Background(), osticket.CreateTicketParams{
+		Title: "Printer on fire", Subject: "It is really on fire", UserID: 12,
+		PriorityID: 2, StatusID: 1, DeptID: 1, SLAID: 1, TopicID: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 777 {
+		t.Errorf("id = %d, want 777", id)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/errors.json")...)
+
+	err := srv.Client(t).ReplyToTicket(context.Background(), 404, "Hello", 1)
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrNotFound {
+		t.Fatalf("err = %v, want a not-found error", err)
+	}
+}
+
+func TestUnrecordedCall(t *testing.T) {
+	srv := osticketest.NewServer(t)
+
+	_, err := srv.Client(t).GetTopics(context.Background())
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("err = %v, want HTTP 501", err)
+	}
+}
+
+func TestNotAPIResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"web UI page", http.StatusOK, "text/html", `<!DOCTYPE html><html><head><title>Support Center</title></head><body></body></html>`},
+		{"empty body", http.StatusOK, "text/html", ""},
+		{"HTML 404", http.StatusNotFound, "text/html", `<html><head><title>Not Found</title></head><body>nope</body></html>`},
+		{"plain text", http.StatusOK, "text/plain", "OK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			client, err := osticket.NewClient(srv.URL+"/", "key")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.GetDepartments(context.Background())
+			var apiErr *osticket.Error
+			if !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrNotAPI {
+				t.Fatalf("err = %v, want ErrNotAPI", err)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`<html><head><title>Support Center</title></head><body></body></html>`))
+	})
+	mux.HandleFunc("/helpdesk/ost_wbs/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"Success","data":{"total":0,"departments":[]}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	d, err := osticket.Discover(context.Background(), srv.URL, "key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.URL != srv.URL+"/helpdesk/ost_wbs/" || d.Err != nil {
+		t.Fatalf("found %q (err %v), want %s/helpdesk/ost_wbs/", d.URL, d.Err, srv.URL)
+	}
+	for _, a := range d.Attempts[:len(d.Attempts)-1] {
+		if a.Plugin {
+			t.Errorf("%s taken for the plugin", a.URL)
+		}
+	}
+
+	// A custom path is tried before the common ones
+	d, err = osticket.Discover(context.Background(), srv.URL+"/", "key", []string{"/nowhere/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Attempts[1].URL != srv.URL+"/nowhere/" {
+		t.Errorf("second attempt = %s, want the custom path", d.Attempts[1].URL)
+	}
+}
+
+func TestDiscoverUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	d, err := osticket.Discover(context.Background(), url, "key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.URL != "" || d.Err == nil || len(d.Attempts) != 1 {
+		t.Fatalf("discovery = %+v, want one failed attempt", d)
+	}
+}
+
+func TestSearchTicketsByEmail(t *testing.T) {
+	user := json.RawMessage(`{"status":"Success","data":{"total":1,"users":[{"user_id":12,"name":"Ann Example","email":"ann@example.com"}]}}`)
+	tickets := json.RawMessage(`{"status":"Success","data":{"total":2,"tickets":[{"ticket_id":42,"number":"100042","user_id":12},{"ticket_id":43,"number":"100043","user_id":"13"}]}}`)
+
+	// The server filters by user ID
+	srv := osticketest.NewServer(t)
+	srv.Add("GET user/specific", user)
+	srv.Add("GET ticket/user", tickets)
+	data, _, err := srv.Client(t).SearchTicketsByEmail(context.Background(), "ann@example.com", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 2 || reqs[1].Condition != "user" || reqs[1].Parameters["user_id"] != float64(12) || reqs[1].Parameters["status"] != float64(1) {
+		t.Errorf("requests = %+v", reqs)
+	}
+	if len(data.Tickets) != 1 || data.Tickets[0]["number"] != "100042" {
+		t.Errorf("tickets = %v", data.Tickets)
+	}
+
+	// Older plugins: every ticket is fetched and matched locally
+	srv = osticketest.NewServer(t)
+	srv.Add("GET user/specific", user)
+	srv.Add("GET ticket/all", tickets)
+	data, _, err = srv.Client(t).SearchTicketsByEmail(context.Background(), "ann@example.com", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 3 || reqs[2].Condition != "all" {
+		t.Errorf("requests = %+v", reqs)
+	}
+	if len(data.Tickets) != 1 || data.Tickets[0]["number"] != "100042" {
+		t.Errorf("tickets = %v", data.Tickets)
+	}
+}
+
+func TestSearchTicketsByEmailNoFallback(t *testing.T) {
+	user := `{"status":"Success","data":{"total":1,"users":[{"user_id":12,"name":"Ann Example","email":"ann@example.com"}]}}`
+
+	// A user without tickets is answered as not found
+	srv := osticketest.NewServer(t)
+	srv.Add("GET user/specific", json.RawMessage(user))
+	srv.Add("GET ticket/user", json.RawMessage(`{"status":"Error","message":"No tickets found"}`))
+	data, _, err := srv.Client(t).SearchTicketsByEmail(context.Background(), "ann@example.com", 0)
+	if err != nil || data.Total != 0 {
+		t.Fatalf("user without tickets: got %+v, %v, want no tickets", data, err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 2 {
+		t.Errorf("requests = %+v, want no fallback", reqs)
+	}
+
+	// A failing server is reported, not worked around
+	var conditions []string
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req osticket.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		conditions = append(conditions, req.Query+"/"+req.Condition)
+		if req.Query == "user" {
+			w.Write([]byte(user))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"status":"Error","message":"Database unavailable"}`))
+	}))
+	defer failing.Close()
+	client, err := osticket.NewClient(failing.URL+"/", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.SearchTicketsByEmail(context.Background(), "ann@example.com", 0)
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrServer {
+		t.Errorf("server failure: got %v, want a server error", err)
+	}
+	if len(conditions) != 2 {
+		t.Errorf("requests = %v, want no fallback", conditions)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, err := osticket.NewClient(srv.URL+"/", "key", osticket.WithReadTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetDepartments(context.Background())
+	if !osticket.IsNetworkError(err) {
+		t.Errorf("slow server: got %v, want a network error", err)
+	}
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/departments.json")...)
+	recorder := srv.Client(t)
+	recorder.HTTPClient.Transport = osticket.NewCassette(dir).Record(recorder.HTTPClient.Transport)
+	want, err := recorder.GetDepartments(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	client, err := osticket.NewClient("http://replay.invalid/", "",
+		osticket.WithHTTPClient(&http.Client{Transport: osticket.NewCassette(dir).Replay()}),
+		osticket.WithRetries(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetDepartments(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != want.Total || len(got.Departments) != len(want.Departments) || got.Departments[1].Name != "Billing" {
+		t.Errorf("replayed %+v, recorded %+v", got, want)
+	}
+
+	// A call never recorded is answered like an unknown plugin query
+	_, err = client.GetTopics(context.Background())
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("err = %v, want HTTP 501", err)
+	}
+}
+
+func TestQueueOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL + "/"
+	srv.Close()
+
+	client, err := osticket.NewClient(url, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Store = offline.NewStore(t.TempDir(), t.TempDir())
+	client.QueueOnFailure = true
+
+	// Changes are queued; reads still fail
+	err = client.ReplyToTicket(context.Background(), 42, "On my way", 1)
+	var qErr *osticket.QueuedError
+	if !errors.As(err, &qErr) || !osticket.IsNetworkError(qErr.Cause) || osticket.IsNetworkError(err) {
+		t.Fatalf("reply with the server down: got %v, want a queued network error", err)
+	}
+	if entries, _ := client.Store.List(); len(entries) != 1 || entries[0].ID != qErr.ID {
+		t.Errorf("outbox = %+v, want the reply", entries)
+	}
+	if _, err := client.GetDepartments(context.Background()); errors.As(err, &qErr) || !osticket.IsNetworkError(err) {
+		t.Errorf("read with the server down: got %v, want a network error", err)
+	}
+}
+
+func TestOfflineUpdateUserQueued(t *testing.T) {
+	client, err := osticket.NewClient("http://osticket.invalid/api/", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Store = offline.NewStore(t.TempDir(), t.TempDir())
+	client.Offline = true
+
+	err = client.UpdateUser(context.Background(), osticket.UpdateUserParams{UserID: 12, Email: "ann@example.org"})
+	var qErr *osticket.QueuedError
+	if !errors.As(err, &qErr) {
+		t.Fatalf("offline user update: got %v, want a *QueuedError", err)
+	}
+	if entries, _ := client.Store.List(); len(entries) != 1 || entries[0].ID != qErr.ID {
+		t.Errorf("outbox = %+v, want the update", entries)
+	}
+}
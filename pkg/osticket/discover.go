@@ -0,0 +1,163 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DiscoveryPaths are where the API plugin is commonly installed, relative
+// to the site URL, tried in order after the URL itself
+var DiscoveryPaths = []string{
+	"ost_wbs/",
+	"api/ost_wbs/",
+	"osticket/ost_wbs/",
+	"support/ost_wbs/",
+	"helpdesk/ost_wbs/",
+	"api/",
+	"api/http.php/",
+}
+
+// discoveryProbe is the read-only call every candidate endpoint gets
+var discoveryProbe = Request{Query: "department", Condition: "all", Sort: "all", Parameters: map[string]interface{}{}}
+
+// Attempt is one candidate endpoint tried by Discover
+type Attempt struct {
+	URL string `json:"url"`
+	// Plugin reports whether the URL answered like the API plugin, even
+	// if with an error such as a rejected key
+	Plugin  bool          `json:"plugin"`
+	Error   string        `json:"error,omitempty"`
+	Elapsed time.Duration `json:"-"`
+}
+
+// Discovery is the outcome of Discover. URL is the endpoint found, empty
+// when no candidate answered like the plugin. Err is the error the found
+// endpoint answered the probe with, such as an ErrAuth for a wrong key, or
+// with no URL, the error that kept the site from being reached at all.
+type Discovery struct {
+	URL      string    `json:"url,omitempty"`
+	Err      error     `json:"-"`
+	Attempts []Attempt `json:"attempts"`
+}
+
+// Discover finds the API plugin's endpoint from a site URL such as
+// https://support.example.com, trying the URL itself, then extra paths
+// and DiscoveryPaths under it, and stopping at the first that answers like
+// the plugin, or after the first when the site cannot be reached.
+// Candidates are probed with a read-only call, once each;
+// opts configure the client as for NewClient. An error is only returned
+// for an invalid site URL or a cancelled context.
+func Discover(ctx context.Context, siteURL, apiKey string, extra []string, opts ...Option) (*Discovery, error) {
+	candidates, err := discoveryCandidates(siteURL, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Discovery{}
+	opts = append(opts, WithRetries(0, 0))
+	for _, candidate := range candidates {
+		client, err := NewClient(candidate, apiKey, opts...)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := client.doRequest(ctx, discoveryProbe)
+		attempt := Attempt{URL: candidate, Plugin: answeredByPlugin(resp, err), Elapsed: time.Since(start)}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request failed: %w", ctx.Err())
+		}
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		d.Attempts = append(d.Attempts, attempt)
+		if attempt.Plugin {
+			d.URL = candidate
+			d.Err = err
+			break
+		}
+		// Every path is on the same host: if the site itself cannot be
+		// reached, neither can they
+		if len(d.Attempts) == 1 && IsNetworkError(err) {
+			d.Err = err
+			break
+		}
+	}
+	return d, nil
+}
+
+// Ping checks that the client's endpoint answers as the API plugin, with
+// the read-only call Discover probes candidates with, bypassing the cache
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.doRequest(ctx, discoveryProbe)
+	return err
+}
+
+// SiteURL returns the site root of an endpoint URL, for rediscovering the
+// endpoint of a misconfigured profile
+func SiteURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host + "/"
+}
+
+// discoveryCandidates lists the URLs to probe for a site, without
+// duplicates. A site given without a scheme is taken as https.
+func discoveryCandidates(siteURL string, extra []string) ([]string, error) {
+	siteURL = strings.TrimSpace(siteURL)
+	if !strings.Contains(siteURL, "://") {
+		siteURL = "https://" + siteURL
+	}
+	base, err := url.Parse(siteURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid site URL %q: use e.g. https://support.example.com", siteURL)
+	}
+	base.RawQuery, base.Fragment = "", ""
+
+	seen := map[string]bool{}
+	var candidates []string
+	add := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			candidates = append(candidates, u)
+		}
+	}
+	add(base.String())
+
+	// Paths resolve against the site root and, when the URL has a path of
+	// its own (an osTicket installed under /support/), against that too
+	root := *base
+	root.Path = "/"
+	dir := *base
+	if !strings.HasSuffix(dir.Path, "/") {
+		dir.Path += "/"
+	}
+	for _, path := range append(append([]string{}, extra...), DiscoveryPaths...) {
+		path = strings.TrimLeft(path, "/")
+		if dir.Path != "/" {
+			add(dir.JoinPath(path).String())
+		}
+		add(root.JoinPath(path).String())
+	}
+	return candidates, nil
+}
+
+// answeredByPlugin reports whether a probe's outcome came from the API
+// plugin: a response with its status field, or an osTicket error carried
+// in JSON. Other JSON APIs on the site parse without a status.
+func answeredByPlugin(resp *Response, err error) bool {
+	if err == nil {
+		return resp.Status != ""
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Kind == ErrNotAPI {
+		return false
+	}
+	return apiErr.StatusCode == 0 || json.Valid(apiErr.Body)
+}
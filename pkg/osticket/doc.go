@@ -0,0 +1,25 @@
+// Package osticket is a client for the osTicket API plugin, the same one
+// the osticket CLI uses, for Go programs that would otherwise shell out to
+// the CLI.
+//
+// The plugin takes every call as a JSON body naming a query, a condition
+// and parameters; Client wraps those calls in typed methods. Every method
+// takes a context, which cancels the request and any retry wait:
+//
+//	client, err := osticket.NewClient("https://support.example.com/api/", apiKey,
+//		osticket.WithTimeout(10*time.Second),
+//		osticket.WithRetries(2, time.Second),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	data, err := client.GetTicket(ctx, "100042")
+//
+// Failed calls return an *Error when the server answered, whose Kind tells
+// authentication, not-found, validation and server errors apart; use
+// IsNetworkError for requests that never got an answer.
+//
+// The exported API follows semantic versioning with the CLI's releases.
+// The Client fields behind the CLI's offline mode and list cache (Offline,
+// Store and Cache) use internal types and are not part of it.
+package osticket
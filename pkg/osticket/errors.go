@@ -0,0 +1,176 @@
+package osticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrorKind classifies an API error so callers can react to it without
+// matching messages
+type ErrorKind int
+
+const (
+	ErrUnknown ErrorKind = iota
+	// ErrAuth means the API key was missing, wrong or not allowed the call
+	ErrAuth
+	// ErrNotFound means the ticket, user or other object does not exist
+	ErrNotFound
+	// ErrValidation means the server rejected the request's parameters
+	ErrValidation
+	// ErrServer means the server failed to handle a valid request
+	ErrServer
+	// ErrNotAPI means the URL answered with something other than the API
+	// plugin's JSON, such as the web UI's HTML pages
+	ErrNotAPI
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrAuth:
+		return "auth"
+	case ErrNotFound:
+		return "not_found"
+	case ErrValidation:
+		return "validation"
+	case ErrServer:
+		return "server"
+	case ErrNotAPI:
+		return "not_api"
+	}
+	return "unknown"
+}
+
+// Error is returned by Client methods when the server answers with an
+// HTTP error status or an osTicket error response. StatusCode is 0 for
+// errors reported in a successful HTTP response, and RequestID is empty
+// unless the server sent one. Body is the response as received.
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+	Kind       ErrorKind
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	msg := "API error: " + e.Message
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("API error (HTTP %d): %s", e.StatusCode, e.Message)
+	}
+	if e.RequestID != "" {
+		msg += " (request ID " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// requestIDHeaders are the response headers servers and proxies commonly
+// put a request ID in
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// newError creates the Error for a response with an error status or an
+// osTicket error message
+func newError(resp *http.Response, message string) *Error {
+	e := &Error{Message: message}
+	if resp != nil {
+		if resp.StatusCode >= 400 {
+			e.StatusCode = resp.StatusCode
+		}
+		for _, h := range requestIDHeaders {
+			if id := resp.Header.Get(h); id != "" {
+				e.RequestID = id
+				break
+			}
+		}
+	}
+	e.Kind = errorKind(e.StatusCode, message)
+	return e
+}
+
+// httpError creates the Error for a response with an HTTP error status,
+// taking the message from an osTicket error body when there is one
+func httpError(resp *http.Response, body []byte) *Error {
+	var apiResp Response
+	message := ""
+	html := isHTML(bytes.TrimSpace(body))
+	if json.Unmarshal(body, &apiResp) == nil && apiResp.Message != "" {
+		message = apiResp.Message
+	} else if text := strings.TrimSpace(string(body)); text != "" && len(text) <= 200 && !strings.HasPrefix(text, "<") {
+		message = text
+	} else if html {
+		message = http.StatusText(resp.StatusCode) + ", with an HTML page" + pageTitle(body)
+	} else {
+		message = http.StatusText(resp.StatusCode)
+	}
+	e := newError(resp, message)
+	// The plugin answers unknown calls with JSON; an HTML 404 or 405 comes
+	// from a URL that is not the API endpoint
+	if html && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed) {
+		e.Kind = ErrNotAPI
+		e.Message += ": the URL points at the web UI or another page, not the API endpoint"
+	}
+	e.Body = body
+	return e
+}
+
+// ResponseError returns the error of an osTicket error response body, or
+// nil when body is not one
+func ResponseError(body []byte) *Error {
+	var apiResp Response
+	if json.Unmarshal(body, &apiResp) != nil || apiResp.Status != "Error" {
+		return nil
+	}
+	e := newError(nil, apiResp.Message)
+	e.Body = body
+	return e
+}
+
+// IsNetworkError reports whether err means the server could not be
+// reached or did not answer in time, including while the circuit breaker
+// refuses requests. A request cancelled by the caller is not one.
+func IsNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	var openErr *CircuitOpenError
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.As(err, &openErr) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// errorKind classifies an error by its HTTP status, or by its message when
+// the server reported it with a successful status, as the osTicket API
+// plugin does for most errors
+func errorKind(status int, message string) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case status >= 500:
+		return ErrServer
+	case status >= 400:
+		return ErrUnknown
+	}
+
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "api key"), strings.Contains(msg, "unauthorized"), strings.Contains(msg, "permission"):
+		return ErrAuth
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "does not exist"), strings.Contains(msg, "no such"),
+		strings.HasPrefix(msg, "no ") && strings.Contains(msg, " found"):
+		return ErrNotFound
+	case strings.Contains(msg, "required"), strings.Contains(msg, "invalid"), strings.Contains(msg, "missing"):
+		return ErrValidation
+	}
+	return ErrUnknown
+}
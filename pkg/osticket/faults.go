@@ -0,0 +1,203 @@
+package osticket
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Faults describes degraded API conditions to simulate, so scripts can be
+// tested against slow and failing servers before they meet one. It is a
+// development aid, never meant for production use.
+type Faults struct {
+	// Latency is added to every request
+	Latency time.Duration
+	// P50Latency adds a random, exponentially distributed delay with this
+	// median to every request, so some requests are much slower
+	P50Latency time.Duration
+	// ErrorRate is the fraction of requests answered with ErrorStatus
+	// without reaching the server
+	ErrorRate float64
+	// ErrorStatus is the HTTP status of injected errors (default 503)
+	ErrorStatus int
+	// ResetRate is the fraction of requests that fail with a connection
+	// reset after they were sent
+	ResetRate float64
+	// Seed makes the injected faults repeatable (0 picks a random seed)
+	Seed int64
+}
+
+// faultKeys are the keys ParseFaults accepts, for error messages
+var faultKeys = []string{"latency", "p50-latency", "error-rate", "error-status", "reset-rate", "seed"}
+
+// ParseFaults parses a comma-separated fault spec such as
+// "p50-latency=2s,error-rate=0.1"
+func ParseFaults(spec string) (Faults, error) {
+	f := Faults{ErrorStatus: http.StatusServiceUnavailable}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return f, fmt.Errorf("invalid fault %q, expected key=value", part)
+		}
+
+		var err error
+		switch key {
+		case "latency":
+			f.Latency, err = time.ParseDuration(value)
+			if err == nil && f.Latency < 0 {
+				err = fmt.Errorf("cannot be negative")
+			}
+		case "p50-latency":
+			f.P50Latency, err = time.ParseDuration(value)
+			if err == nil && f.P50Latency < 0 {
+				err = fmt.Errorf("cannot be negative")
+			}
+		case "error-rate":
+			f.ErrorRate, err = parseRate(value)
+		case "reset-rate":
+			f.ResetRate, err = parseRate(value)
+		case "error-status":
+			f.ErrorStatus, err = strconv.Atoi(value)
+			if err == nil && (f.ErrorStatus < 400 || f.ErrorStatus > 599) {
+				err = fmt.Errorf("must be an HTTP error status (400-599)")
+			}
+		case "seed":
+			f.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return f, fmt.Errorf("unknown fault %q: use %s", key, strings.Join(faultKeys, ", "))
+		}
+		if err != nil {
+			return f, fmt.Errorf("invalid fault %s=%s: %w", key, value, err)
+		}
+	}
+	if f.ErrorRate+f.ResetRate > 1 {
+		return f, fmt.Errorf("error-rate and reset-rate add up to more than 1")
+	}
+	return f, nil
+}
+
+// parseRate parses a fraction between 0 and 1
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("must be a fraction between 0 and 1")
+	}
+	return rate, nil
+}
+
+// String describes the faults in the syntax ParseFaults accepts
+func (f Faults) String() string {
+	parts := map[string]string{}
+	if f.Latency > 0 {
+		parts["latency"] = f.Latency.String()
+	}
+	if f.P50Latency > 0 {
+		parts["p50-latency"] = f.P50Latency.String()
+	}
+	if f.ErrorRate > 0 {
+		parts["error-rate"] = strconv.FormatFloat(f.ErrorRate, 'g', -1, 64)
+		parts["error-status"] = strconv.Itoa(f.ErrorStatus)
+	}
+	if f.ResetRate > 0 {
+		parts["reset-rate"] = strconv.FormatFloat(f.ResetRate, 'g', -1, 64)
+	}
+	if f.Seed != 0 {
+		parts["seed"] = strconv.FormatInt(f.Seed, 10)
+	}
+	out := make([]string, 0, len(parts))
+	for k, v := range parts {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+// Transport wraps base in a transport that injects the faults
+func (f Faults) Transport(base http.RoundTripper) http.RoundTripper {
+	seed := f.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &faultTransport{faults: f, base: base, rng: rand.New(rand.NewSource(seed))}
+}
+
+// faultTransport injects faults into the requests passing through it
+type faultTransport struct {
+	faults Faults
+	base   http.RoundTripper
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// draw returns the delay for a request and a uniform number deciding
+// whether it fails
+func (t *faultTransport) draw() (time.Duration, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delay := t.faults.Latency
+	if t.faults.P50Latency > 0 {
+		// The median of an exponential distribution is its mean * ln 2
+		mean := float64(t.faults.P50Latency) / math.Ln2
+		delay += time.Duration(t.rng.ExpFloat64() * mean)
+	}
+	return delay, t.rng.Float64()
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay, roll := t.draw()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if roll < t.faults.ErrorRate {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		body := fmt.Sprintf(`{"status":"Error","message":"injected fault: HTTP %d"}`, t.faults.ErrorStatus)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", t.faults.ErrorStatus, http.StatusText(t.faults.ErrorStatus)),
+			StatusCode:    t.faults.ErrorStatus,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}, "X-Injected-Fault": {"error"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil && roll < t.faults.ErrorRate+t.faults.ResetRate {
+		// The server has handled the request; only the answer is lost
+		resp.Body.Close()
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("injected fault: %w", syscall.ECONNRESET)}
+	}
+	return resp, err
+}
@@ -0,0 +1,79 @@
+package osticket
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds each HTTP round trip of a client
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a client created by NewClient
+type Option func(*options)
+
+// options collects the Options given to NewClient
+type options struct {
+	conn       Connection
+	httpClient *http.Client
+	timeout    time.Duration
+	connect    time.Duration
+	read       time.Duration
+	retries    int
+	retryWait  time.Duration
+	rate       float64
+	logger     *slog.Logger
+}
+
+// WithConnection reaches the server through a proxy, with extra CAs or a
+// client certificate. It is ignored when WithHTTPClient is given.
+func WithConnection(conn Connection) Option {
+	return func(o *options) { o.conn = conn }
+}
+
+// WithHTTPClient sends requests with hc, e.g. one with a custom
+// transport, keeping its timeout unless WithTimeout is given. The client
+// is copied, so later changes to hc do not apply.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) { o.httpClient = hc }
+}
+
+// WithTimeout bounds each HTTP round trip, retries counted separately
+// (default DefaultTimeout; 0 means no timeout)
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithConnectTimeout bounds connecting to the server, TLS handshake
+// included, so an unreachable server fails fast even when WithTimeout
+// allows slow answers. It is ignored when WithHTTPClient is given.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(o *options) { o.connect = d }
+}
+
+// WithReadTimeout bounds the wait for the server to start answering once
+// a request is sent. It is ignored when WithHTTPClient is given.
+func WithReadTimeout(d time.Duration) Option {
+	return func(o *options) { o.read = d }
+}
+
+// WithRetries retries failed requests up to n times, waiting wait before
+// the first retry and twice as long before each next one. Requests that
+// change data are only retried when the server cannot have handled them.
+func WithRetries(n int, wait time.Duration) Option {
+	return func(o *options) {
+		o.retries = n
+		o.retryWait = wait
+	}
+}
+
+// WithRateLimit sends at most perSecond requests per second, retries
+// included
+func WithRateLimit(perSecond float64) Option {
+	return func(o *options) { o.rate = perSecond }
+}
+
+// WithLogger sends diagnostics such as retries to l
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) { o.logger = l }
+}
@@ -0,0 +1,67 @@
+package osticket
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// normalizeTickets drops repeated tickets and orders the rest newest
+// first by creation time, then by ticket ID, so the same search gives the
+// same output on every run. It is for complete result sets: sorting a
+// single page would not order it against the other pages.
+func normalizeTickets(tickets []map[string]interface{}) []map[string]interface{} {
+	unique := dedupeTickets(tickets)
+	sort.SliceStable(unique, func(i, j int) bool {
+		a, b := ticketString(unique[i], "created"), ticketString(unique[j], "created")
+		if a != b {
+			// Timestamps are "YYYY-MM-DD HH:MM:SS", which sort as strings
+			return a > b
+		}
+		return toInt(unique[i]["ticket_id"]) > toInt(unique[j]["ticket_id"])
+	})
+	return unique
+}
+
+// dedupeTickets drops repeated tickets, which the nested response can
+// contain, keeping the order of the rest. Tickets without an ID or number
+// are kept as they are.
+func dedupeTickets(tickets []map[string]interface{}) []map[string]interface{} {
+	seen := make(map[string]bool, len(tickets))
+	unique := tickets[:0]
+	for _, t := range tickets {
+		key := ticketKey(t)
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		unique = append(unique, t)
+	}
+	return unique
+}
+
+// ticketKey identifies a ticket by its ID, or its number when the ID is
+// missing
+func ticketKey(t map[string]interface{}) string {
+	if id := toInt(t["ticket_id"]); id != 0 {
+		return "id:" + strconv.Itoa(id)
+	}
+	if number := ticketString(t, "number"); number != "" {
+		return "number:" + number
+	}
+	return ""
+}
+
+// ticketString returns a ticket field as a string
+func ticketString(t map[string]interface{}, key string) string {
+	switch v := t[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
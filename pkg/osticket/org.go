@@ -0,0 +1,136 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// OrganizationData represents organization response data
+type OrganizationData struct {
+	Total         int            `json:"total"`
+	Organizations []Organization `json:"organizations"`
+}
+
+// Organization represents a single organization
+type Organization struct {
+	ID      int    `json:"-"` // Parsed manually due to API returning string or int
+	Name    string `json:"name"`
+	Domain  string `json:"domain"`
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
+// UnmarshalJSON handles id as string or int
+func (o *Organization) UnmarshalJSON(data []byte) error {
+	type Alias Organization
+	aux := &struct {
+		ID interface{} `json:"id"`
+		*Alias
+	}{
+		Alias: (*Alias)(o),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.ID = toInt(aux.ID)
+	return nil
+}
+
+// MarshalJSON includes the manually parsed ID in JSON output
+func (o Organization) MarshalJSON() ([]byte, error) {
+	type Alias Organization
+	return json.Marshal(&struct {
+		ID int `json:"id"`
+		Alias
+	}{
+		ID:    o.ID,
+		Alias: Alias(o),
+	})
+}
+
+// GetOrganization gets an organization by ID
+func (c *Client) GetOrganization(ctx context.Context, id int) (*OrganizationData, error) {
+	return c.getOrganizations(ctx, Request{
+		Query:      "organization",
+		Condition:  "specific",
+		Sort:       "id",
+		Parameters: map[string]interface{}{"id": id},
+	})
+}
+
+// GetOrganizations gets all organizations
+func (c *Client) GetOrganizations(ctx context.Context) (*OrganizationData, error) {
+	return c.getOrganizations(ctx, Request{
+		Query:      "organization",
+		Condition:  "all",
+		Sort:       "all",
+		Parameters: map[string]interface{}{},
+	})
+}
+
+func (c *Client) getOrganizations(ctx context.Context, req Request) (*OrganizationData, error) {
+	resp, err := c.doGetRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data OrganizationData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse organization data: %w", err)
+	}
+	return &data, nil
+}
+
+// CreateOrganizationParams contains parameters for creating an organization
+type CreateOrganizationParams struct {
+	Name   string
+	Domain string
+	Notes  string
+}
+
+// CreateOrganization creates an organization and returns its ID
+func (c *Client) CreateOrganization(ctx context.Context, params CreateOrganizationParams) (int, error) {
+	if params.Name == "" {
+		return 0, fmt.Errorf("organization name is required")
+	}
+
+	parameters := map[string]interface{}{
+		"name": params.Name,
+	}
+	if params.Domain != "" {
+		parameters["domain"] = params.Domain
+	}
+	if params.Notes != "" {
+		parameters["notes"] = params.Notes
+	}
+
+	resp, err := c.doRequest(ctx, Request{
+		Query:      "organization",
+		Condition:  "add",
+		Parameters: parameters,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// API returns the ID as string or int
+	var raw interface{}
+	if err := json.Unmarshal(resp.Data, &raw); err != nil {
+		return 0, fmt.Errorf("failed to parse organization ID: %w", err)
+	}
+	return toInt(raw), nil
+}
+
+// AddUserToOrganization makes a user a member of an organization
+func (c *Client) AddUserToOrganization(ctx context.Context, orgID, userID int) error {
+	_, err := c.doRequest(ctx, Request{
+		Query:     "organization",
+		Condition: "add_user",
+		Parameters: map[string]interface{}{
+			"org_id":  orgID,
+			"user_id": userID,
+		},
+	})
+	return err
+}
@@ -0,0 +1,12 @@
+// Package osticketest provides test doubles for code using the osticket
+// package: Fake, an in-memory osticket.OSTicketAPI for code that takes
+// the interface, and Server, an httptest server replaying recorded API
+// plugin responses for code that needs a real *osticket.Client.
+//
+//	fake := osticketest.NewFake()
+//	fake.Users = append(fake.Users, osticket.User{UserID: 7, Name: "Ann", Email: "ann@example.com"})
+//	id, err := fake.CreateTicket(ctx, osticket.CreateTicketParams{Title: "Printer", Subject: "It is on fire", UserID: 7})
+//
+//	srv := osticketest.NewServer(t, osticketest.Load(t, "testdata/departments.json")...)
+//	data, err := srv.Client(t).GetDepartments(ctx)
+package osticketest
@@ -0,0 +1,1000 @@
+package osticketest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// Fake is an in-memory osticket.OSTicketAPI. Its exported fields are the
+// data it serves: set them before use, and read them afterwards to check
+// what calls changed. Tickets are maps of the fields the API returns,
+// such as ticket_id, number, subject, user_id, status_id and created;
+// like the real client, the Fake returns copies decoded from JSON, so
+// numbers come back as float64. A Fake is safe for concurrent use.
+type Fake struct {
+	Tickets       []map[string]interface{}
+	Threads       map[int][]osticket.ThreadEntry
+	Users         []osticket.User
+	Organizations []osticket.Organization
+	// Members maps organization IDs to the IDs of their users
+	Members     map[int][]int
+	Staff       []osticket.Staff
+	Departments []osticket.Department
+	Topics      []osticket.Topic
+	SLAs        []osticket.SLA
+	Teams       []osticket.Team
+	Priorities  []osticket.Priority
+	Statuses    []osticket.Status
+	Canned      []osticket.CannedResponse
+	Tasks       []osticket.Task
+
+	// Responses answer Call, keyed by "query/condition", with the raw
+	// response body; other calls fail like the plugin's unknown queries
+	Responses map[string][]byte
+	// Errors makes the named method fail with the error, e.g.
+	// Errors["CreateTicket"] = &osticket.Error{Kind: osticket.ErrValidation}
+	Errors map[string]error
+
+	// Now stamps created tickets and thread entries (default time.Now)
+	Now func() time.Time
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// NewFake returns a Fake with the reference data of a fresh osTicket
+// install: one department, topic, SLA and agent, the default priorities
+// and statuses, and no tickets or users.
+func NewFake() *Fake {
+	return &Fake{
+		Threads: map[int][]osticket.ThreadEntry{},
+		Members: map[int][]int{},
+		Staff: []osticket.Staff{
+			{StaffID: 1, DeptID: 1, IsActive: true, IsAdmin: true, Username: "admin", Firstname: "Admin", Lastname: "User", Email: "admin@example.com"},
+		},
+		Departments: []osticket.Department{{ID: 1, Name: "Support"}},
+		Topics:      []osticket.Topic{{TopicID: 1, Topic: "General Inquiry"}},
+		SLAs:        []osticket.SLA{{ID: 1, Name: "Default SLA", GracePeriod: 18}},
+		Teams:       []osticket.Team{{ID: 1, Name: "Level I Support"}},
+		Priorities: []osticket.Priority{
+			{ID: 1, Name: "Low", Urgency: 4},
+			{ID: 2, Name: "Normal", Urgency: 3},
+			{ID: 3, Name: "High", Urgency: 2},
+			{ID: 4, Name: "Emergency", Urgency: 1},
+		},
+		Statuses: []osticket.Status{
+			{ID: 1, Name: "Open", State: "open", Sort: 1},
+			{ID: 2, Name: "Resolved", State: "closed", Sort: 2},
+			{ID: 3, Name: "Closed", State: "closed", Sort: 3},
+			{ID: 4, Name: "Archived", State: "archived", Sort: 4},
+			{ID: 5, Name: "Deleted", State: "deleted", Sort: 5},
+		},
+	}
+}
+
+var _ osticket.OSTicketAPI = (*Fake)(nil)
+
+// Calls returns the names of the methods called so far, in order
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// begin records a call and returns the error it should fail with. It
+// locks the Fake; the caller unlocks it.
+func (f *Fake) begin(method string) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, method)
+	return f.Errors[method]
+}
+
+func (f *Fake) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+// notFound is the error the plugin answers for a missing object
+func notFound(what string, id interface{}) error {
+	return &osticket.Error{Kind: osticket.ErrNotFound, Message: fmt.Sprintf("%s %v not found", what, id)}
+}
+
+// invalid is the error the plugin answers for rejected parameters
+func invalid(format string, a ...interface{}) error {
+	return &osticket.Error{Kind: osticket.ErrValidation, Message: fmt.Sprintf(format, a...)}
+}
+
+// copyTicket returns a copy of a ticket as decoded from its JSON, as the
+// real client would return it
+func copyTicket(t map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	data, _ := json.Marshal(t)
+	json.Unmarshal(data, &out)
+	return out
+}
+
+// success wraps data in the plugin's response envelope
+func success(data interface{}) []byte {
+	body, _ := json.Marshal(map[string]interface{}{"status": "Success", "data": data})
+	return body
+}
+
+// ticketResponse copies tickets into a response as the real client
+// returns them: newest first, with priority names, then paged
+func (f *Fake) ticketResponse(tickets []map[string]interface{}, page osticket.Page) *osticket.SimpleTicketResponse {
+	copies := make([]map[string]interface{}, 0, len(tickets))
+	for _, t := range tickets {
+		c := copyTicket(t)
+		if id := intField(c, "priority_id"); id != 0 {
+			c["priority_id"] = id
+			for _, p := range f.Priorities {
+				if p.ID == id {
+					c["priority"] = p.Name
+				}
+			}
+		}
+		copies = append(copies, c)
+	}
+	sort.SliceStable(copies, func(i, j int) bool {
+		a, b := fmt.Sprint(copies[i]["created"]), fmt.Sprint(copies[j]["created"])
+		if a != b {
+			return a > b
+		}
+		return intField(copies[i], "ticket_id") > intField(copies[j], "ticket_id")
+	})
+	if page.Limit > 0 {
+		start := min(page.Offset, len(copies))
+		copies = copies[start:min(start+page.Limit, len(copies))]
+	}
+	return &osticket.SimpleTicketResponse{Total: len(copies), Tickets: copies}
+}
+
+// intField reads a numeric ticket field set as an int, float or string
+func intField(t map[string]interface{}, key string) int {
+	switch v := t[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// ticket finds a ticket by ID or number
+func (f *Fake) ticket(id string) map[string]interface{} {
+	for _, t := range f.Tickets {
+		if strconv.Itoa(intField(t, "ticket_id")) == id || fmt.Sprint(t["number"]) == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// ticketByID finds a ticket by ID
+func (f *Fake) ticketByID(id int) (map[string]interface{}, error) {
+	if t := f.ticket(strconv.Itoa(id)); t != nil && intField(t, "ticket_id") == id {
+		return t, nil
+	}
+	return nil, notFound("ticket", id)
+}
+
+// filterTickets returns the tickets keep accepts
+func (f *Fake) filterTickets(keep func(map[string]interface{}) bool) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, t := range f.Tickets {
+		if keep(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// createdIn reports whether a ticket was created between two dates,
+// inclusive, compared as YYYY-MM-DD strings
+func createdIn(t map[string]interface{}, startDate, endDate string) bool {
+	created := fmt.Sprint(t["created"])
+	day := created[:min(len(created), 10)]
+	return (startDate == "" || day >= startDate) && (endDate == "" || day <= endDate)
+}
+
+// statusID returns the ID of the first status in a state
+func (f *Fake) statusID(state string) (int, error) {
+	for _, s := range f.Statuses {
+		if s.State == state {
+			return s.ID, nil
+		}
+	}
+	return 0, invalid("no %s status", state)
+}
+
+// hasPriority reports whether a priority exists
+func (f *Fake) hasPriority(id int) bool {
+	for _, p := range f.Priorities {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// nextID returns one more than the largest id
+func nextID(ids ...int) int {
+	last := 0
+	for _, id := range ids {
+		last = max(last, id)
+	}
+	return last + 1
+}
+
+func (f *Fake) GetTicket(ctx context.Context, id string) (*osticket.SimpleTicketResponse, error) {
+	err := f.begin("GetTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if t := f.ticket(id); t != nil {
+		return f.ticketResponse([]map[string]interface{}{t}, osticket.Page{}), nil
+	}
+	return f.ticketResponse(nil, osticket.Page{}), nil
+}
+
+func (f *Fake) GetTicketRaw(ctx context.Context, id string) ([]byte, error) {
+	data, err := f.GetTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return success(data), nil
+}
+
+func (f *Fake) GetTicketThread(ctx context.Context, id string) (*osticket.ThreadData, error) {
+	err := f.begin("GetTicketThread")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	t := f.ticket(id)
+	if t == nil {
+		return nil, notFound("ticket", id)
+	}
+	entries := append([]osticket.ThreadEntry{}, f.Threads[intField(t, "ticket_id")]...)
+	return &osticket.ThreadData{Total: len(entries), Entries: entries}, nil
+}
+
+func (f *Fake) GetTicketsBatch(ctx context.Context, ids []string, concurrency int) []osticket.BatchResult {
+	results := make([]osticket.BatchResult, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+		data, err := f.GetTicket(ctx, id)
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case len(data.Tickets) == 0:
+			results[i].Err = fmt.Errorf("ticket not found")
+		default:
+			results[i].Ticket = data.Tickets[0]
+		}
+	}
+	return results
+}
+
+// GetTicketsByStatus lists the tickets of a status, or all tickets for
+// status 0
+func (f *Fake) GetTicketsByStatus(ctx context.Context, status int, page osticket.Page) (*osticket.SimpleTicketResponse, error) {
+	err := f.begin("GetTicketsByStatus")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.ticketResponse(f.filterTickets(func(t map[string]interface{}) bool {
+		return status == 0 || intField(t, "status_id") == status
+	}), page), nil
+}
+
+func (f *Fake) GetTicketsByStatusRaw(ctx context.Context, status int, page osticket.Page) ([]byte, error) {
+	data, err := f.GetTicketsByStatus(ctx, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return success(data), nil
+}
+
+func (f *Fake) GetTicketsByDateRange(ctx context.Context, startDate, endDate string, page osticket.Page) (*osticket.SimpleTicketResponse, error) {
+	err := f.begin("GetTicketsByDateRange")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.ticketResponse(f.filterTickets(func(t map[string]interface{}) bool {
+		return createdIn(t, startDate, endDate)
+	}), page), nil
+}
+
+func (f *Fake) GetTicketsByDateRangeRaw(ctx context.Context, startDate, endDate string, page osticket.Page) ([]byte, error) {
+	data, err := f.GetTicketsByDateRange(ctx, startDate, endDate, page)
+	if err != nil {
+		return nil, err
+	}
+	return success(data), nil
+}
+
+// SearchTicketsByTerm matches the term in subjects, case-insensitively
+func (f *Fake) SearchTicketsByTerm(ctx context.Context, term, startDate, endDate string, status int, page osticket.Page) (*osticket.SimpleTicketResponse, error) {
+	err := f.begin("SearchTicketsByTerm")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	term = strings.ToLower(term)
+	return f.ticketResponse(f.filterTickets(func(t map[string]interface{}) bool {
+		return strings.Contains(strings.ToLower(fmt.Sprint(t["subject"])), term) &&
+			createdIn(t, startDate, endDate) && (status <= 0 || intField(t, "status_id") == status)
+	}), page), nil
+}
+
+func (f *Fake) SearchTicketsByTermRaw(ctx context.Context, term, startDate, endDate string, status int, page osticket.Page) ([]byte, error) {
+	data, err := f.SearchTicketsByTerm(ctx, term, startDate, endDate, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return success(data), nil
+}
+
+func (f *Fake) SearchTicketsByEmail(ctx context.Context, email string, status int) (*osticket.SimpleTicketResponse, *osticket.User, error) {
+	err := f.begin("SearchTicketsByEmail")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	user := f.userByEmail(email)
+	if user == nil {
+		return f.ticketResponse(nil, osticket.Page{}), nil, nil
+	}
+	return f.ticketResponse(f.filterTickets(func(t map[string]interface{}) bool {
+		return intField(t, "user_id") == user.UserID && (status <= 0 || intField(t, "status_id") == status)
+	}), osticket.Page{}), user, nil
+}
+
+// CreateTicket adds a ticket, numbered from 100001. As the plugin does,
+// the title becomes the ticket's subject and the subject parameter its
+// first message.
+func (f *Fake) CreateTicket(ctx context.Context, params osticket.CreateTicketParams) (int, error) {
+	err := f.begin("CreateTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if params.Title == "" || params.Subject == "" {
+		return 0, invalid("title and subject are required")
+	}
+	if f.user(params.UserID) == nil {
+		return 0, invalid("user %d does not exist", params.UserID)
+	}
+	ids := make([]int, len(f.Tickets))
+	for i, t := range f.Tickets {
+		ids[i] = intField(t, "ticket_id")
+	}
+	id := nextID(ids...)
+	created := f.now().Format("2006-01-02 15:04:05")
+	ticket := map[string]interface{}{
+		"ticket_id":   id,
+		"number":      strconv.Itoa(100000 + id),
+		"subject":     params.Title,
+		"user_id":     params.UserID,
+		"priority_id": params.PriorityID,
+		"status_id":   params.StatusID,
+		"dept_id":     params.DeptID,
+		"sla_id":      params.SLAID,
+		"topic_id":    params.TopicID,
+		"created":     created,
+	}
+	for name, value := range params.Fields {
+		ticket[name] = value
+	}
+	f.Tickets = append(f.Tickets, ticket)
+	if f.Threads == nil {
+		f.Threads = map[int][]osticket.ThreadEntry{}
+	}
+	f.Threads[id] = append(f.Threads[id], osticket.ThreadEntry{ID: 1, UserID: params.UserID, Type: "M", Title: params.Title, Body: params.Subject, Created: created})
+	return id, nil
+}
+
+// addEntry appends a thread entry to an existing ticket
+func (f *Fake) addEntry(ticketID int, entry osticket.ThreadEntry) error {
+	if _, err := f.ticketByID(ticketID); err != nil {
+		return err
+	}
+	if entry.Body == "" {
+		return invalid("body is required")
+	}
+	if f.Threads == nil {
+		f.Threads = map[int][]osticket.ThreadEntry{}
+	}
+	entry.ID = len(f.Threads[ticketID]) + 1
+	entry.Created = f.now().Format("2006-01-02 15:04:05")
+	f.Threads[ticketID] = append(f.Threads[ticketID], entry)
+	return nil
+}
+
+func (f *Fake) ReplyToTicket(ctx context.Context, ticketID int, body string, staffID int, attachments ...osticket.Attachment) error {
+	err := f.begin("ReplyToTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return f.addEntry(ticketID, osticket.ThreadEntry{StaffID: staffID, Type: "R", Body: body})
+}
+
+func (f *Fake) AddInternalNote(ctx context.Context, ticketID int, title, body string, staffID int, attachments ...osticket.Attachment) error {
+	err := f.begin("AddInternalNote")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return f.addEntry(ticketID, osticket.ThreadEntry{StaffID: staffID, Type: "N", Title: title, Body: body})
+}
+
+// CloseTicket sets the ticket's status, the first closed one unless
+// StatusID is given, and adds the body as a reply
+func (f *Fake) CloseTicket(ctx context.Context, params osticket.CloseTicketParams) error {
+	err := f.begin("CloseTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.ticketByID(params.TicketID)
+	if err != nil {
+		return err
+	}
+	status := params.StatusID
+	if status == 0 {
+		if status, err = f.statusID("closed"); err != nil {
+			return err
+		}
+	}
+	t["status_id"] = status
+	if params.Body != "" {
+		return f.addEntry(params.TicketID, osticket.ThreadEntry{StaffID: params.StaffID, Type: "R", Body: params.Body})
+	}
+	return nil
+}
+
+func (f *Fake) AssignTicket(ctx context.Context, params osticket.AssignTicketParams) error {
+	err := f.begin("AssignTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.ticketByID(params.TicketID)
+	if err != nil {
+		return err
+	}
+	if (params.StaffID == 0) == (params.TeamID == 0) {
+		return invalid("exactly one of staff_id and team_id is required")
+	}
+	if params.StaffID != 0 {
+		t["staff_id"] = params.StaffID
+	} else {
+		t["team_id"] = params.TeamID
+	}
+	return nil
+}
+
+func (f *Fake) UpdateTicketStatus(ctx context.Context, params osticket.UpdateTicketStatusParams) error {
+	err := f.begin("UpdateTicketStatus")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.ticketByID(params.TicketID)
+	if err != nil {
+		return err
+	}
+	t["status_id"] = params.StatusID
+	return nil
+}
+
+func (f *Fake) UpdateTicketPriority(ctx context.Context, params osticket.UpdateTicketPriorityParams) error {
+	err := f.begin("UpdateTicketPriority")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.ticketByID(params.TicketID)
+	if err != nil {
+		return err
+	}
+	if !f.hasPriority(params.PriorityID) {
+		return invalid("invalid priority %d", params.PriorityID)
+	}
+	t["priority_id"] = params.PriorityID
+	return nil
+}
+
+func (f *Fake) ArchiveTicket(ctx context.Context, ticketID, staffID int, comment string) error {
+	err := f.begin("ArchiveTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.ticketByID(ticketID)
+	if err != nil {
+		return err
+	}
+	status, err := f.statusID("archived")
+	if err != nil {
+		return err
+	}
+	t["status_id"] = status
+	return nil
+}
+
+// DeleteTicket removes the ticket and its thread
+func (f *Fake) DeleteTicket(ctx context.Context, ticketID, staffID int, comment string) error {
+	err := f.begin("DeleteTicket")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if _, err := f.ticketByID(ticketID); err != nil {
+		return err
+	}
+	f.Tickets = f.filterTickets(func(t map[string]interface{}) bool {
+		return intField(t, "ticket_id") != ticketID
+	})
+	delete(f.Threads, ticketID)
+	return nil
+}
+
+func (f *Fake) task(id int) (*osticket.Task, error) {
+	for i := range f.Tasks {
+		if f.Tasks[i].ID == id {
+			return &f.Tasks[i], nil
+		}
+	}
+	return nil, notFound("task", id)
+}
+
+// GetTicketTasks returns the ticket's tasks, open and closed
+func (f *Fake) GetTicketTasks(ctx context.Context, ticketID int) (*osticket.TaskData, error) {
+	err := f.begin("GetTicketTasks")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.ticketByID(ticketID); err != nil {
+		return nil, err
+	}
+	tasks := []osticket.Task{}
+	for _, t := range f.Tasks {
+		if t.TicketID == ticketID {
+			tasks = append(tasks, t)
+		}
+	}
+	return &osticket.TaskData{Total: len(tasks), Tasks: tasks}, nil
+}
+
+// CreateTask adds an open task, in the ticket's department unless DeptID
+// is given
+func (f *Fake) CreateTask(ctx context.Context, params osticket.CreateTaskParams) (int, error) {
+	err := f.begin("CreateTask")
+	defer f.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	t, err := f.ticketByID(params.TicketID)
+	if err != nil {
+		return 0, err
+	}
+	if params.Title == "" {
+		return 0, fmt.Errorf("task title is required")
+	}
+	if params.StaffID != 0 && params.TeamID != 0 {
+		return 0, invalid("only one of staff_id and team_id may be given")
+	}
+	dept := params.DeptID
+	if dept == 0 {
+		dept = intField(t, "dept_id")
+	}
+	ids := make([]int, len(f.Tasks))
+	for i, task := range f.Tasks {
+		ids[i] = task.ID
+	}
+	id := nextID(ids...)
+	f.Tasks = append(f.Tasks, osticket.Task{
+		ID:          id,
+		TicketID:    params.TicketID,
+		DeptID:      dept,
+		StaffID:     params.StaffID,
+		TeamID:      params.TeamID,
+		IsOpen:      true,
+		Number:      strconv.Itoa(id),
+		Title:       params.Title,
+		Description: params.Description,
+		DueDate:     params.DueDate,
+		Created:     f.now().Format("2006-01-02 15:04:05"),
+	})
+	return id, nil
+}
+
+func (f *Fake) CloseTask(ctx context.Context, taskID, staffID int, comment string) error {
+	err := f.begin("CloseTask")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.task(taskID)
+	if err != nil {
+		return err
+	}
+	if !t.IsOpen {
+		return invalid("task %d is already closed", taskID)
+	}
+	t.IsOpen = false
+	t.Closed = f.now().Format("2006-01-02 15:04:05")
+	return nil
+}
+
+func (f *Fake) AssignTask(ctx context.Context, params osticket.AssignTaskParams) error {
+	err := f.begin("AssignTask")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	t, err := f.task(params.TaskID)
+	if err != nil {
+		return err
+	}
+	if (params.StaffID == 0) == (params.TeamID == 0) {
+		return invalid("exactly one of staff_id and team_id is required")
+	}
+	if params.StaffID != 0 {
+		t.StaffID = params.StaffID
+	} else {
+		t.TeamID = params.TeamID
+	}
+	return nil
+}
+
+// user finds a user by ID
+func (f *Fake) user(id int) *osticket.User {
+	for i := range f.Users {
+		if f.Users[i].UserID == id {
+			return &f.Users[i]
+		}
+	}
+	return nil
+}
+
+// userByEmail finds a user by email, case-insensitively
+func (f *Fake) userByEmail(email string) *osticket.User {
+	for i := range f.Users {
+		if strings.EqualFold(f.Users[i].Email, email) {
+			u := f.Users[i]
+			return &u
+		}
+	}
+	return nil
+}
+
+// userData returns a user, if any, as the API lists users
+func userData(u *osticket.User) *osticket.UserData {
+	if u == nil {
+		return &osticket.UserData{Users: []osticket.User{}}
+	}
+	return &osticket.UserData{Total: 1, Users: []osticket.User{*u}}
+}
+
+func (f *Fake) GetUserByID(ctx context.Context, id string) (*osticket.UserData, error) {
+	err := f.begin("GetUserByID")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, invalid("invalid user ID %q", id)
+	}
+	return userData(f.user(n)), nil
+}
+
+func (f *Fake) GetUserByEmail(ctx context.Context, email string) (*osticket.UserData, error) {
+	err := f.begin("GetUserByEmail")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return userData(f.userByEmail(email)), nil
+}
+
+func (f *Fake) GetUserByEmailRaw(ctx context.Context, email string) ([]byte, error) {
+	data, err := f.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return success(data), nil
+}
+
+func (f *Fake) CreateUser(ctx context.Context, params osticket.CreateUserParams) (int, error) {
+	err := f.begin("CreateUser")
+	defer f.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if err := params.Validate(); err != nil {
+		return 0, invalid("%v", err)
+	}
+	if f.userByEmail(params.Email) != nil {
+		return 0, invalid("a user with email %s already exists", params.Email)
+	}
+	ids := make([]int, len(f.Users))
+	for i, u := range f.Users {
+		ids[i] = u.UserID
+	}
+	id := nextID(ids...)
+	f.Users = append(f.Users, osticket.User{UserID: id, Name: params.Name, Email: params.Email, Created: f.now().Format("2006-01-02 15:04:05")})
+	if params.OrgID != 0 {
+		if f.Members == nil {
+			f.Members = map[int][]int{}
+		}
+		f.Members[params.OrgID] = append(f.Members[params.OrgID], id)
+	}
+	return id, nil
+}
+
+func (f *Fake) UpdateUser(ctx context.Context, params osticket.UpdateUserParams) error {
+	err := f.begin("UpdateUser")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := params.Validate(); err != nil {
+		return invalid("%v", err)
+	}
+	u := f.user(params.UserID)
+	if u == nil {
+		return notFound("user", params.UserID)
+	}
+	if params.Name != "" {
+		u.Name = params.Name
+	}
+	if params.Email != "" {
+		u.Email = params.Email
+	}
+	return nil
+}
+
+func (f *Fake) DisableUser(ctx context.Context, userID int) error {
+	err := f.begin("DisableUser")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if f.user(userID) == nil {
+		return notFound("user", userID)
+	}
+	return nil
+}
+
+func (f *Fake) GetOrganization(ctx context.Context, id int) (*osticket.OrganizationData, error) {
+	err := f.begin("GetOrganization")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	data := &osticket.OrganizationData{Organizations: []osticket.Organization{}}
+	for _, o := range f.Organizations {
+		if o.ID == id {
+			data.Organizations = append(data.Organizations, o)
+		}
+	}
+	data.Total = len(data.Organizations)
+	return data, nil
+}
+
+func (f *Fake) GetOrganizations(ctx context.Context) (*osticket.OrganizationData, error) {
+	err := f.begin("GetOrganizations")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	orgs := append([]osticket.Organization{}, f.Organizations...)
+	return &osticket.OrganizationData{Total: len(orgs), Organizations: orgs}, nil
+}
+
+func (f *Fake) CreateOrganization(ctx context.Context, params osticket.CreateOrganizationParams) (int, error) {
+	err := f.begin("CreateOrganization")
+	defer f.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if params.Name == "" {
+		return 0, fmt.Errorf("organization name is required")
+	}
+	ids := make([]int, len(f.Organizations))
+	for i, o := range f.Organizations {
+		ids[i] = o.ID
+	}
+	id := nextID(ids...)
+	f.Organizations = append(f.Organizations, osticket.Organization{ID: id, Name: params.Name, Domain: params.Domain, Created: f.now().Format("2006-01-02 15:04:05")})
+	return id, nil
+}
+
+func (f *Fake) AddUserToOrganization(ctx context.Context, orgID, userID int) error {
+	err := f.begin("AddUserToOrganization")
+	defer f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, o := range f.Organizations {
+		found = found || o.ID == orgID
+	}
+	if !found {
+		return notFound("organization", orgID)
+	}
+	if f.user(userID) == nil {
+		return notFound("user", userID)
+	}
+	if f.Members == nil {
+		f.Members = map[int][]int{}
+	}
+	f.Members[orgID] = append(f.Members[orgID], userID)
+	return nil
+}
+
+// staffData lists the agents keep accepts
+func (f *Fake) staffData(keep func(osticket.Staff) bool) *osticket.StaffData {
+	data := &osticket.StaffData{Staff: []osticket.Staff{}}
+	for _, s := range f.Staff {
+		if keep(s) {
+			data.Staff = append(data.Staff, s)
+		}
+	}
+	data.Total = len(data.Staff)
+	return data
+}
+
+func (f *Fake) GetStaff(ctx context.Context, id int) (*osticket.StaffData, error) {
+	err := f.begin("GetStaff")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.staffData(func(s osticket.Staff) bool { return s.StaffID == id }), nil
+}
+
+func (f *Fake) GetStaffByUsername(ctx context.Context, username string) (*osticket.StaffData, error) {
+	err := f.begin("GetStaffByUsername")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.staffData(func(s osticket.Staff) bool { return strings.EqualFold(s.Username, username) }), nil
+}
+
+func (f *Fake) GetStaffList(ctx context.Context) (*osticket.StaffData, error) {
+	err := f.begin("GetStaffList")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return f.staffData(func(osticket.Staff) bool { return true }), nil
+}
+
+func (f *Fake) GetDepartments(ctx context.Context) (*osticket.DepartmentData, error) {
+	err := f.begin("GetDepartments")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	depts := append([]osticket.Department{}, f.Departments...)
+	return &osticket.DepartmentData{Total: len(depts), Departments: depts}, nil
+}
+
+func (f *Fake) GetTopics(ctx context.Context) (*osticket.TopicData, error) {
+	err := f.begin("GetTopics")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	topics := append([]osticket.Topic{}, f.Topics...)
+	return &osticket.TopicData{Total: len(topics), Topics: topics}, nil
+}
+
+func (f *Fake) GetSLAs(ctx context.Context) (*osticket.SLAData, error) {
+	err := f.begin("GetSLAs")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	slas := append([]osticket.SLA{}, f.SLAs...)
+	return &osticket.SLAData{Total: len(slas), SLA: slas}, nil
+}
+
+func (f *Fake) GetTeams(ctx context.Context) (*osticket.TeamData, error) {
+	err := f.begin("GetTeams")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	teams := append([]osticket.Team{}, f.Teams...)
+	return &osticket.TeamData{Total: len(teams), Teams: teams}, nil
+}
+
+func (f *Fake) GetPriorities(ctx context.Context) (*osticket.PriorityData, error) {
+	err := f.begin("GetPriorities")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	priorities := append([]osticket.Priority{}, f.Priorities...)
+	return &osticket.PriorityData{Total: len(priorities), Priorities: priorities}, nil
+}
+
+func (f *Fake) GetCannedResponses(ctx context.Context) (*osticket.CannedData, error) {
+	err := f.begin("GetCannedResponses")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	canned := append([]osticket.CannedResponse{}, f.Canned...)
+	return &osticket.CannedData{Total: len(canned), Responses: canned}, nil
+}
+
+func (f *Fake) GetStatuses(ctx context.Context) (*osticket.StatusData, error) {
+	err := f.begin("GetStatuses")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	statuses := append([]osticket.Status{}, f.Statuses...)
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Sort < statuses[j].Sort })
+	return &osticket.StatusData{Total: len(statuses), Statuses: statuses}, nil
+}
+
+func (f *Fake) Ping(ctx context.Context) error {
+	err := f.begin("Ping")
+	f.mu.Unlock()
+	return err
+}
+
+// ServerInfo reports every probe as passing, and no versions
+func (f *Fake) ServerInfo(ctx context.Context) (*osticket.ServerInfo, error) {
+	err := f.begin("ServerInfo")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	info := &osticket.ServerInfo{BaseURL: "fake:"}
+	for _, name := range []string{"info", "departments", "topics", "sla", "teams", "priorities", "statuses", "tickets"} {
+		info.Probes = append(info.Probes, osticket.Probe{Name: name, OK: true})
+	}
+	return info, nil
+}
+
+// Call answers from Responses, by the request's query and condition
+func (f *Fake) Call(ctx context.Context, method string, req osticket.Request) ([]byte, error) {
+	err := f.begin("Call")
+	defer f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if body, ok := f.Responses[req.Query+"/"+req.Condition]; ok {
+		return append([]byte(nil), body...), nil
+	}
+	body, _ := json.Marshal(map[string]string{"status": "Error", "message": "Unknown query"})
+	return body, nil
+}
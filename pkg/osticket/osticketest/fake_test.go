@@ -0,0 +1,128 @@
+package osticketest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+	"github.com/osticket-cli-go/pkg/osticket/osticketest"
+)
+
+func newFake() *osticketest.Fake {
+	fake := osticketest.NewFake()
+	fake.Now = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }
+	fake.Users = []osticket.User{{UserID: 12, Name: "Ann Example", Email: "ann@example.com"}}
+	return fake
+}
+
+func TestFakeTicketLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake()
+
+	id, err := fake.CreateTicket(ctx, osticket.CreateTicketParams{
+		Title: "Printer on fire", Subject: "It is really on fire", UserID: 12, PriorityID: 3, StatusID: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.ReplyToTicket(ctx, id, "Try turning it off", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.CloseTicket(ctx, osticket.CloseTicketParams{TicketID: id, StaffID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fake.GetTicket(ctx, "100001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tickets) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(data.Tickets))
+	}
+	ticket := data.Tickets[0]
+	if ticket["subject"] != "Printer on fire" || ticket["priority"] != "High" || ticket["status_id"] != float64(2) || ticket["created"] != "2026-10-16 09:00:00" {
+		t.Errorf("ticket = %v", ticket)
+	}
+
+	thread, err := fake.GetTicketThread(ctx, "100001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thread.Total != 2 || thread.Entries[0].Body != "It is really on fire" || thread.Entries[1].Type != "R" {
+		t.Errorf("thread = %+v", thread)
+	}
+}
+
+func TestFakeReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake()
+	fake.Tickets = []map[string]interface{}{{"ticket_id": 1, "number": "100001", "subject": "Original", "status_id": 1}}
+
+	data, _ := fake.GetTicketsByStatus(ctx, 1, osticket.Page{})
+	data.Tickets[0]["subject"] = "Changed"
+	if fake.Tickets[0]["subject"] != "Original" {
+		t.Error("changing a returned ticket changed the fake's")
+	}
+}
+
+func TestFakeErrors(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake()
+
+	_, err := fake.CreateTicket(ctx, osticket.CreateTicketParams{Title: "No user", Subject: "Body", UserID: 99})
+	var apiErr *osticket.Error
+	if !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrValidation {
+		t.Errorf("create for a missing user: err = %v, want a validation error", err)
+	}
+
+	if err := fake.ReplyToTicket(ctx, 5, "Hello", 1); !errors.As(err, &apiErr) || apiErr.Kind != osticket.ErrNotFound {
+		t.Errorf("reply to a missing ticket: err = %v, want a not-found error", err)
+	}
+
+	down := &osticket.Error{Kind: osticket.ErrServer, StatusCode: 503, Message: "Service Unavailable"}
+	fake.Errors = map[string]error{"GetDepartments": down}
+	if _, err := fake.GetDepartments(ctx); err != down {
+		t.Errorf("err = %v, want the configured error", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 || calls[2] != "GetDepartments" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestFakeSearch(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake()
+	fake.Tickets = []map[string]interface{}{
+		{"ticket_id": 1, "number": "100001", "subject": "VPN drops", "user_id": 12, "status_id": 1, "created": "2026-09-01 10:00:00"},
+		{"ticket_id": 2, "number": "100002", "subject": "Printer jam", "user_id": 7, "status_id": 1, "created": "2026-10-01 10:00:00"},
+		{"ticket_id": 3, "number": "100003", "subject": "Printer on fire", "user_id": 12, "status_id": 3, "created": "2026-10-02 10:00:00"},
+	}
+
+	data, err := fake.SearchTicketsByTerm(ctx, "printer", "2026-10-01", "2026-10-31", 0, osticket.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tickets) != 2 || data.Tickets[0]["number"] != "100003" {
+		t.Errorf("search = %v, want 100003 then 100002", data.Tickets)
+	}
+
+	data, user, err := fake.SearchTicketsByEmail(ctx, "ANN@example.com", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user == nil || user.UserID != 12 || len(data.Tickets) != 2 {
+		t.Errorf("by email: user %v, %d tickets", user, len(data.Tickets))
+	}
+
+	data, err = fake.GetTicketsByStatus(ctx, 1, osticket.Page{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tickets) != 1 || data.Tickets[0]["number"] != "100001" {
+		t.Errorf("second page = %v", data.Tickets)
+	}
+}
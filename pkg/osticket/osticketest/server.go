@@ -0,0 +1,135 @@
+package osticketest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/osticket-cli-go/pkg/osticket"
+)
+
+// Server is an httptest server standing in for the API plugin, answering
+// each call with a recorded response. Recordings are the labeled
+// responses '--output raw' prints for commands that make several calls,
+// e.g.
+//
+//	[{"call": "POST department/all", "response": {"status": "Success", ...}}]
+//
+// and are matched by method, query and condition. A call recorded several
+// times gets the recordings in order, then the last one again; a call
+// with no recording is answered 501 with a plugin-style error naming it.
+type Server struct {
+	*httptest.Server
+	// APIKey is the key requests must send, when set
+	APIKey string
+
+	mu       sync.Mutex
+	replies  map[string][]json.RawMessage
+	served   map[string]int
+	requests []osticket.Request
+}
+
+// NewServer starts a Server replaying the recordings, closed when the
+// test ends
+func NewServer(t testing.TB, recordings ...osticket.RawResponse) *Server {
+	t.Helper()
+	s := &Server{replies: map[string][]json.RawMessage{}, served: map[string]int{}}
+	for _, r := range recordings {
+		s.Add(r.Call, r.Response)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Load reads recordings from a file written by '--output raw', failing
+// the test when it cannot be read
+func Load(t testing.TB, path string) []osticket.RawResponse {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("load recordings: %v", err)
+	}
+	var recordings []osticket.RawResponse
+	if err := json.Unmarshal(data, &recordings); err != nil {
+		t.Fatalf("load recordings %s: %v (a single response needs its call: use Server.Add)", path, err)
+	}
+	return recordings
+}
+
+// Add records a response for a call, given as "METHOD query/condition"
+// as in recordings; anything after the condition is ignored
+func (s *Server) Add(call string, response json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := callKey(call)
+	s.replies[key] = append(s.replies[key], response)
+}
+
+// Client returns a client of the server, sending APIKey, failing the test
+// when it cannot be created
+func (s *Server) Client(t testing.TB, opts ...osticket.Option) *osticket.Client {
+	t.Helper()
+	client, err := osticket.NewClient(s.URL+"/", s.APIKey, opts...)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []osticket.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]osticket.Request(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if s.APIKey != "" && r.Header.Get("apikey") != s.APIKey {
+		writeReply(w, http.StatusUnauthorized, []byte(`{"status":"Error","message":"Valid API key required"}`))
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	var req osticket.Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeReply(w, http.StatusBadRequest, []byte(`{"status":"Error","message":"Invalid JSON"}`))
+		return
+	}
+
+	key := r.Method + " " + req.Query + "/" + req.Condition
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	replies := s.replies[key]
+	n := s.served[key]
+	s.served[key]++
+	s.mu.Unlock()
+
+	if len(replies) == 0 {
+		msg, _ := json.Marshal(fmt.Sprintf("no recorded response for %s", key))
+		writeReply(w, http.StatusNotImplemented, []byte(`{"status":"Error","message":`+string(msg)+`}`))
+		return
+	}
+	writeReply(w, http.StatusOK, replies[min(n, len(replies)-1)])
+}
+
+func writeReply(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// callKey reduces a recorded call such as "POST ticket/specific (id)" to
+// its method, query and condition
+func callKey(call string) string {
+	fields := strings.Fields(call)
+	if len(fields) < 2 {
+		return call
+	}
+	return fields[0] + " " + fields[1]
+}
@@ -0,0 +1,122 @@
+package osticket
+
+// DefaultPageSize is the page size used by CollectPages when none is given
+const DefaultPageSize = 100
+
+// Page selects a window of ticket search results. A zero Limit requests
+// every result.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// apply adds the paging parameters to a request
+func (p Page) apply(params map[string]interface{}) {
+	if p.Limit > 0 {
+		params["limit"] = p.Limit
+		params["offset"] = p.Offset
+	}
+}
+
+// complete reports whether resp holds the full result set rather than
+// one page of it: no page was asked for, the server ignored the paging
+// parameters, or the first page is also the last
+func (p Page) complete(resp *SimpleTicketResponse) bool {
+	return p.Limit <= 0 || resp.rows > p.Limit || p.Offset == 0 && resp.rows < p.Limit
+}
+
+// trim applies the page client-side when the server ignored the paging
+// parameters and returned more results than requested
+func (p Page) trim(resp *SimpleTicketResponse) {
+	if p.Limit <= 0 || len(resp.Tickets) <= p.Limit {
+		return
+	}
+
+	start := p.Offset
+	if start > len(resp.Tickets) {
+		start = len(resp.Tickets)
+	}
+	end := start + p.Limit
+	if end > len(resp.Tickets) {
+		end = len(resp.Tickets)
+	}
+	resp.Tickets = resp.Tickets[start:end]
+	resp.rows = len(resp.Tickets)
+}
+
+// CollectPages calls fetch for successive pages until a short page is
+// returned, and combines the results without repeats, newest first
+func CollectPages(pageSize int, fetch func(Page) (*SimpleTicketResponse, error)) (*SimpleTicketResponse, error) {
+	all, _, err := CollectPagesFrom(pageSize, 0, nil, fetch)
+	return all, err
+}
+
+// CollectPagesFrom is CollectPages starting at offset. stop is checked
+// before each page after the first; when it returns true collection ends
+// early and the offset of the next page is returned so the caller can
+// resume from it. next is -1 when every page was fetched.
+func CollectPagesFrom(pageSize, offset int, stop func() bool, fetch func(Page) (*SimpleTicketResponse, error)) (all *SimpleTicketResponse, next int, err error) {
+	all = &SimpleTicketResponse{Tickets: []map[string]interface{}{}}
+	next, all.Total, err = StreamPages(pageSize, offset, stop, fetch, func(tickets []map[string]interface{}) error {
+		all.Tickets = append(all.Tickets, tickets...)
+		return nil
+	})
+	if err != nil {
+		return nil, next, err
+	}
+	// Tickets can move between pages while they are fetched
+	all.Tickets = normalizeTickets(all.Tickets)
+
+	if next < 0 && all.Total < len(all.Tickets) {
+		all.Total = len(all.Tickets)
+	}
+	return all, next, nil
+}
+
+// StreamPages fetches pages as CollectPagesFrom does, but hands each
+// page's tickets to emit as it arrives instead of combining them, so
+// results can be written before the last page is fetched. Tickets emitted
+// with an earlier page are dropped; the order is the server's, page by
+// page. total is the largest total the server reported. An error from
+// emit ends the fetching and is returned.
+func StreamPages(pageSize, offset int, stop func() bool, fetch func(Page) (*SimpleTicketResponse, error), emit func([]map[string]interface{}) error) (next, total int, err error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	seen := map[string]bool{}
+	for first := true; ; first = false {
+		if !first && stop != nil && stop() {
+			return offset, total, nil
+		}
+
+		page, err := fetch(Page{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return offset, total, err
+		}
+		if page.Total > total {
+			total = page.Total
+		}
+
+		fresh := make([]map[string]interface{}, 0, len(page.Tickets))
+		for _, t := range page.Tickets {
+			if key := ticketKey(t); key != "" {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			fresh = append(fresh, t)
+		}
+		if err := emit(fresh); err != nil {
+			return offset, total, err
+		}
+
+		offset += pageSize
+		// Count what the server sent, so a page shortened by dropping
+		// repeats does not end fetching early
+		if max(page.rows, len(page.Tickets)) < pageSize {
+			return -1, total, nil
+		}
+	}
+}
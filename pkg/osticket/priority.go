@@ -0,0 +1,79 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Priority IDs osTicket ships with
+const (
+	PriorityLow       = 1
+	PriorityNormal    = 2
+	PriorityHigh      = 3
+	PriorityEmergency = 4
+)
+
+// PriorityNames are the names of the priorities osTicket ships with
+var PriorityNames = map[int]string{
+	PriorityLow:       "Low",
+	PriorityNormal:    "Normal",
+	PriorityHigh:      "High",
+	PriorityEmergency: "Emergency",
+}
+
+// PriorityData represents priority response data
+type PriorityData struct {
+	Total      int        `json:"total"`
+	Priorities []Priority `json:"priorities"`
+}
+
+// Priority represents a single ticket priority. Higher urgency is less
+// urgent, as in osTicket.
+type Priority struct {
+	ID          int    `json:"priority_id"`
+	Name        string `json:"priority"`
+	Description string `json:"priority_desc"`
+	Color       string `json:"priority_color"`
+	Urgency     int    `json:"priority_urgency"`
+}
+
+// GetPriorities gets all ticket priorities
+func (c *Client) GetPriorities(ctx context.Context) (*PriorityData, error) {
+	resp, err := c.cachedRequest(ctx, "POST", Request{
+		Query:      "priority",
+		Condition:  "all",
+		Sort:       "all",
+		Parameters: map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data PriorityData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse priority data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// setPriorities gives each ticket a priority_id and a priority name.
+// Depending on the plugin version the ID comes as priority_id, or as
+// priority when the ticket's cdata is joined. Tickets with a priority
+// osTicket does not ship with keep whatever priority the server sent.
+func setPriorities(tickets []map[string]interface{}) {
+	for _, t := range tickets {
+		id := toInt(t["priority_id"])
+		if id == 0 {
+			id = toInt(t["priority"])
+		}
+		if id == 0 {
+			continue
+		}
+		t["priority_id"] = id
+		if name, ok := PriorityNames[id]; ok {
+			t["priority"] = name
+		}
+	}
+}
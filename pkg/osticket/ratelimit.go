@@ -0,0 +1,64 @@
+package osticket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket spacing requests out to at most Rate per
+// second, so bulk jobs and watch mode do not overload the server. Up to
+// Burst requests may go out back to back after an idle period.
+type RateLimiter struct {
+	Rate  float64
+	Burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second with
+// no burst
+func NewRateLimiter(rate float64) *RateLimiter {
+	return &RateLimiter{Rate: rate, Burst: 1}
+}
+
+// Wait blocks until a request may be sent, or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("request failed: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, returning how long to wait until it is available.
+// Tokens may go negative, which queues concurrent callers in order.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	burst := float64(max(l.Burst, 1))
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens = min(burst, l.tokens+now.Sub(l.last).Seconds()*l.Rate)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.Rate * float64(time.Second))
+}
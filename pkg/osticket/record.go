@@ -0,0 +1,68 @@
+package osticket
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// RawResponse is an unmodified API response with the call that produced
+// it, e.g. "GET user/specific (email)"
+type RawResponse struct {
+	Call     string          `json:"call"`
+	Response json.RawMessage `json:"response"`
+}
+
+// Recorder keeps the raw responses of a client's requests in the order
+// the requests were made, for raw output of commands that make several
+type Recorder struct {
+	mu    sync.Mutex
+	slots []*RawResponse
+}
+
+// start reserves the place of a request's response
+func (r *Recorder) start(method string, req Request) *RawResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	slot := &RawResponse{Call: describeCall(method, req)}
+	r.slots = append(r.slots, slot)
+	return slot
+}
+
+// finish stores a response; bodies that are not JSON are kept as a string
+func (r *Recorder) finish(slot *RawResponse, body []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if json.Valid(body) {
+		slot.Response = append(json.RawMessage(nil), body...)
+	} else {
+		slot.Response, _ = json.Marshal(string(body))
+	}
+}
+
+// Responses returns the recorded responses, skipping failed requests
+func (r *Recorder) Responses() []RawResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []RawResponse
+	for _, s := range r.slots {
+		if s.Response != nil {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// JSON returns the single response as it was received, or for several a
+// JSON array of the responses labeled with their calls. It is nil when
+// nothing was recorded.
+func (r *Recorder) JSON() []byte {
+	responses := r.Responses()
+	switch len(responses) {
+	case 0:
+		return nil
+	case 1:
+		return responses[0].Response
+	}
+	data, _ := json.MarshalIndent(responses, "", "  ")
+	return append(data, '\n')
+}
@@ -0,0 +1,80 @@
+package osticket
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// snippetSize is how much of an unexpected response errors quote
+const snippetSize = 200
+
+// utf8BOM is stripped before looking at a body; some PHP setups send it
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// titlePattern finds the title of an HTML page
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// checkBody refuses a successful response that cannot be the plugin's
+// JSON, saying what came back instead: nothing, an HTML page such as the
+// web UI's, JSON preceded by PHP warnings, or other content
+func checkBody(resp *http.Response, body []byte) *Error {
+	text := bytes.TrimSpace(bytes.TrimPrefix(body, utf8BOM))
+	contentType := resp.Header.Get("Content-Type")
+
+	var message string
+	switch {
+	case len(text) == 0:
+		message = fmt.Sprintf("empty response (HTTP %d): check that the URL is the API plugin's endpoint and that the plugin is installed and enabled", resp.StatusCode)
+	case text[0] == '{' || text[0] == '[':
+		return nil
+	case bytes.Contains(text, []byte(`{"status"`)):
+		// PHP notices printed before the JSON when display_errors is on
+		message = "the server printed PHP warnings before the JSON; turn off display_errors on the server: " + snippet(text)
+	case isHTML(text):
+		message = "got an HTML page" + pageTitle(text) + " instead of JSON: the URL points at the web UI, not the API endpoint"
+	default:
+		message = fmt.Sprintf("response is not JSON (content type %q): %s", contentType, snippet(text))
+	}
+	e := newError(resp, message)
+	e.Kind = ErrNotAPI
+	e.Body = body
+	return e
+}
+
+// isHTML reports whether a body is an HTML page
+func isHTML(text []byte) bool {
+	head := strings.ToLower(string(text[:min(len(text), 512)]))
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html") ||
+		(strings.HasPrefix(head, "<") && (strings.Contains(head, "<head") || strings.Contains(head, "<body")))
+}
+
+// pageTitle returns ` ("title")` for an HTML page with a title
+func pageTitle(text []byte) string {
+	m := titlePattern.FindSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	title := strings.Join(strings.Fields(string(m[1])), " ")
+	if title == "" {
+		return ""
+	}
+	return " (" + strconv.Quote(title) + ")"
+}
+
+// snippet quotes the start of a response for error messages
+func snippet(body []byte) string {
+	text := bytes.TrimSpace(body)
+	if len(text) <= snippetSize {
+		return strconv.Quote(string(text))
+	}
+	return strconv.Quote(string(text[:snippetSize])) + "..."
+}
+
+// parseError describes a response that could not be decoded
+func parseError(err error, body []byte) error {
+	return fmt.Errorf("failed to parse response: %w (response starts %s)", err, snippet(body))
+}
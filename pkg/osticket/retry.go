@@ -0,0 +1,105 @@
+package osticket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryWait is the base backoff delay between retries
+const DefaultRetryWait = 500 * time.Millisecond
+
+// maxRetryWait caps a single backoff delay
+const maxRetryWait = 30 * time.Second
+
+// shouldRetry decides whether a failed attempt is worth repeating. Reads are
+// retried on 429, 5xx and transient network errors. Mutations are only
+// retried when the server cannot have acted on them: 429, 503 and failures
+// to connect at all, so a retry never creates a duplicate ticket or reply.
+func shouldRetry(resp *http.Response, err error, idempotent bool) bool {
+	if err != nil {
+		// The caller gave up; the client timeout is handled as transient below
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		if isConnectError(err) {
+			return true
+		}
+		return idempotent && resp == nil && isTransient(err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode >= 500:
+		return idempotent
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt: exponential with
+// jitter, or the server's Retry-After when it sent one
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			wait := time.Duration(secs) * time.Second
+			if wait > maxRetryWait {
+				wait = maxRetryWait
+			}
+			return wait
+		}
+	}
+
+	base := c.RetryWait
+	if base <= 0 {
+		base = DefaultRetryWait
+	}
+	wait := base << attempt
+	if wait <= 0 || wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+
+	// Full jitter between 50% and 100% of the delay
+	half := int64(wait / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// isConnectError reports errors where the request never reached the server
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsTemporary {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// unreachable reports failures that mean a request never reached the
+// server, so it can be queued and sent later without being applied twice:
+// connection failures and requests the circuit breaker refused
+func unreachable(err error) bool {
+	var openErr *CircuitOpenError
+	return isConnectError(err) || errors.As(err, &openErr)
+}
+
+// isTransient reports network errors that may succeed on another attempt
+func isTransient(err error) bool {
+	return isTimeout(err) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,132 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Probe is the result of a single diagnostic query
+type Probe struct {
+	Name       string        `json:"name"`
+	Query      string        `json:"query"`
+	Condition  string        `json:"condition"`
+	OK         bool          `json:"ok"`
+	Error      string        `json:"error,omitempty"`
+	Latency    time.Duration `json:"-"`
+	LatencyMS  int64         `json:"latency_ms"`
+	ServerTime float64       `json:"server_time,omitempty"`
+}
+
+// ServerInfo aggregates what the server reveals about itself. Version
+// fields are empty when the API plugin does not expose them.
+type ServerInfo struct {
+	BaseURL         string            `json:"base_url"`
+	OSTicketVersion string            `json:"osticket_version,omitempty"`
+	PluginVersion   string            `json:"plugin_version,omitempty"`
+	PHPVersion      string            `json:"php_version,omitempty"`
+	Database        string            `json:"database,omitempty"`
+	Extra           map[string]string `json:"extra,omitempty"`
+	Probes          []Probe           `json:"probes"`
+}
+
+// serverProbes are the read-only queries used to detect enabled features
+var serverProbes = []struct {
+	name string
+	req  Request
+}{
+	{"info", Request{Query: "info", Condition: "all", Sort: "all"}},
+	{"departments", Request{Query: "department", Condition: "all", Sort: "all"}},
+	{"topics", Request{Query: "topics", Condition: "all", Sort: "all"}},
+	{"sla", Request{Query: "sla", Condition: "all", Sort: "all"}},
+	{"teams", Request{Query: "team", Condition: "all", Sort: "all"}},
+	{"priorities", Request{Query: "priority", Condition: "all", Sort: "all"}},
+	{"statuses", Request{Query: "status", Condition: "all", Sort: "all"}},
+	{"canned", Request{Query: "canned", Condition: "all", Sort: "all"}},
+	{"tickets", Request{Query: "ticket", Condition: "all", Sort: "status", Parameters: map[string]interface{}{"status": 1, "limit": 1}}},
+}
+
+// versionKeys maps the field names plugin versions use for version details
+var versionKeys = map[string][]string{
+	"osticket": {"osticket_version", "ost_version", "version"},
+	"plugin":   {"plugin_version", "api_version"},
+	"php":      {"php_version", "php"},
+	"database": {"db_version", "mysql_version", "database"},
+}
+
+// ServerInfo runs every probe and collects the results. A failing probe is
+// recorded rather than returned, so the report shows partial outages; an
+// error is only returned when the context is cancelled.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	info := &ServerInfo{BaseURL: c.BaseURL}
+
+	for _, p := range serverProbes {
+		probe := Probe{Name: p.name, Query: p.req.Query, Condition: p.req.Condition}
+
+		start := time.Now()
+		resp, err := c.doGetRequest(ctx, p.req)
+		probe.Latency = time.Since(start)
+		probe.LatencyMS = probe.Latency.Milliseconds()
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request failed: %w", ctx.Err())
+		}
+		if err != nil {
+			probe.Error = err.Error()
+		} else {
+			probe.OK = true
+			probe.ServerTime = resp.Time
+			if p.name == "info" {
+				info.setVersions(resp.Data)
+			}
+		}
+		info.Probes = append(info.Probes, probe)
+	}
+
+	return info, nil
+}
+
+// setVersions fills the version fields from the info probe's data
+func (info *ServerInfo) setVersions(data json.RawMessage) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+
+	lookup := func(kind string) string {
+		for _, key := range versionKeys[kind] {
+			if v, ok := fields[key]; ok {
+				delete(fields, key)
+				return fmt.Sprint(v)
+			}
+		}
+		return ""
+	}
+	info.OSTicketVersion = lookup("osticket")
+	info.PluginVersion = lookup("plugin")
+	info.PHPVersion = lookup("php")
+	info.Database = lookup("database")
+
+	for key, v := range fields {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		if info.Extra == nil {
+			info.Extra = map[string]string{}
+		}
+		info.Extra[key] = fmt.Sprint(v)
+	}
+}
+
+// ExtraKeys returns the keys of Extra in sorted order
+func (info *ServerInfo) ExtraKeys() []string {
+	keys := make([]string, 0, len(info.Extra))
+	for k := range info.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,195 @@
+package osticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Session defaults
+const (
+	DefaultTokenField    = "token"
+	DefaultSessionHeader = "Authorization"
+)
+
+// expirySkew renews a token this long before it expires, so it does not
+// run out while a request is in flight
+const expirySkew = 30 * time.Second
+
+// SessionToken is a token issued by a gateway's login call. A zero Expires
+// means the token is used until the server rejects it.
+type SessionToken struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// valid reports whether the token can still be sent
+func (t *SessionToken) valid() bool {
+	return t != nil && t.Token != "" && (t.Expires.IsZero() || time.Now().Add(expirySkew).Before(t.Expires))
+}
+
+// TokenCache keeps a session token between invocations
+type TokenCache interface {
+	Load() (*SessionToken, error)
+	Save(*SessionToken) error
+	Clear() error
+}
+
+// Session logs in to API gateways that require a short-lived session token
+// on every request. The login call posts {"username", "password"} as JSON
+// to LoginURL and reads the token from the TokenField of the response,
+// with its lifetime from expires_in (seconds) or expires_at (RFC 3339 or
+// Unix seconds). The token is sent in Header, as "Bearer <token>" when
+// Header is Authorization. A request rejected with HTTP 401 logs in again
+// and is retried once.
+type Session struct {
+	LoginURL   string
+	Username   string
+	Password   string
+	TokenField string
+	Header     string
+	// Cache keeps the token between invocations (nil keeps it in memory)
+	Cache TokenCache
+	// HTTPClient performs the login call. It is kept apart from the
+	// client's own so debug logging never shows the password.
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	token *SessionToken
+}
+
+// HeaderName returns the header the token is sent in
+func (s *Session) HeaderName() string {
+	if s.Header == "" {
+		return DefaultSessionHeader
+	}
+	return s.Header
+}
+
+// headerValue returns the header value carrying token
+func (s *Session) headerValue(token string) string {
+	if http.CanonicalHeaderKey(s.HeaderName()) == DefaultSessionHeader {
+		return "Bearer " + token
+	}
+	return token
+}
+
+// Token returns a valid session token, from memory, the cache or a new
+// login
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.valid() {
+		return s.token.Token, nil
+	}
+	if s.Cache != nil {
+		// An unreadable cache just means logging in again
+		if t, err := s.Cache.Load(); err == nil && t.valid() {
+			s.token = t
+			return t.Token, nil
+		}
+	}
+
+	t, err := s.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = t
+	if s.Cache != nil {
+		// Caching is best effort; the token works for this invocation
+		s.Cache.Save(t)
+	}
+	return t.Token, nil
+}
+
+// Expire drops token after the server rejected it, so the next request
+// logs in again. Requests that failed with an older token do not drop a
+// newer one.
+func (s *Session) Expire(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.Token != token {
+		return
+	}
+	s.token = nil
+	if s.Cache != nil {
+		s.Cache.Clear()
+	}
+}
+
+// login performs the login call
+func (s *Session) login(ctx context.Context) (*SessionToken, error) {
+	body, err := json.Marshal(map[string]string{"username": s.Username, "password": s.Password})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.LoginURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := s.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read login response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		e := httpError(resp, respBody)
+		e.Message = "login failed: " + e.Message
+		if resp.StatusCode == http.StatusBadRequest {
+			// Gateways answer bad credentials with 400 as often as 401
+			e.Kind = ErrAuth
+		}
+		return nil, e
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse login response: %w", err)
+	}
+	field := s.TokenField
+	if field == "" {
+		field = DefaultTokenField
+	}
+	token, _ := data[field].(string)
+	if token == "" {
+		return nil, fmt.Errorf("login response has no %q field", field)
+	}
+	return &SessionToken{Token: token, Expires: tokenExpiry(data)}, nil
+}
+
+// tokenExpiry reads a login response's expires_in or expires_at
+func tokenExpiry(data map[string]interface{}) time.Time {
+	if secs := toInt(data["expires_in"]); secs > 0 {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	switch at := data["expires_at"].(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			return t
+		}
+		if secs, err := strconv.ParseInt(at, 10, 64); err == nil {
+			return time.Unix(secs, 0)
+		}
+	case float64:
+		return time.Unix(int64(at), 0)
+	}
+	return time.Time{}
+}
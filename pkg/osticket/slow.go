@@ -0,0 +1,71 @@
+package osticket
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SlowCall is a request that took longer than the client's SlowThreshold
+type SlowCall struct {
+	Method  string
+	Request Request
+	Elapsed time.Duration
+}
+
+// String names the call, e.g. "GET ticket/search (email)"
+func (s SlowCall) String() string {
+	return describeCall(s.Method, s.Request)
+}
+
+// describeCall names a request by method, query and condition, listing
+// the parameter names but not their values
+func describeCall(method string, req Request) string {
+	name := method + " " + req.Query + "/" + req.Condition
+	if len(req.Parameters) > 0 {
+		params := make([]string, 0, len(req.Parameters))
+		for k := range req.Parameters {
+			params = append(params, k)
+		}
+		sort.Strings(params)
+		name += " (" + strings.Join(params, ", ") + ")"
+	}
+	return name
+}
+
+// checkSlow reports a request that exceeded SlowThreshold to OnSlow
+func (c *Client) checkSlow(method string, body []byte, elapsed time.Duration) {
+	if c.SlowThreshold <= 0 || c.OnSlow == nil || elapsed <= c.SlowThreshold {
+		return
+	}
+	call := SlowCall{Method: method, Elapsed: elapsed}
+	// Bodies are encoded Requests, including queued outbox entries
+	json.Unmarshal(body, &call.Request)
+	c.OnSlow(call)
+}
+
+// Call is a finished request, reported to the client's OnCall
+type Call struct {
+	Method  string
+	Request Request
+	Elapsed time.Duration
+	// StatusCode is the HTTP status, 0 when no response arrived
+	StatusCode int
+	// Err is set when the request failed without a response
+	Err error
+}
+
+// reportCall reports a finished request to OnCall
+func (c *Client) reportCall(method string, body []byte, elapsed time.Duration, resp *http.Response, err error) {
+	if c.OnCall == nil {
+		return
+	}
+	call := Call{Method: method, Elapsed: elapsed, Err: err}
+	if resp != nil {
+		call.StatusCode = resp.StatusCode
+	}
+	json.Unmarshal(body, &call.Request)
+	c.OnCall(call)
+}
@@ -0,0 +1,124 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StaffData represents staff response data
+type StaffData struct {
+	Total int     `json:"total"`
+	Staff []Staff `json:"staff"`
+}
+
+// Staff represents a single agent
+type Staff struct {
+	StaffID   int    `json:"-"` // Parsed manually due to API returning string or int
+	DeptID    int    `json:"-"`
+	IsActive  bool   `json:"-"`
+	IsAdmin   bool   `json:"-"`
+	Username  string `json:"username"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	Created   string `json:"created"`
+	LastLogin string `json:"lastlogin"`
+}
+
+// Name returns the agent's full name
+func (s Staff) Name() string {
+	return strings.TrimSpace(s.Firstname + " " + s.Lastname)
+}
+
+// UnmarshalJSON handles IDs and flags sent as string or int
+func (s *Staff) UnmarshalJSON(data []byte) error {
+	type Alias Staff
+	aux := &struct {
+		StaffID  interface{} `json:"staff_id"`
+		DeptID   interface{} `json:"dept_id"`
+		IsActive interface{} `json:"isactive"`
+		IsAdmin  interface{} `json:"isadmin"`
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.StaffID = toInt(aux.StaffID)
+	s.DeptID = toInt(aux.DeptID)
+	s.IsActive = toInt(aux.IsActive) == 1
+	s.IsAdmin = toInt(aux.IsAdmin) == 1
+	return nil
+}
+
+// MarshalJSON includes the manually parsed fields in JSON output
+func (s Staff) MarshalJSON() ([]byte, error) {
+	type Alias Staff
+	return json.Marshal(&struct {
+		StaffID  int  `json:"staff_id"`
+		DeptID   int  `json:"dept_id"`
+		IsActive bool `json:"isactive"`
+		IsAdmin  bool `json:"isadmin"`
+		Alias
+	}{
+		StaffID:  s.StaffID,
+		DeptID:   s.DeptID,
+		IsActive: s.IsActive,
+		IsAdmin:  s.IsAdmin,
+		Alias:    Alias(s),
+	})
+}
+
+// GetStaff gets an agent by ID
+func (c *Client) GetStaff(ctx context.Context, id int) (*StaffData, error) {
+	return c.getStaff(ctx, Request{
+		Query:      "staff",
+		Condition:  "specific",
+		Sort:       "id",
+		Parameters: map[string]interface{}{"id": id},
+	})
+}
+
+// GetStaffByUsername gets an agent by username
+func (c *Client) GetStaffByUsername(ctx context.Context, username string) (*StaffData, error) {
+	return c.getStaff(ctx, Request{
+		Query:      "staff",
+		Condition:  "specific",
+		Sort:       "username",
+		Parameters: map[string]interface{}{"username": username},
+	})
+}
+
+// GetStaffList gets all agents
+func (c *Client) GetStaffList(ctx context.Context) (*StaffData, error) {
+	resp, err := c.cachedRequest(ctx, "GET", Request{
+		Query:      "staff",
+		Condition:  "all",
+		Sort:       "all",
+		Parameters: map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseStaff(resp)
+}
+
+func (c *Client) getStaff(ctx context.Context, req Request) (*StaffData, error) {
+	resp, err := c.doGetRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseStaff(resp)
+}
+
+func parseStaff(resp *Response) (*StaffData, error) {
+	var data StaffData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse staff data: %w", err)
+	}
+	return &data, nil
+}
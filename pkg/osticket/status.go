@@ -0,0 +1,43 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// StatusData represents ticket status response data
+type StatusData struct {
+	Total    int      `json:"total"`
+	Statuses []Status `json:"statuses"`
+}
+
+// Status represents a single ticket status. State is what the status
+// means to osTicket (open, closed, archived or deleted); installs can add
+// statuses such as "On Hold" that share a state.
+type Status struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Sort  int    `json:"sort"`
+}
+
+// GetStatuses gets all ticket statuses, including custom ones
+func (c *Client) GetStatuses(ctx context.Context) (*StatusData, error) {
+	resp, err := c.cachedRequest(ctx, "POST", Request{
+		Query:      "status",
+		Condition:  "all",
+		Sort:       "all",
+		Parameters: map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data StatusData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse status data: %w", err)
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,226 @@
+package osticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TaskData represents task response data
+type TaskData struct {
+	Total int    `json:"total"`
+	Tasks []Task `json:"tasks"`
+}
+
+// Task is a piece of internal work attached to a ticket
+type Task struct {
+	ID          int    `json:"-"` // Parsed manually due to API returning string or int
+	TicketID    int    `json:"-"`
+	DeptID      int    `json:"-"`
+	StaffID     int    `json:"-"`
+	TeamID      int    `json:"-"`
+	IsOpen      bool   `json:"-"`
+	Number      string `json:"number"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"duedate,omitempty"`
+	Created     string `json:"created"`
+	Updated     string `json:"updated,omitempty"`
+	Closed      string `json:"closed,omitempty"`
+}
+
+// UnmarshalJSON handles IDs sent as string or int. Plugin versions give
+// the ticket as ticket_id or object_id, and the state as a status name or
+// as osTicket's flags, whose lowest bit is set while the task is open.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type Alias Task
+	aux := &struct {
+		ID       interface{} `json:"id"`
+		TaskID   interface{} `json:"task_id"`
+		TicketID interface{} `json:"ticket_id"`
+		ObjectID interface{} `json:"object_id"`
+		DeptID   interface{} `json:"dept_id"`
+		StaffID  interface{} `json:"staff_id"`
+		TeamID   interface{} `json:"team_id"`
+		Status   string      `json:"status"`
+		Flags    interface{} `json:"flags"`
+		*Alias
+	}{
+		Alias: (*Alias)(t),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.ID = toInt(aux.TaskID)
+	if t.ID == 0 {
+		t.ID = toInt(aux.ID)
+	}
+	t.TicketID = toInt(aux.TicketID)
+	if t.TicketID == 0 {
+		t.TicketID = toInt(aux.ObjectID)
+	}
+	t.DeptID = toInt(aux.DeptID)
+	t.StaffID = toInt(aux.StaffID)
+	t.TeamID = toInt(aux.TeamID)
+	switch {
+	case aux.Status != "":
+		t.IsOpen = strings.EqualFold(aux.Status, "open")
+	case aux.Flags != nil:
+		t.IsOpen = toInt(aux.Flags)&1 == 1
+	default:
+		t.IsOpen = t.Closed == "" || strings.HasPrefix(t.Closed, "0000-00-00")
+	}
+	return nil
+}
+
+// MarshalJSON includes the manually parsed fields in JSON output
+func (t Task) MarshalJSON() ([]byte, error) {
+	type Alias Task
+	status := "closed"
+	if t.IsOpen {
+		status = "open"
+	}
+	return json.Marshal(&struct {
+		ID       int    `json:"task_id"`
+		TicketID int    `json:"ticket_id"`
+		DeptID   int    `json:"dept_id"`
+		StaffID  int    `json:"staff_id"`
+		TeamID   int    `json:"team_id"`
+		Status   string `json:"status"`
+		Alias
+	}{
+		ID:       t.ID,
+		TicketID: t.TicketID,
+		DeptID:   t.DeptID,
+		StaffID:  t.StaffID,
+		TeamID:   t.TeamID,
+		Status:   status,
+		Alias:    Alias(t),
+	})
+}
+
+// GetTicketTasks gets the tasks of a ticket
+func (c *Client) GetTicketTasks(ctx context.Context, ticketID int) (*TaskData, error) {
+	resp, err := c.doGetRequest(ctx, Request{
+		Query:      "task",
+		Condition:  "ticket",
+		Sort:       "id",
+		Parameters: map[string]interface{}{"ticket_id": ticketID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data TaskData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse task data: %w", err)
+	}
+	return &data, nil
+}
+
+// CreateTaskParams contains parameters for creating a task
+type CreateTaskParams struct {
+	TicketID    int
+	Title       string
+	Description string
+	DeptID      int
+	StaffID     int
+	TeamID      int
+	// DueDate is "YYYY-MM-DD" or "YYYY-MM-DD HH:MM:SS"; optional
+	DueDate string
+}
+
+// CreateTask adds a task to a ticket and returns its ID
+func (c *Client) CreateTask(ctx context.Context, params CreateTaskParams) (int, error) {
+	if params.Title == "" {
+		return 0, fmt.Errorf("task title is required")
+	}
+
+	parameters := map[string]interface{}{
+		"ticket_id": params.TicketID,
+		"title":     params.Title,
+	}
+	if params.Description != "" {
+		parameters["description"] = params.Description
+	}
+	if params.DeptID != 0 {
+		parameters["dept_id"] = params.DeptID
+	}
+	if params.StaffID != 0 {
+		parameters["staff_id"] = params.StaffID
+	}
+	if params.TeamID != 0 {
+		parameters["team_id"] = params.TeamID
+	}
+	if params.DueDate != "" {
+		parameters["duedate"] = params.DueDate
+	}
+
+	resp, err := c.doRequest(ctx, Request{
+		Query:      "task",
+		Condition:  "add",
+		Parameters: parameters,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// API returns the ID as string or int
+	var raw interface{}
+	if err := json.Unmarshal(resp.Data, &raw); err != nil {
+		return 0, fmt.Errorf("failed to parse task ID: %w", err)
+	}
+	return toInt(raw), nil
+}
+
+// CloseTask closes a task
+func (c *Client) CloseTask(ctx context.Context, taskID, staffID int, comment string) error {
+	parameters := map[string]interface{}{
+		"task_id": taskID,
+	}
+	if staffID != 0 {
+		parameters["staff_id"] = staffID
+	}
+	if comment != "" {
+		parameters["comments"] = comment
+	}
+
+	_, err := c.doRequest(ctx, Request{
+		Query:      "task",
+		Condition:  "close",
+		Parameters: parameters,
+	})
+	return err
+}
+
+// AssignTaskParams contains parameters for assigning a task
+type AssignTaskParams struct {
+	TaskID  int
+	StaffID int
+	TeamID  int
+	Comment string
+}
+
+// AssignTask assigns a task to an agent or a team
+func (c *Client) AssignTask(ctx context.Context, params AssignTaskParams) error {
+	parameters := map[string]interface{}{
+		"task_id": params.TaskID,
+	}
+	if params.StaffID != 0 {
+		parameters["staff_id"] = params.StaffID
+	}
+	if params.TeamID != 0 {
+		parameters["team_id"] = params.TeamID
+	}
+	if params.Comment != "" {
+		parameters["comments"] = params.Comment
+	}
+
+	_, err := c.doRequest(ctx, Request{
+		Query:      "task",
+		Condition:  "assign",
+		Parameters: parameters,
+	})
+	return err
+}
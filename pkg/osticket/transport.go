@@ -0,0 +1,79 @@
+package osticket
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Connection configures how the client reaches the server: through a
+// proxy, trusting extra CAs, or with a client certificate
+type Connection struct {
+	// Proxy is an http://, https:// or socks5:// URL; empty uses the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	Proxy string
+	// CAFile is a PEM bundle of CAs trusted in addition to the system ones
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and its key
+	CertFile string
+	KeyFile  string
+	// Insecure skips verification of the server certificate
+	Insecure bool
+}
+
+// transport builds the HTTP transport for a connection
+func (c Connection) transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.Proxy != "" {
+		proxy, err := ParseProxy(c.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tlsConfig
+	return t, nil
+}
+
+// ParseProxy checks a proxy URL
+func ParseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: use a URL like http://proxy:3128 or socks5://proxy:1080", raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	}
+	return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https or socks5", raw)
+}